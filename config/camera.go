@@ -1,28 +1,71 @@
 package config
 
 import (
+	"image"
 	"math"
 	"math/rand"
 
 	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+	"github.com/paulwrubel/photolum/enumeration/cameratype"
+	"github.com/paulwrubel/photolum/enumeration/stereolayout"
 )
 
 // A Camera holds information about the scene's camera
 // and facilitates the casting of Rays into the scene
+// the camera's type decides how points on the image map to directions out of the eye
 type Camera struct {
-	EyeLocation    geometry.Point
-	TargetLocation geometry.Point
-	UpVector       geometry.Vector
-	VerticalFOV    float64
-	AspectRatio    float64
-	Aperture       float64
-	FocusDistance  float64
+	CameraType         cameratype.CameraType
+	EyeLocation        geometry.Point
+	TargetLocation     geometry.Point
+	UpVector           geometry.Vector
+	VerticalFOV        float64 // for fisheyes, the angle across the image circle, which spans the image's height
+	AspectRatio        float64
+	Aperture           float64
+	FocusDistance      float64
+	OrthographicHeight float64 // height of the view of an orthographic camera, in world units
+
+	// a physical camera derives its field of view and aperture from its lens and sensor,
+	// taking the scene to be measured in metres, and scales the light it gathers by its exposure
+	IsPhysical   bool
+	FocalLength  float64 // in millimetres
+	SensorWidth  float64 // in millimetres
+	FNumber      float64
+	ShutterSpeed float64 // in seconds
+	ISO          float64
+
+	// the lens of a perspective or orthographic camera shapes its out-of-focus highlights
+	ApertureBlades   int             // number of straight blades forming the aperture, or zero for a round one
+	ApertureRotation float64         // rotation of the blades, in degrees
+	ApertureMask     texture.Texture // optional mask whose luminance is how much light each part of the lens lets through
+	CatsEye          float64         // how far, from zero to one, the lens is cut off towards the image's corners
+	TiltX            float64         // swing of the focal plane about the camera's horizontal axis, in degrees, with positive angles pushing its top away
+	TiltY            float64         // swing of the focal plane about the camera's vertical axis, in degrees, with positive angles pushing its right away
+	ShiftX           float64         // shift of the view across the image, as a fraction of its width
+	ShiftY           float64         // shift of the view up the image, as a fraction of its height
+
+	// rays are cast at moments spread evenly across the interval the shutter is open, blurring whatever moves during it
+	ShutterOpen  float64
+	ShutterClose float64
+
+	// a stereo camera renders each eye into its own half of the image, with the left eye on the left or top
+	// perspective eyes sit either side of the eye location, while equirectangular eyes circle it, omni-directionally
+	StereoLayout        stereolayout.StereoLayout // empty for a single view
+	InterocularDistance float64                   // distance between the eyes, in world units
+	ConvergenceDistance float64                   // distance at which the eyes' views meet, or zero for parallel eyes
+
+	views       []image.Rectangle
+	imageWidth  int
+	imageHeight int
 
 	lensRadius float64
+	exposure   float64
 	theta      float64
 	halfWidth  float64
 	halfHeight float64
 
+	focalNormal geometry.Vector
+
 	w geometry.Vector
 	u geometry.Vector
 	v geometry.Vector
@@ -36,11 +79,31 @@ type Camera struct {
 // It fills the unexported fields, such as derived vectors and measures
 func (c *Camera) Setup(p *Parameters) error {
 	c.UpVector = c.UpVector.Unit()
-	c.AspectRatio = float64(p.ImageWidth) / float64(p.ImageHeight)
+	c.imageWidth = p.ImageWidth
+	c.imageHeight = p.ImageHeight
+	c.views = stereoViews(c.StereoLayout, p.ImageWidth, p.ImageHeight)
+	// every eye sees the same view, shaped like its part of the image
+	c.AspectRatio = float64(c.views[0].Dx()) / float64(c.views[0].Dy())
+
+	c.exposure = 1.0
+	if c.IsPhysical {
+		// the sensor is fit to the image's width, and the lens is focal length over f-number across
+		sensorHeight := c.SensorWidth / c.AspectRatio
+		c.VerticalFOV = 2.0 * math.Atan(sensorHeight/(2.0*c.FocalLength)) * 180.0 / math.Pi
+		c.Aperture = c.FocalLength / 1000.0 / c.FNumber
+		// the exposure which maps the luminance saturating an ISO 100 sensor to one, with no headroom
+		// this is the usual photographic exposure value with a calibration constant of 1.2
+		c.exposure = c.ShutterSpeed * c.ISO / (1.2 * 100.0 * c.FNumber * c.FNumber)
+	}
 
 	c.lensRadius = c.Aperture / 2.0
 	c.theta = c.VerticalFOV * math.Pi / 180.0
-	c.halfHeight = math.Tan(c.theta / 2.0)
+	switch c.CameraType {
+	case cameratype.Equirectangular, cameratype.FisheyeEquidistant, cameratype.FisheyeEquisolid:
+		c.halfHeight = 1.0
+	default:
+		c.halfHeight = math.Tan(c.theta / 2.0)
+	}
 	c.halfWidth = c.AspectRatio * c.halfHeight
 
 	c.w = c.TargetLocation.To(c.EyeLocation).Unit()
@@ -55,19 +118,235 @@ func (c *Camera) Setup(p *Parameters) error {
 	c.horizonal = c.u.MultScalar(2.0 * c.halfWidth * c.FocusDistance)
 	c.verical = c.v.MultScalar(2.0 * c.halfHeight * c.FocusDistance)
 
+	// an orthographic view is measured in world units, rather than scaled out to the focus distance
+	if c.CameraType == cameratype.Orthographic {
+		c.horizonal = c.u.MultScalar(c.AspectRatio * c.OrthographicHeight)
+		c.verical = c.v.MultScalar(c.OrthographicHeight)
+	}
+
+	// an untilted focal plane faces the camera
+	c.focalNormal = c.w.Add(
+		c.v.MultScalar(math.Tan(c.TiltX * math.Pi / 180.0))).Add(
+		c.u.MultScalar(math.Tan(c.TiltY * math.Pi / 180.0))).Unit()
+
 	return nil
 }
 
-// GetRay returns a Ray from the eye location to a point on the view place u% across and v% up
-func (c *Camera) GetRay(u float64, v float64, rng *rand.Rand) geometry.Ray {
-	randomOnLens := geometry.RandomOnUnitDisk(rng).MultScalar(c.lensRadius)
-	offset := c.u.MultScalar(randomOnLens.X).Add(c.v.MultScalar(randomOnLens.Y))
+// Exposure returns the multiplier applied to the light reaching the camera before it's developed
+func (c *Camera) Exposure() float64 {
+	return c.exposure
+}
+
+// Views returns the parts of the image each eye's view is rendered into, from the left eye to the right,
+// in pixels with y increasing upwards
+// a camera without stereo has a single view covering the whole image
+func (c *Camera) Views() []image.Rectangle {
+	return c.views
+}
+
+// stereoViews splits an image of the given size into the views of a stereo layout
+func stereoViews(layout stereolayout.StereoLayout, width, height int) []image.Rectangle {
+	switch layout {
+	case stereolayout.SideBySide:
+		return []image.Rectangle{
+			image.Rect(0, 0, width/2, height),
+			image.Rect(width/2, 0, width, height),
+		}
+	case stereolayout.TopBottom:
+		return []image.Rectangle{
+			image.Rect(0, height/2, width, height),
+			image.Rect(0, 0, width, height/2),
+		}
+	default:
+		return []image.Rectangle{image.Rect(0, 0, width, height)}
+	}
+}
+
+// viewAt returns which eye sees the point on the image u% across and v% up, as -1 for the left eye,
+// 1 for the right, or 0 for a camera without stereo, along with how far across and up that eye's view the point is
+func (c *Camera) viewAt(u, v float64) (float64, float64, float64) {
+	if len(c.views) < 2 {
+		return 0.0, u, v
+	}
+	x := u * float64(c.imageWidth)
+	y := v * float64(c.imageHeight)
+	pixel := image.Point{
+		X: int(math.Min(math.Floor(x), float64(c.imageWidth-1))),
+		Y: int(math.Min(math.Floor(y), float64(c.imageHeight-1))),
+	}
+	eye, view := 1.0, c.views[1]
+	if pixel.In(c.views[0]) {
+		eye, view = -1.0, c.views[0]
+	}
+	return eye, (x - float64(view.Min.X)) / float64(view.Dx()), (y - float64(view.Min.Y)) / float64(view.Dy())
+}
+
+// GetRay returns a Ray from the eye location through the point on the image u% across and v% up,
+// cast at a random moment while the shutter is open
+// it returns false if there's no ray through that point, as outside the image circle of a fisheye
+func (c *Camera) GetRay(u float64, v float64, rng *rand.Rand) (geometry.Ray, bool) {
+	eye, u, v := c.viewAt(u, v)
+	ray, ok := c.castRay(u, v, eye, rng)
+	ray.Moment = c.ShutterOpen + rng.Float64()*(c.ShutterClose-c.ShutterOpen)
+	return ray, ok
+}
+
+// castRay returns a Ray through the point on an eye's view u% across and v% up, as GetRay does, but at no particular moment
+// eye is -1 for the left eye, 1 for the right, or 0 for a camera without stereo
+func (c *Camera) castRay(u float64, v float64, eye float64, rng *rand.Rand) (geometry.Ray, bool) {
+	switch c.CameraType {
+	case cameratype.Orthographic:
+		// every ray leaves the view plane parallel, from a point on the lens around where it crosses the plane
+		onPlane := c.EyeLocation.AddVector(
+			c.horizonal.MultScalar(u + c.ShiftX - 0.5)).AddVector(
+			c.verical.MultScalar(v + c.ShiftY - 0.5))
+		return c.focusedRay(onPlane, c.w.Negate(), u, v, rng)
+	case cameratype.Equirectangular:
+		// longitude runs across the whole image and latitude up it, both centered on the target
+		longitude := (u - 0.5) * 2.0 * math.Pi
+		latitude := (v - 0.5) * math.Pi
+		ray := c.panoramicRay(
+			math.Cos(latitude)*math.Sin(longitude),
+			math.Sin(latitude),
+			math.Cos(latitude)*math.Cos(longitude))
+		return c.omnidirectionalRay(ray, eye, longitude, latitude), true
+	case cameratype.Cylindrical:
+		// longitude runs across the whole image, and height up it as it would for a perspective camera
+		longitude := (u - 0.5) * 2.0 * math.Pi
+		return c.panoramicRay(
+			math.Sin(longitude),
+			(2.0*v-1.0)*c.halfHeight,
+			math.Cos(longitude)), true
+	case cameratype.FisheyeEquidistant, cameratype.FisheyeEquisolid:
+		// the image circle spans the image's height, with the angle from the target growing with distance from the center
+		x := (2.0*u - 1.0) * c.AspectRatio
+		y := 2.0*v - 1.0
+		r := math.Sqrt(x*x + y*y)
+		if r > 1.0 {
+			return geometry.Ray{}, false
+		}
+		var angle float64
+		if c.CameraType == cameratype.FisheyeEquidistant {
+			angle = r * c.theta / 2.0
+		} else {
+			angle = 2.0 * math.Asin(r*math.Sin(c.theta/4.0))
+		}
+		if r == 0.0 {
+			return c.panoramicRay(0.0, 0.0, 1.0), true
+		}
+		return c.panoramicRay(
+			math.Sin(angle)*x/r,
+			math.Sin(angle)*y/r,
+			math.Cos(angle)), true
+	default:
+		// each eye sits half the interocular distance to its side, with its view shifted back across
+		// so the views of both meet at the convergence distance
+		eyeShift := 0.0
+		if c.ConvergenceDistance > 0.0 {
+			eyeShift = -eye * c.InterocularDistance / 2.0 / (c.ConvergenceDistance * 2.0 * c.halfWidth)
+		}
+		direction := c.lowerLeftCorner.AddVector(
+			c.horizonal.MultScalar(u + c.ShiftX + eyeShift)).AddVector(
+			c.verical.MultScalar(v + c.ShiftY)).From(
+			c.EyeLocation).Unit()
+		origin := c.EyeLocation.AddVector(c.u.MultScalar(eye * c.InterocularDistance / 2.0))
+		return c.focusedRay(origin, direction, u, v, rng)
+	}
+}
+
+// focusedRay returns a Ray from a point on the lens around origin, towards where the ray from origin
+// in direction meets the focal plane, for the point on the image u% across and v% up
+// it returns false if the lens lets no light through at the point it chose
+func (c *Camera) focusedRay(origin geometry.Point, direction geometry.Vector, u, v float64, rng *rand.Rand) (geometry.Ray, bool) {
+	if c.lensRadius == 0.0 {
+		return geometry.Ray{Origin: origin, Direction: direction}, true
+	}
+	onLens, ok := c.sampleLens(u, v, rng)
+	if !ok {
+		return geometry.Ray{}, false
+	}
+	// a focal plane tilted far enough may never be reached, leaving the ray as sharp as a pinhole's
+	toPlane := c.EyeLocation.SubVector(c.w.MultScalar(c.FocusDistance)).From(origin).Dot(c.focalNormal)
+	t := toPlane / direction.Dot(c.focalNormal)
+	if t <= 0.0 || math.IsInf(t, 0) || math.IsNaN(t) {
+		return geometry.Ray{Origin: origin, Direction: direction}, true
+	}
+	focus := origin.AddVector(direction.MultScalar(t))
+	lensPoint := origin.AddVector(
+		c.u.MultScalar(onLens.X * c.lensRadius)).AddVector(
+		c.v.MultScalar(onLens.Y * c.lensRadius))
+	return geometry.Ray{
+		Origin:    lensPoint,
+		Direction: focus.From(lensPoint).Unit(),
+	}, true
+}
+
+// sampleLens returns a random point within the aperture, on a lens of unit radius,
+// for the point on the image u% across and v% up
+// it returns false if the point chosen is cut off or masked, so rays are lost in proportion to the light the lens blocks
+func (c *Camera) sampleLens(u, v float64, rng *rand.Rand) (geometry.Vector, bool) {
+	var onLens geometry.Vector
+	if c.ApertureBlades >= 3 {
+		// choose one of the triangles fanning out from the center to the blades' edges, then a point within it
+		edge := 2.0 * math.Pi / float64(c.ApertureBlades)
+		start := float64(rng.Intn(c.ApertureBlades))*edge + c.ApertureRotation*math.Pi/180.0
+		a, b := rng.Float64(), rng.Float64()
+		if a+b > 1.0 {
+			a, b = 1.0-a, 1.0-b
+		}
+		onLens = geometry.Vector{
+			X: a*math.Cos(start) + b*math.Cos(start+edge),
+			Y: a*math.Sin(start) + b*math.Sin(start+edge),
+		}
+	} else {
+		onLens = geometry.RandomOnUnitDisk(rng)
+	}
+	if c.CatsEye > 0.0 {
+		// away from the image's center, the lens barrel hides the side of the lens facing outwards,
+		// leaving only the part within a second disk pulled towards the center
+		x := (2.0*u - 1.0) * c.AspectRatio
+		y := 2.0*v - 1.0
+		corner := math.Sqrt(c.AspectRatio*c.AspectRatio + 1.0)
+		cutoff := geometry.Vector{X: -x / corner * c.CatsEye, Y: -y / corner * c.CatsEye}
+		if onLens.Sub(cutoff).Magnitude() > 1.0 {
+			return geometry.Vector{}, false
+		}
+	}
+	if c.ApertureMask != nil {
+		transmission := c.ApertureMask.Value((onLens.X+1.0)/2.0, (onLens.Y+1.0)/2.0, geometry.Point{X: onLens.X, Y: onLens.Y}).Luminance()
+		if rng.Float64() >= transmission {
+			return geometry.Vector{}, false
+		}
+	}
+	return onLens, true
+}
+
+// panoramicRay returns a Ray from the eye location in the direction given by its components
+// to the right, up, and forward toward the target
+// panoramic cameras have no lens, so every ray leaves from the eye location itself
+func (c *Camera) panoramicRay(right, up, forward float64) geometry.Ray {
 	return geometry.Ray{
-		Origin: c.EyeLocation.AddVector(offset),
-		Direction: c.lowerLeftCorner.AddVector(
-			c.horizonal.MultScalar(u)).AddVector(
-			c.verical.MultScalar(v)).From(
-			c.EyeLocation).Sub(
-			offset).Unit(),
+		Origin: c.EyeLocation,
+		Direction: c.u.MultScalar(right).Add(
+			c.v.MultScalar(up)).Sub(
+			c.w.MultScalar(forward)).Unit(),
+	}
+}
+
+// omnidirectionalRay moves a ray cast from the eye location at a longitude and latitude out to an eye,
+// where eye is -1 for the left eye, 1 for the right, or 0 for a camera without stereo
+// the eyes sit on opposite sides of a circle around the eye location, across from wherever they're looking,
+// and the circle shrinks towards the poles so looking straight up or down doesn't twist the eyes round
+func (c *Camera) omnidirectionalRay(ray geometry.Ray, eye, longitude, latitude float64) geometry.Ray {
+	if eye == 0.0 {
+		return ray
+	}
+	radius := eye * c.InterocularDistance / 2.0 * math.Cos(latitude)
+	offset := c.u.MultScalar(radius * math.Cos(longitude)).Add(c.w.MultScalar(radius * math.Sin(longitude)))
+	ray.Origin = ray.Origin.AddVector(offset)
+	// converging eyes look towards the point at the convergence distance from the eye location
+	if c.ConvergenceDistance > 0.0 {
+		ray.Direction = ray.Direction.MultScalar(c.ConvergenceDistance).Sub(offset).Unit()
 	}
+	return ray
 }
@@ -0,0 +1,244 @@
+package config
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/enumeration/cameratype"
+	"github.com/paulwrubel/photolum/enumeration/stereolayout"
+)
+
+// testCamera returns a camera of the given type at the origin, looking down -Z for a 2:1 image
+func testCamera(cameraType cameratype.CameraType, verticalFOV float64) *Camera {
+	c := &Camera{
+		CameraType:         cameraType,
+		EyeLocation:        geometry.Point{},
+		TargetLocation:     geometry.Point{Z: -1.0},
+		UpVector:           geometry.Vector{Y: 1.0},
+		VerticalFOV:        verticalFOV,
+		FocusDistance:      1.0,
+		OrthographicHeight: 2.0,
+	}
+	c.Setup(&Parameters{ImageWidth: 200, ImageHeight: 100})
+	return c
+}
+
+func closeToVector(a, b geometry.Vector) bool {
+	return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9 && math.Abs(a.Z-b.Z) < 1e-9
+}
+
+func TestCameraGetRay(t *testing.T) {
+	diagonal := math.Sqrt(0.5)
+	tests := []struct {
+		name        string
+		cameraType  cameratype.CameraType
+		verticalFOV float64
+		u, v        float64
+		direction   geometry.Vector
+	}{
+		{"perspective center", cameratype.Perspective, 90.0, 0.5, 0.5, geometry.Vector{Z: -1.0}},
+		{"perspective top", cameratype.Perspective, 90.0, 0.5, 1.0, geometry.Vector{Y: diagonal, Z: -diagonal}},
+		{"orthographic corner", cameratype.Orthographic, 0.0, 0.0, 0.0, geometry.Vector{Z: -1.0}},
+		{"equirectangular right", cameratype.Equirectangular, 0.0, 0.75, 0.5, geometry.Vector{X: 1.0}},
+		{"equirectangular behind", cameratype.Equirectangular, 0.0, 1.0, 0.5, geometry.Vector{Z: 1.0}},
+		{"equirectangular zenith", cameratype.Equirectangular, 0.0, 0.3, 1.0, geometry.Vector{Y: 1.0}},
+		{"equidistant top", cameratype.FisheyeEquidistant, 180.0, 0.5, 1.0, geometry.Vector{Y: 1.0}},
+		{"equidistant halfway", cameratype.FisheyeEquidistant, 180.0, 0.5, 0.75, geometry.Vector{Y: diagonal, Z: -diagonal}},
+		{"equisolid top", cameratype.FisheyeEquisolid, 180.0, 0.5, 1.0, geometry.Vector{Y: 1.0}},
+		{"cylindrical left", cameratype.Cylindrical, 90.0, 0.25, 0.5, geometry.Vector{X: -1.0}},
+		{"cylindrical top", cameratype.Cylindrical, 90.0, 0.5, 1.0, geometry.Vector{Y: diagonal, Z: -diagonal}},
+	}
+	rng := rand.New(rand.NewSource(0))
+	for _, test := range tests {
+		ray, ok := testCamera(test.cameraType, test.verticalFOV).GetRay(test.u, test.v, rng)
+		if !ok {
+			t.Errorf("%s: expected a ray\n", test.name)
+			continue
+		}
+		if !closeToVector(ray.Direction, test.direction) {
+			t.Errorf("%s: expected direction %v but got %v\n", test.name, test.direction, ray.Direction)
+		}
+	}
+}
+
+func TestCameraOrthographicOrigin(t *testing.T) {
+	// the view is two units high, and twice as wide
+	ray, _ := testCamera(cameratype.Orthographic, 0.0).GetRay(0.0, 0.0, rand.New(rand.NewSource(0)))
+	expected := geometry.Point{X: -2.0, Y: -1.0}
+	if math.Abs(ray.Origin.X-expected.X) > 1e-9 || math.Abs(ray.Origin.Y-expected.Y) > 1e-9 || math.Abs(ray.Origin.Z) > 1e-9 {
+		t.Errorf("Expected origin %v but got %v\n", expected, ray.Origin)
+	}
+}
+
+func TestCameraFisheyeOutsideImageCircle(t *testing.T) {
+	for _, cameraType := range []cameratype.CameraType{cameratype.FisheyeEquidistant, cameratype.FisheyeEquisolid} {
+		c := testCamera(cameraType, 180.0)
+		if _, ok := c.GetRay(0.0, 0.0, rand.New(rand.NewSource(0))); ok {
+			t.Errorf("Expected no ray through the corner of a %s image\n", cameraType)
+		}
+	}
+}
+
+func TestCameraPhysical(t *testing.T) {
+	// sunny 16: at f/16 and ISO 100, a shutter of 1/100s should just saturate on a sunlit scene
+	c := &Camera{
+		EyeLocation:    geometry.Point{},
+		TargetLocation: geometry.Point{Z: -1.0},
+		UpVector:       geometry.Vector{Y: 1.0},
+		FocusDistance:  1.0,
+		IsPhysical:     true,
+		FocalLength:    50.0,
+		SensorWidth:    36.0,
+		FNumber:        16.0,
+		ShutterSpeed:   0.01,
+		ISO:            100.0,
+	}
+	c.Setup(&Parameters{ImageWidth: 300, ImageHeight: 200})
+	// a 36x24mm sensor behind a 50mm lens
+	expectedFOV := 2.0 * math.Atan(12.0/50.0) * 180.0 / math.Pi
+	if math.Abs(c.VerticalFOV-expectedFOV) > 1e-9 {
+		t.Errorf("Expected a vertical field of view of %f but got %f\n", expectedFOV, c.VerticalFOV)
+	}
+	if math.Abs(c.Aperture-0.003125) > 1e-12 {
+		t.Errorf("Expected an aperture of 0.003125m but got %f\n", c.Aperture)
+	}
+	if luminance := 1.0 / c.Exposure(); luminance < 25000.0 || luminance > 35000.0 {
+		t.Errorf("Expected a saturating luminance near daylight but got %f\n", luminance)
+	}
+}
+
+func TestCameraApertureBlades(t *testing.T) {
+	c := testCamera(cameratype.Perspective, 40.0)
+	c.Aperture = 2.0
+	c.ApertureBlades = 4
+	c.Setup(&Parameters{ImageWidth: 200, ImageHeight: 100})
+	rng := rand.New(rand.NewSource(0))
+	// an unrotated square aperture has its corners on the axes, so it's a diamond
+	for i := 0; i < 1000; i++ {
+		ray, _ := c.GetRay(0.5, 0.5, rng)
+		if math.Abs(ray.Origin.X)+math.Abs(ray.Origin.Y) > 1.0+1e-9 {
+			t.Fatalf("Expected ray origins within the aperture but got %v\n", ray.Origin)
+		}
+	}
+}
+
+func TestCameraCatsEye(t *testing.T) {
+	c := testCamera(cameratype.Perspective, 40.0)
+	c.Aperture = 2.0
+	c.CatsEye = 1.0
+	c.Setup(&Parameters{ImageWidth: 200, ImageHeight: 100})
+	rng := rand.New(rand.NewSource(0))
+	lost := func(u, v float64) int {
+		count := 0
+		for i := 0; i < 1000; i++ {
+			if _, ok := c.GetRay(u, v, rng); !ok {
+				count++
+			}
+		}
+		return count
+	}
+	if center := lost(0.5, 0.5); center != 0 {
+		t.Errorf("Expected no rays lost at the center but lost %d\n", center)
+	}
+	// two unit disks a radius apart overlap across 39% of either
+	if corner := lost(1.0, 1.0); corner < 550 || corner > 670 {
+		t.Errorf("Expected around 610 rays lost at the corner but lost %d\n", corner)
+	}
+}
+
+func TestCameraTilt(t *testing.T) {
+	// tilted 45 degrees, the focal plane passes through the focus point and recedes upwards
+	c := testCamera(cameratype.Perspective, 90.0)
+	c.Aperture = 0.5
+	c.FocusDistance = 2.0
+	c.TiltX = 45.0
+	c.Setup(&Parameters{ImageWidth: 200, ImageHeight: 100})
+	rng := rand.New(rand.NewSource(0))
+	// the plane is y = -z - 2, so looking halfway up the view, where y = -z / 2, it's twice as far away
+	for _, expected := range []geometry.Point{{Z: -2.0}, {Y: 2.0, Z: -4.0}, {Y: -2.0 / 3.0, Z: -4.0 / 3.0}} {
+		v := 0.5 + 0.5*expected.Y/-expected.Z
+		for i := 0; i < 10; i++ {
+			ray, _ := c.GetRay(0.5, v, rng)
+			closest := ray.ClosestPoint(expected)
+			if closest.To(expected).Magnitude() > 1e-9 {
+				t.Fatalf("Expected rays at v = %f to pass through %v but got %v\n", v, expected, ray)
+			}
+		}
+	}
+}
+
+func TestCameraShutter(t *testing.T) {
+	c := testCamera(cameratype.Perspective, 40.0)
+	c.ShutterOpen = 1.0
+	c.ShutterClose = 1.5
+	rng := rand.New(rand.NewSource(0))
+	earliest, latest := math.Inf(1), math.Inf(-1)
+	for i := 0; i < 1000; i++ {
+		ray, _ := c.GetRay(0.5, 0.5, rng)
+		earliest = math.Min(earliest, ray.Moment)
+		latest = math.Max(latest, ray.Moment)
+	}
+	if earliest < 1.0 || latest > 1.5 || latest-earliest < 0.45 {
+		t.Errorf("Expected moments spread across [1, 1.5] but got [%f, %f]\n", earliest, latest)
+	}
+}
+
+func TestCameraStereoPerspective(t *testing.T) {
+	c := testCamera(cameratype.Perspective, 90.0)
+	c.StereoLayout = stereolayout.SideBySide
+	c.InterocularDistance = 0.5
+	c.ConvergenceDistance = 4.0
+	c.Setup(&Parameters{ImageWidth: 200, ImageHeight: 100})
+	if c.AspectRatio != 1.0 {
+		t.Errorf("Expected each eye's view to be square but got an aspect ratio of %f\n", c.AspectRatio)
+	}
+	rng := rand.New(rand.NewSource(0))
+	// the center of each eye's view looks from that eye towards the point both converge on
+	converged := geometry.Point{Z: -4.0}
+	for _, eye := range []struct {
+		u float64
+		x float64
+	}{{0.25, -0.25}, {0.75, 0.25}} {
+		ray, _ := c.GetRay(eye.u, 0.5, rng)
+		if math.Abs(ray.Origin.X-eye.x) > 1e-9 {
+			t.Errorf("Expected the eye at u = %f to be at x = %f but got %v\n", eye.u, eye.x, ray.Origin)
+		}
+		if ray.ClosestPoint(converged).To(converged).Magnitude() > 1e-9 {
+			t.Errorf("Expected the eye at u = %f to look towards %v but got %v\n", eye.u, converged, ray)
+		}
+	}
+}
+
+func TestCameraStereoOmnidirectional(t *testing.T) {
+	c := testCamera(cameratype.Equirectangular, 0.0)
+	c.StereoLayout = stereolayout.TopBottom
+	c.InterocularDistance = 0.5
+	c.Setup(&Parameters{ImageWidth: 200, ImageHeight: 201})
+	if views := c.Views(); views[0].Min.Y != 100 || views[1].Max.Y != 100 {
+		t.Errorf("Expected the left eye's view above the right's but got %v\n", views)
+	}
+	c.Setup(&Parameters{ImageWidth: 200, ImageHeight: 200})
+	rng := rand.New(rand.NewSource(0))
+	tests := []struct {
+		name      string
+		u, v      float64
+		origin    geometry.Point
+		direction geometry.Vector
+	}{
+		{"left forward", 0.5, 0.75, geometry.Point{X: -0.25}, geometry.Vector{Z: -1.0}},
+		{"right forward", 0.5, 0.25, geometry.Point{X: 0.25}, geometry.Vector{Z: -1.0}},
+		{"left looking right", 0.75, 0.75, geometry.Point{Z: -0.25}, geometry.Vector{X: 1.0}},
+		{"right looking behind", 1.0, 0.25, geometry.Point{X: -0.25}, geometry.Vector{Z: 1.0}},
+	}
+	for _, test := range tests {
+		ray, _ := c.GetRay(test.u, test.v, rng)
+		if !closeToVector(geometry.Point{}.To(ray.Origin), geometry.Point{}.To(test.origin)) {
+			t.Errorf("%s: expected origin %v but got %v\n", test.name, test.origin, ray.Origin)
+		}
+		if !closeToVector(ray.Direction, test.direction) {
+			t.Errorf("%s: expected direction %v but got %v\n", test.name, test.direction, ray.Direction)
+		}
+	}
+}
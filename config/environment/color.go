@@ -0,0 +1,16 @@
+package environment
+
+import (
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// Color is an environment of a single, uniform color
+type Color struct {
+	Color shading.Color `json:"color"`
+}
+
+// Radiance returns the environment's color, regardless of direction
+func (c *Color) Radiance(direction geometry.Vector) shading.Color {
+	return c.Color
+}
@@ -0,0 +1,49 @@
+package environment
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// Environment describes the light arriving from infinitely far away,
+// returned for every ray which escapes the scene
+type Environment interface {
+	Radiance(direction geometry.Vector) shading.Color
+}
+
+// Sampler is implemented by environments which can be importance sampled,
+// which lets the tracer send rays directly towards their brightest regions
+type Sampler interface {
+	// Sample returns a direction towards the environment and the solid angle density it was chosen with
+	Sample(rng *rand.Rand) (geometry.Vector, float64)
+	// PDF returns the solid angle density with which Sample would choose the given direction
+	PDF(direction geometry.Vector) float64
+}
+
+// directionToUV maps a direction onto equirectangular coordinates, rotated about the vertical axis
+// u wraps around the horizon, with the forward (negative Z) direction at its center,
+// and v runs from straight up (0) to straight down (1)
+func directionToUV(direction geometry.Vector, rotation float64) (float64, float64) {
+	d := direction.Unit()
+	phi := math.Atan2(d.X, -d.Z)
+	theta := math.Acos(math.Max(-1.0, math.Min(1.0, d.Y)))
+	u := (phi+math.Pi)/(2.0*math.Pi) - rotation/(2.0*math.Pi)
+	u -= math.Floor(u)
+	return u, theta / math.Pi
+}
+
+// uvToDirection is the inverse of directionToUV
+// it also returns the sine of the polar angle, needed to convert between densities
+func uvToDirection(u, v, rotation float64) (geometry.Vector, float64) {
+	phi := 2.0*math.Pi*u + rotation - math.Pi
+	theta := math.Pi * v
+	sinTheta := math.Sin(theta)
+	return geometry.Vector{
+		X: sinTheta * math.Sin(phi),
+		Y: math.Cos(theta),
+		Z: -sinTheta * math.Cos(phi),
+	}, sinTheta
+}
@@ -0,0 +1,91 @@
+package environment
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/sampling"
+	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+// Image is an environment backed by an equirectangular (latitude-longitude) image texture
+type Image struct {
+	Texture   *texture.Image `json:"-"`
+	Rotation  float64        `json:"rotation"`  // rotation about the vertical axis, in degrees
+	Intensity float64        `json:"intensity"` // amount to scale the image's radiance by
+
+	width        int
+	height       int
+	rotation     float64
+	texels       []shading.Color
+	distribution *sampling.Distribution2D
+}
+
+// Setup decodes the texture's texels and builds the luminance distribution used for importance sampling
+func (i *Image) Setup() (*Image, error) {
+	if i.Texture == nil {
+		return nil, fmt.Errorf("environment image texture is nil")
+	}
+	if i.Intensity < 0.0 {
+		return nil, fmt.Errorf("environment intensity is negative")
+	}
+	i.width = i.Texture.Width()
+	i.height = i.Texture.Height()
+	i.rotation = i.Rotation * math.Pi / 180.0
+
+	i.texels = make([]shading.Color, i.width*i.height)
+	weights := make([]float64, i.width*i.height)
+	for y := 0; y < i.height; y++ {
+		// rows near the poles cover less solid angle, so they're chosen proportionally less often
+		sinTheta := math.Sin(math.Pi * (float64(y) + 0.5) / float64(i.height))
+		for x := 0; x < i.width; x++ {
+			c := i.Texture.Texel(x, y).MultScalar(i.Intensity)
+			i.texels[y*i.width+x] = c
+			weights[y*i.width+x] = c.Luminance() * sinTheta
+		}
+	}
+	i.distribution = sampling.NewDistribution2D(weights, i.width, i.height)
+	return i, nil
+}
+
+// Radiance returns the color of the image in the given direction
+func (i *Image) Radiance(direction geometry.Vector) shading.Color {
+	u, v := directionToUV(direction, i.rotation)
+	return i.texels[i.texelIndex(u, v)]
+}
+
+// Sample chooses a direction proportionally to the image's luminance
+func (i *Image) Sample(rng *rand.Rand) (geometry.Vector, float64) {
+	u, v, pdf := i.distribution.Sample(rng.Float64(), rng.Float64())
+	direction, sinTheta := uvToDirection(u, v, i.rotation)
+	if pdf == 0.0 || sinTheta == 0.0 {
+		return direction, 0.0
+	}
+	// convert from a density over the image to a density over the sphere
+	return direction, pdf / (2.0 * math.Pi * math.Pi * sinTheta)
+}
+
+// PDF returns the solid angle density with which Sample chooses the given direction
+func (i *Image) PDF(direction geometry.Vector) float64 {
+	u, v := directionToUV(direction, i.rotation)
+	sinTheta := math.Sin(math.Pi * v)
+	if sinTheta == 0.0 {
+		return 0.0
+	}
+	return i.distribution.PDF(u, v) / (2.0 * math.Pi * math.Pi * sinTheta)
+}
+
+func (i *Image) texelIndex(u, v float64) int {
+	x := int(u * float64(i.width))
+	y := int(v * float64(i.height))
+	if x > i.width-1 {
+		x = i.width - 1
+	}
+	if y > i.height-1 {
+		y = i.height - 1
+	}
+	return y*i.width + x
+}
@@ -0,0 +1,63 @@
+package environment
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+func TestDirectionRoundTrip(t *testing.T) {
+	directions := []geometry.Vector{
+		{X: 0.0, Y: 0.0, Z: -1.0},
+		{X: 1.0, Y: 0.5, Z: 0.0},
+		{X: -0.3, Y: -0.8, Z: 0.2},
+	}
+	for _, rotation := range []float64{0.0, 1.0, -2.5} {
+		for _, d := range directions {
+			u, v := directionToUV(d, rotation)
+			back, _ := uvToDirection(u, v, rotation)
+			if back.Sub(d.Unit()).Magnitude() > 1e-9 {
+				t.Errorf("Expected %v but got %v (rotation %f)\n", d.Unit(), back, rotation)
+			}
+		}
+	}
+}
+
+func TestImageSamplesBrightTexel(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 4))
+	img.Set(5, 1, color.RGBA{255, 255, 255, 255})
+	var imageData bytes.Buffer
+	if err := png.Encode(&imageData, img); err != nil {
+		t.Fatal(err)
+	}
+	imageTexture := &texture.Image{ImageData: imageData.Bytes(), Gamma: 1.0, Magnitude: 1.0}
+	if err := imageTexture.Load(); err != nil {
+		t.Fatal(err)
+	}
+	env, err := (&Image{
+		Texture:   imageTexture,
+		Rotation:  30.0,
+		Intensity: 2.0,
+	}).Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rng := rand.New(rand.NewSource(0))
+	for s := 0; s < 16; s++ {
+		direction, pdf := env.Sample(rng)
+		if env.Radiance(direction).Luminance() == 0.0 {
+			t.Errorf("Expected sampled direction %v to point at the bright texel\n", direction)
+		}
+		if p := env.PDF(direction); math.Abs(p-pdf) > 1e-9*pdf {
+			t.Errorf("Expected PDF %f to match the sampled density %f\n", p, pdf)
+		}
+	}
+}
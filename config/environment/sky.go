@@ -0,0 +1,228 @@
+package environment
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/sampling"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// resolution of the grid used to importance sample the sky
+const skyGridWidth = 128
+const skyGridHeight = 64
+
+// Sky is an analytic daylight sky following Preetham et al., "A Practical Analytic Model for Daylight" (1999),
+// together with the disk of the sun it is lit by. Below the horizon, it shows a diffuse ground lit by both
+type Sky struct {
+	SunDirection       geometry.Vector `json:"sun_direction"`        // direction towards the sun, which must be above the horizon
+	Turbidity          float64         `json:"turbidity"`            // haziness of the atmosphere, from 1.7 (very clear) to 10 (hazy)
+	GroundAlbedo       shading.Color   `json:"ground_albedo"`        // reflectance of the ground below the horizon
+	SkyIntensity       float64         `json:"sky_intensity"`        // amount to scale the sky's radiance (in kcd/m^2) by
+	SunIntensity       float64         `json:"sun_intensity"`        // irradiance of the sun outside the atmosphere, or zero to disable the sun
+	SunAngularDiameter float64         `json:"sun_angular_diameter"` // apparent size of the sun, in degrees
+
+	sunTheta       float64
+	zenith         [3]float64 // zenith values of Y, x, and y
+	perez          [3][5]float64
+	sunRadiance    shading.Color
+	sunCosMax      float64
+	groundRadiance shading.Color
+
+	distribution      *sampling.Distribution2D
+	sunSampleFraction float64
+	sunU, sunV, sunW  geometry.Vector
+	sunConeSolidAngle float64
+}
+
+// Setup validates the sky and precomputes the model's coefficients, the ground's brightness,
+// and the distribution used for importance sampling
+func (s *Sky) Setup() (*Sky, error) {
+	if s.SunDirection.Magnitude() == 0.0 {
+		return nil, fmt.Errorf("sky sun direction is zero")
+	}
+	s.SunDirection = s.SunDirection.Unit()
+	if s.SunDirection.Y <= 0.0 {
+		return nil, fmt.Errorf("sky sun direction is not above the horizon")
+	}
+	if s.Turbidity < 1.7 || s.Turbidity > 10.0 {
+		return nil, fmt.Errorf("sky turbidity is outside of the range [1.7, 10]")
+	}
+	if s.SkyIntensity < 0.0 || s.SunIntensity < 0.0 {
+		return nil, fmt.Errorf("sky intensity is negative")
+	}
+	if s.SunAngularDiameter <= 0.0 || s.SunAngularDiameter >= 180.0 {
+		return nil, fmt.Errorf("sky sun angular diameter is outside of the range (0, 180)")
+	}
+
+	s.sunTheta = math.Acos(s.SunDirection.Y)
+	s.setupPreetham()
+	s.setupSun()
+
+	// tabulate the sky (without the sun) over the sphere, both to sample it
+	// and to find out how brightly it lights the ground
+	weights := make([]float64, skyGridWidth*skyGridHeight)
+	skyIrradiance := shading.ColorBlack
+	skyPower := 0.0
+	for y := 0; y < skyGridHeight/2; y++ {
+		for x := 0; x < skyGridWidth; x++ {
+			direction, sinTheta := uvToDirection((float64(x)+0.5)/skyGridWidth, (float64(y)+0.5)/skyGridHeight, 0.0)
+			solidAngle := (2.0 * math.Pi / skyGridWidth) * (math.Pi / skyGridHeight) * sinTheta
+			radiance := s.skyRadiance(direction)
+			skyIrradiance = skyIrradiance.Add(radiance.MultScalar(direction.Y * solidAngle))
+			skyPower += radiance.Luminance() * solidAngle
+			weights[y*skyGridWidth+x] = radiance.Luminance() * sinTheta
+		}
+	}
+	sunIrradiance := s.sunRadiance.MultScalar(s.sunConeSolidAngle * s.SunDirection.Y)
+	s.groundRadiance = s.GroundAlbedo.MultColor(skyIrradiance.Add(sunIrradiance)).DivScalar(math.Pi)
+	for y := skyGridHeight / 2; y < skyGridHeight; y++ {
+		_, sinTheta := uvToDirection(0.0, (float64(y)+0.5)/skyGridHeight, 0.0)
+		solidAngle := (2.0 * math.Pi / skyGridWidth) * (math.Pi / skyGridHeight) * sinTheta
+		for x := 0; x < skyGridWidth; x++ {
+			skyPower += s.groundRadiance.Luminance() * solidAngle
+			weights[y*skyGridWidth+x] = s.groundRadiance.Luminance() * sinTheta
+		}
+	}
+	s.distribution = sampling.NewDistribution2D(weights, skyGridWidth, skyGridHeight)
+
+	// the sun is sampled separately, as it's far too small to show up in the grid
+	sunPower := s.sunRadiance.Luminance() * s.sunConeSolidAngle
+	if sunPower+skyPower > 0.0 {
+		s.sunSampleFraction = sunPower / (sunPower + skyPower)
+	}
+	return s, nil
+}
+
+// Radiance returns the color of the sky, sun, or ground in the given direction
+func (s *Sky) Radiance(direction geometry.Vector) shading.Color {
+	d := direction.Unit()
+	if d.Y < 0.0 {
+		return s.groundRadiance
+	}
+	radiance := s.skyRadiance(d)
+	if d.Dot(s.SunDirection) >= s.sunCosMax {
+		radiance = radiance.Add(s.sunRadiance)
+	}
+	return radiance
+}
+
+// Sample chooses a direction either towards the sun or proportionally to the sky's brightness
+func (s *Sky) Sample(rng *rand.Rand) (geometry.Vector, float64) {
+	var direction geometry.Vector
+	if rng.Float64() < s.sunSampleFraction {
+		// uniformly within the cone subtended by the sun
+		cosTheta := 1.0 - rng.Float64()*(1.0-s.sunCosMax)
+		sinTheta := math.Sqrt(math.Max(0.0, 1.0-cosTheta*cosTheta))
+		phi := 2.0 * math.Pi * rng.Float64()
+		direction = s.sunU.MultScalar(sinTheta * math.Cos(phi)).Add(
+			s.sunV.MultScalar(sinTheta * math.Sin(phi))).Add(
+			s.sunW.MultScalar(cosTheta))
+	} else {
+		u, v, _ := s.distribution.Sample(rng.Float64(), rng.Float64())
+		direction, _ = uvToDirection(u, v, 0.0)
+	}
+	return direction, s.PDF(direction)
+}
+
+// PDF returns the solid angle density with which Sample chooses the given direction
+func (s *Sky) PDF(direction geometry.Vector) float64 {
+	d := direction.Unit()
+	pdf := 0.0
+	if d.Dot(s.SunDirection) >= s.sunCosMax {
+		pdf += s.sunSampleFraction / s.sunConeSolidAngle
+	}
+	u, v := directionToUV(d, 0.0)
+	if sinTheta := math.Sin(math.Pi * v); sinTheta > 0.0 {
+		pdf += (1.0 - s.sunSampleFraction) * s.distribution.PDF(u, v) / (2.0 * math.Pi * math.Pi * sinTheta)
+	}
+	return pdf
+}
+
+// setupPreetham computes the zenith values and the Perez distribution coefficients for the turbidity and sun position
+func (s *Sky) setupPreetham() {
+	t := s.Turbidity
+	theta := s.sunTheta
+	theta2 := theta * theta
+	theta3 := theta2 * theta
+
+	chi := (4.0/9.0 - t/120.0) * (math.Pi - 2.0*theta)
+	s.zenith[0] = (4.0453*t-4.9710)*math.Tan(chi) - 0.2155*t + 2.4192
+	s.zenith[1] = t*t*(0.00166*theta3-0.00375*theta2+0.00209*theta) +
+		t*(-0.02903*theta3+0.06377*theta2-0.03202*theta+0.00394) +
+		(0.11693*theta3 - 0.21196*theta2 + 0.06052*theta + 0.25886)
+	s.zenith[2] = t*t*(0.00275*theta3-0.00610*theta2+0.00317*theta) +
+		t*(-0.04214*theta3+0.08970*theta2-0.04153*theta+0.00516) +
+		(0.15346*theta3 - 0.26756*theta2 + 0.06670*theta + 0.26688)
+
+	s.perez = [3][5]float64{
+		{0.1787*t - 1.4630, -0.3554*t + 0.4275, -0.0227*t + 5.3251, 0.1206*t - 2.5771, -0.0670*t + 0.3703},
+		{-0.0193*t - 0.2592, -0.0665*t + 0.0008, -0.0004*t + 0.2125, -0.0641*t - 0.8989, -0.0033*t + 0.0452},
+		{-0.0167*t - 0.2608, -0.0950*t + 0.0092, -0.0079*t + 0.2102, -0.0441*t - 1.6537, -0.0109*t + 0.0529},
+	}
+}
+
+// setupSun computes the sun's radiance after its light is attenuated on the way through the atmosphere
+func (s *Sky) setupSun() {
+	s.sunCosMax = math.Cos(s.SunAngularDiameter / 2.0 * math.Pi / 180.0)
+	s.sunConeSolidAngle = 2.0 * math.Pi * (1.0 - s.sunCosMax)
+
+	s.sunW = s.SunDirection
+	if math.Abs(s.sunW.X) > 0.9 {
+		s.sunU = geometry.VectorUp.Cross(s.sunW).Unit()
+	} else {
+		s.sunU = geometry.VectorRight.Cross(s.sunW).Unit()
+	}
+	s.sunV = s.sunW.Cross(s.sunU)
+
+	// relative optical air mass (Kasten and Young)
+	thetaDegrees := s.sunTheta * 180.0 / math.Pi
+	airMass := 1.0 / (math.Cos(s.sunTheta) + 0.50572*math.Pow(96.07995-thetaDegrees, -1.6364))
+	// Rayleigh and aerosol (Angstrom) optical depths, at representative wavelengths for red, green and blue
+	beta := 0.04608*s.Turbidity - 0.04586
+	transmittance := func(lambda float64) float64 {
+		rayleigh := 0.008735 * math.Pow(lambda, -4.08)
+		aerosol := beta * math.Pow(lambda, -1.3)
+		return math.Exp(-airMass * (rayleigh + aerosol))
+	}
+	s.sunRadiance = shading.Color{
+		Red:   transmittance(0.680),
+		Green: transmittance(0.550),
+		Blue:  transmittance(0.440),
+	}.MultScalar(s.SunIntensity / s.sunConeSolidAngle)
+}
+
+// skyRadiance returns the radiance of the sky, without the sun, in a direction above the horizon
+func (s *Sky) skyRadiance(d geometry.Vector) shading.Color {
+	cosTheta := math.Max(d.Y, 1e-3)
+	cosGamma := math.Max(-1.0, math.Min(1.0, d.Dot(s.SunDirection)))
+	gamma := math.Acos(cosGamma)
+
+	var values [3]float64
+	for i := range values {
+		values[i] = s.zenith[i] * perez(s.perez[i], cosTheta, gamma, cosGamma) /
+			perez(s.perez[i], 1.0, s.sunTheta, math.Cos(s.sunTheta))
+	}
+	return xyYToColor(values[1], values[2], values[0]).MultScalar(s.SkyIntensity).Clamp(0, math.MaxFloat64)
+}
+
+// perez evaluates the Perez sky luminance distribution function
+func perez(c [5]float64, cosTheta, gamma, cosGamma float64) float64 {
+	return (1.0 + c[0]*math.Exp(c[1]/cosTheta)) * (1.0 + c[2]*math.Exp(c[3]*gamma) + c[4]*cosGamma*cosGamma)
+}
+
+// xyYToColor converts from CIE xyY to linear sRGB
+func xyYToColor(x, y, luminance float64) shading.Color {
+	if y == 0.0 {
+		return shading.ColorBlack
+	}
+	bigX := x / y * luminance
+	bigZ := (1.0 - x - y) / y * luminance
+	return shading.Color{
+		Red:   3.2406*bigX - 1.5372*luminance - 0.4986*bigZ,
+		Green: -0.9689*bigX + 1.8758*luminance + 0.0415*bigZ,
+		Blue:  0.0557*bigX - 0.2040*luminance + 1.0570*bigZ,
+	}
+}
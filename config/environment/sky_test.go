@@ -0,0 +1,60 @@
+package environment
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+func newTestSky(t *testing.T) *Sky {
+	sky, err := (&Sky{
+		SunDirection:       geometry.Vector{X: 0.3, Y: 0.5, Z: -1.0},
+		Turbidity:          3.0,
+		GroundAlbedo:       shading.Color{Red: 0.3, Green: 0.3, Blue: 0.3},
+		SkyIntensity:       0.1,
+		SunIntensity:       10.0,
+		SunAngularDiameter: 0.53,
+	}).Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sky
+}
+
+func TestSkyIsBluerThanSun(t *testing.T) {
+	sky := newTestSky(t)
+	zenith := sky.Radiance(geometry.VectorUp)
+	if zenith.Blue <= zenith.Red {
+		t.Errorf("Expected a blue zenith but got %v\n", zenith)
+	}
+	sun := sky.Radiance(sky.SunDirection)
+	if sun.Luminance() <= 1000.0*zenith.Luminance() {
+		t.Errorf("Expected the sun (%v) to be far brighter than the sky (%v)\n", sun, zenith)
+	}
+	ground := sky.Radiance(geometry.Vector{X: 0.0, Y: -1.0, Z: 0.0})
+	if ground.Luminance() <= 0.0 {
+		t.Errorf("Expected a lit ground but got %v\n", ground)
+	}
+}
+
+func TestSkySamplePDF(t *testing.T) {
+	sky := newTestSky(t)
+	rng := rand.New(rand.NewSource(0))
+	hitSun := false
+	for s := 0; s < 256; s++ {
+		direction, pdf := sky.Sample(rng)
+		if pdf <= 0.0 {
+			t.Fatalf("Expected a positive density for sampled direction %v\n", direction)
+		}
+		if p := sky.PDF(direction); math.Abs(p-pdf) > 1e-9*pdf {
+			t.Errorf("Expected PDF %f to match the sampled density %f\n", p, pdf)
+		}
+		hitSun = hitSun || direction.Dot(sky.SunDirection) >= sky.sunCosMax
+	}
+	if !hitSun {
+		t.Errorf("Expected some samples to be drawn towards the sun\n")
+	}
+}
@@ -0,0 +1,33 @@
+package filter
+
+import (
+	"fmt"
+	"math"
+)
+
+// Box is a Filter which weights every sample within its extent equally
+// With a radius of 0.5, each sample only contributes to the pixel it lands in
+type Box struct {
+	Radius float64 `json:"radius"`
+}
+
+// Setup validates a box filter
+func (b *Box) Setup() (*Box, error) {
+	if b.Radius <= 0.0 {
+		return nil, fmt.Errorf("box filter radius is 0 or negative")
+	}
+	return b, nil
+}
+
+// Evaluate returns the weight of a sample offset (dx, dy) from a pixel center
+func (b *Box) Evaluate(dx, dy float64) float64 {
+	if math.Abs(dx) > b.Radius || math.Abs(dy) > b.Radius {
+		return 0.0
+	}
+	return 1.0
+}
+
+// Extent returns the radius beyond which this filter is zero
+func (b *Box) Extent() float64 {
+	return b.Radius
+}
@@ -0,0 +1,9 @@
+package filter
+
+// Filter describes a pixel reconstruction filter
+// Samples are splatted into every pixel whose center lies within the filter's extent,
+// weighted by the filter's value at the offset from the sample to that pixel center
+type Filter interface {
+	Evaluate(dx, dy float64) float64
+	Extent() float64
+}
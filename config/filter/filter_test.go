@@ -0,0 +1,69 @@
+package filter
+
+import (
+	"testing"
+)
+
+var filterWeight float64
+
+func allFilters(radius float64) []Filter {
+	box, _ := (&Box{Radius: radius}).Setup()
+	tent, _ := (&Tent{Radius: radius}).Setup()
+	gaussian, _ := (&Gaussian{Radius: radius, Alpha: 2.0}).Setup()
+	mitchell, _ := (&Mitchell{Radius: radius, B: 1.0 / 3.0, C: 1.0 / 3.0}).Setup()
+	lanczos, _ := (&Lanczos{Radius: radius}).Setup()
+	return []Filter{box, tent, gaussian, mitchell, lanczos}
+}
+
+func TestFilterZeroOutsideExtent(t *testing.T) {
+	for _, f := range allFilters(1.5) {
+		w := f.Evaluate(f.Extent()+0.01, 0.0)
+		if w != 0.0 {
+			t.Errorf("Expected 0.0 outside extent of %T but got %f\n", f, w)
+		}
+		w = f.Evaluate(0.0, -f.Extent()-0.01)
+		if w != 0.0 {
+			t.Errorf("Expected 0.0 outside extent of %T but got %f\n", f, w)
+		}
+	}
+}
+
+func TestFilterPositiveAtCenter(t *testing.T) {
+	for _, f := range allFilters(1.5) {
+		w := f.Evaluate(0.0, 0.0)
+		if w <= 0.0 {
+			t.Errorf("Expected positive weight at center of %T but got %f\n", f, w)
+		}
+	}
+}
+
+func TestFilterSymmetric(t *testing.T) {
+	for _, f := range allFilters(2.0) {
+		w1 := f.Evaluate(0.7, -0.3)
+		w2 := f.Evaluate(-0.7, 0.3)
+		if w1 != w2 {
+			t.Errorf("Expected symmetric weights for %T but got %f and %f\n", f, w1, w2)
+		}
+	}
+}
+
+func TestFilterInvalidRadius(t *testing.T) {
+	_, err := (&Gaussian{Radius: 0.0, Alpha: 2.0}).Setup()
+	if err == nil {
+		t.Errorf("Expected error for zero radius but got nil\n")
+	}
+	_, err = (&Gaussian{Radius: 1.0, Alpha: 0.0}).Setup()
+	if err == nil {
+		t.Errorf("Expected error for zero alpha but got nil\n")
+	}
+}
+
+func BenchmarkMitchellEvaluate(b *testing.B) {
+	m, _ := (&Mitchell{Radius: 2.0, B: 1.0 / 3.0, C: 1.0 / 3.0}).Setup()
+	var w float64
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w = m.Evaluate(0.3, 0.7)
+	}
+	filterWeight = w
+}
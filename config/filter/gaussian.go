@@ -0,0 +1,40 @@
+package filter
+
+import (
+	"fmt"
+	"math"
+)
+
+// Gaussian is a Filter following a gaussian bell curve,
+// shifted down so it reaches exactly zero at its radius
+type Gaussian struct {
+	Radius     float64 `json:"radius"`
+	Alpha      float64 `json:"alpha"` // falloff rate of the curve
+	edgeOffset float64
+}
+
+// Setup validates a gaussian filter and precomputes its edge value
+func (g *Gaussian) Setup() (*Gaussian, error) {
+	if g.Radius <= 0.0 {
+		return nil, fmt.Errorf("gaussian filter radius is 0 or negative")
+	}
+	if g.Alpha <= 0.0 {
+		return nil, fmt.Errorf("gaussian filter alpha is 0 or negative")
+	}
+	g.edgeOffset = math.Exp(-g.Alpha * g.Radius * g.Radius)
+	return g, nil
+}
+
+// Evaluate returns the weight of a sample offset (dx, dy) from a pixel center
+func (g *Gaussian) Evaluate(dx, dy float64) float64 {
+	return g.gaussian1D(dx) * g.gaussian1D(dy)
+}
+
+// Extent returns the radius beyond which this filter is zero
+func (g *Gaussian) Extent() float64 {
+	return g.Radius
+}
+
+func (g *Gaussian) gaussian1D(d float64) float64 {
+	return math.Max(0.0, math.Exp(-g.Alpha*d*d)-g.edgeOffset)
+}
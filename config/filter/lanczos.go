@@ -0,0 +1,46 @@
+package filter
+
+import (
+	"fmt"
+	"math"
+)
+
+// Lanczos is a windowed sinc Filter
+// The sinc is windowed by a second, wider sinc which stretches across the whole radius
+type Lanczos struct {
+	Radius float64 `json:"radius"`
+}
+
+// Setup validates a lanczos filter
+func (l *Lanczos) Setup() (*Lanczos, error) {
+	if l.Radius <= 0.0 {
+		return nil, fmt.Errorf("lanczos filter radius is 0 or negative")
+	}
+	return l, nil
+}
+
+// Evaluate returns the weight of a sample offset (dx, dy) from a pixel center
+func (l *Lanczos) Evaluate(dx, dy float64) float64 {
+	return l.windowedSinc(dx) * l.windowedSinc(dy)
+}
+
+// Extent returns the radius beyond which this filter is zero
+func (l *Lanczos) Extent() float64 {
+	return l.Radius
+}
+
+func (l *Lanczos) windowedSinc(d float64) float64 {
+	d = math.Abs(d)
+	if d > l.Radius {
+		return 0.0
+	}
+	return sinc(d) * sinc(d/l.Radius)
+}
+
+// sinc is the normalized sinc function
+func sinc(x float64) float64 {
+	if x < 1e-5 {
+		return 1.0
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
@@ -0,0 +1,47 @@
+package filter
+
+import (
+	"fmt"
+	"math"
+)
+
+// Mitchell is the Mitchell-Netravali cubic Filter
+// It has small negative lobes, which sharpen edges at the cost of slight ringing
+type Mitchell struct {
+	Radius float64 `json:"radius"`
+	B      float64 `json:"b"`
+	C      float64 `json:"c"`
+}
+
+// Setup validates a mitchell filter
+func (m *Mitchell) Setup() (*Mitchell, error) {
+	if m.Radius <= 0.0 {
+		return nil, fmt.Errorf("mitchell filter radius is 0 or negative")
+	}
+	return m, nil
+}
+
+// Evaluate returns the weight of a sample offset (dx, dy) from a pixel center
+func (m *Mitchell) Evaluate(dx, dy float64) float64 {
+	return m.mitchell1D(dx/m.Radius) * m.mitchell1D(dy/m.Radius)
+}
+
+// Extent returns the radius beyond which this filter is zero
+func (m *Mitchell) Extent() float64 {
+	return m.Radius
+}
+
+// mitchell1D evaluates the cubic for an offset normalized to the range [-1, 1]
+func (m *Mitchell) mitchell1D(d float64) float64 {
+	x := math.Abs(2.0 * d)
+	if x > 2.0 {
+		return 0.0
+	}
+	if x > 1.0 {
+		return ((-m.B-6.0*m.C)*x*x*x + (6.0*m.B+30.0*m.C)*x*x +
+			(-12.0*m.B-48.0*m.C)*x + (8.0*m.B + 24.0*m.C)) * (1.0 / 6.0)
+	}
+	return ((12.0-9.0*m.B-6.0*m.C)*x*x*x +
+		(-18.0+12.0*m.B+6.0*m.C)*x*x +
+		(6.0 - 2.0*m.B)) * (1.0 / 6.0)
+}
@@ -0,0 +1,29 @@
+package filter
+
+import (
+	"fmt"
+	"math"
+)
+
+// Tent is a Filter which falls off linearly from the pixel center to its radius
+type Tent struct {
+	Radius float64 `json:"radius"`
+}
+
+// Setup validates a tent filter
+func (t *Tent) Setup() (*Tent, error) {
+	if t.Radius <= 0.0 {
+		return nil, fmt.Errorf("tent filter radius is 0 or negative")
+	}
+	return t, nil
+}
+
+// Evaluate returns the weight of a sample offset (dx, dy) from a pixel center
+func (t *Tent) Evaluate(dx, dy float64) float64 {
+	return math.Max(0.0, t.Radius-math.Abs(dx)) * math.Max(0.0, t.Radius-math.Abs(dy))
+}
+
+// Extent returns the radius beyond which this filter is zero
+func (t *Tent) Extent() float64 {
+	return t.Radius
+}
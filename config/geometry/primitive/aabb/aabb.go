@@ -104,6 +104,41 @@ func (aabb *AABB) Intersection(ray geometry.Ray, t0, t1 float64) bool {
 	return true
 }
 
+// Span returns the times at which a ray enters and leaves this box, which may be before the ray's origin
+func (aabb *AABB) Span(ray geometry.Ray) (float64, float64, bool) {
+	tMin := math.Inf(-1)
+	tMax := math.Inf(1)
+	origins := [3]float64{ray.Origin.X, ray.Origin.Y, ray.Origin.Z}
+	directions := [3]float64{ray.Direction.X, ray.Direction.Y, ray.Direction.Z}
+	lows := [3]float64{aabb.A.X, aabb.A.Y, aabb.A.Z}
+	highs := [3]float64{aabb.B.X, aabb.B.Y, aabb.B.Z}
+	for axis := 0; axis < 3; axis++ {
+		if directions[axis] == 0.0 {
+			// parallel to this pair of planes, so the ray is either always or never between them
+			if origins[axis] < lows[axis] || origins[axis] > highs[axis] {
+				return 0.0, 0.0, false
+			}
+			continue
+		}
+		inverseDirection := 1.0 / directions[axis]
+		t0 := (lows[axis] - origins[axis]) * inverseDirection
+		t1 := (highs[axis] - origins[axis]) * inverseDirection
+		if inverseDirection < 0.0 {
+			t0, t1 = t1, t0
+		}
+		if t0 > tMin {
+			tMin = t0
+		}
+		if t1 < tMax {
+			tMax = t1
+		}
+		if tMax < tMin {
+			return 0.0, 0.0, false
+		}
+	}
+	return tMin, tMax, true
+}
+
 func (aabb *AABB) intersectionClassic(ray geometry.Ray, t0, t1 float64) bool {
 	tMin := t0
 	tMax := t1
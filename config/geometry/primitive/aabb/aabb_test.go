@@ -274,3 +274,42 @@ func BenchmarkAABBIntersectionZAxisMiss(b *testing.B) {
 	}
 	aabbHit = h
 }
+
+func TestAABBSpanFromInside(t *testing.T) {
+	aabb := basicAABB(0.0, 0.0, 0.0)
+	r := geometry.Ray{
+		Origin: geometry.Point{
+			X: 0.25,
+			Y: 0.5,
+			Z: 0.5,
+		},
+		Direction: geometry.Vector{
+			X: 1.0,
+			Y: 0.0,
+			Z: 0.0,
+		},
+	}
+	tMin, tMax, ok := aabb.Span(r)
+	if !ok || tMin != -0.25 || tMax != 0.75 {
+		t.Errorf("Expected span (-0.25, 0.75) but got (%f, %f), %t\n", tMin, tMax, ok)
+	}
+}
+
+func TestAABBSpanMiss(t *testing.T) {
+	aabb := basicAABB(0.0, 0.0, 0.0)
+	r := geometry.Ray{
+		Origin: geometry.Point{
+			X: -1.0,
+			Y: 2.0,
+			Z: 0.5,
+		},
+		Direction: geometry.Vector{
+			X: 1.0,
+			Y: 0.0,
+			Z: 0.0,
+		},
+	}
+	if _, _, ok := aabb.Span(r); ok {
+		t.Errorf("Expected false (miss) but got %t\n", ok)
+	}
+}
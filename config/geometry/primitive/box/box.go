@@ -124,7 +124,11 @@ func (b *Box) Setup() (*Box, error) {
 // Intersection computer the intersection of this object and a given ray if it exists
 func (b *Box) Intersection(ray geometry.Ray, tMin, tMax float64, rng *rand.Rand) (*material.RayHit, bool) {
 	if b.box.Intersection(ray, tMin, tMax) {
-		return b.list.Intersection(ray, tMin, tMax, rng)
+		// the faces are open primitives, but together they enclose the object
+		if rayHit, wasHit := b.list.Intersection(ray, tMin, tMax, rng); wasHit {
+			rayHit.IsClosed = true
+			return rayHit, true
+		}
 	}
 	return nil, false
 }
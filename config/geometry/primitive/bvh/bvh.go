@@ -22,11 +22,12 @@ type BVH struct {
 }
 
 // New sets up and returns a new BVH
-func New(pl *primitivelist.PrimitiveList) (*BVH, error) {
+// its boxes cover wherever the primitives move between times t0 and t1
+func New(pl *primitivelist.PrimitiveList, t0, t1 float64) (*BVH, error) {
 	newBVH := &BVH{}
 
 	// can we do the sort?
-	_, ok := pl.BoundingBox(0, 0)
+	_, ok := pl.BoundingBox(t0, t1)
 	if !ok {
 		return nil, fmt.Errorf("no bounding box for input Primitive List")
 	}
@@ -62,11 +63,11 @@ func New(pl *primitivelist.PrimitiveList) (*BVH, error) {
 		newBVH.left = pl.List[0]
 		newBVH.isSingle = true
 	} else {
-		left, err := New(pl.FirstHalfCopy())
+		left, err := New(pl.FirstHalfCopy(), t0, t1)
 		if err != nil {
 			return nil, err
 		}
-		right, err := New(pl.LastHalfCopy())
+		right, err := New(pl.LastHalfCopy(), t0, t1)
 		if err != nil {
 			return nil, err
 		}
@@ -74,14 +75,14 @@ func New(pl *primitivelist.PrimitiveList) (*BVH, error) {
 		newBVH.right = right
 	}
 	// est. box
-	leftBox, leftOk := newBVH.left.BoundingBox(0, 0)
+	leftBox, leftOk := newBVH.left.BoundingBox(t0, t1)
 	if newBVH.isSingle {
 		if !leftOk {
 			return nil, fmt.Errorf("no bounding box for some leaf of BVH")
 		}
 		newBVH.box = leftBox
 	} else {
-		rightBox, rightOk := newBVH.right.BoundingBox(0, 0)
+		rightBox, rightOk := newBVH.right.BoundingBox(t0, t1)
 		if !leftOk || !rightOk {
 			return nil, fmt.Errorf("no bounding box for some leaf of BVH")
 		}
@@ -69,7 +69,11 @@ func (c *Cylinder) Setup() (*Cylinder, error) {
 // Intersection computer the intersection of this object and a given ray if it exists
 func (c *Cylinder) Intersection(ray geometry.Ray, tMin, tMax float64, rng *rand.Rand) (*material.RayHit, bool) {
 	if c.box.Intersection(ray, tMin, tMax) {
-		return c.list.Intersection(ray, tMin, tMax, rng)
+		// the faces are open primitives, but together they enclose the object
+		if rayHit, wasHit := c.list.Intersection(ray, tMin, tMax, rng); wasHit {
+			rayHit.IsClosed = true
+			return rayHit, true
+		}
 	}
 	return nil, false
 }
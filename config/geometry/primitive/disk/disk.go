@@ -127,3 +127,8 @@ func Unit(xOffset, yOffset, zOffset float64) *Disk {
 	}).Setup()
 	return d
 }
+
+// SurfaceArea returns the area of the disk
+func (d *Disk) SurfaceArea() (float64, bool) {
+	return math.Pi * d.Radius * d.Radius, true
+}
@@ -82,7 +82,11 @@ func (hc *HollowCylinder) Setup() (*HollowCylinder, error) {
 // Intersection computer the intersection of this object and a given ray if it exists
 func (hc *HollowCylinder) Intersection(ray geometry.Ray, tMin, tMax float64, rng *rand.Rand) (*material.RayHit, bool) {
 	if hc.box.Intersection(ray, tMin, tMax) {
-		return hc.list.Intersection(ray, tMin, tMax, rng)
+		// the faces are open primitives, but together they enclose the object
+		if rayHit, wasHit := hc.list.Intersection(ray, tMin, tMax, rng); wasHit {
+			rayHit.IsClosed = true
+			return rayHit, true
+		}
 	}
 	return nil, false
 }
@@ -71,6 +71,7 @@ func (ic *InfiniteCylinder) Intersection(ray geometry.Ray, tMin, tMax float64, r
 				NormalAtHit: ic.normalAt(ray.PointAt(t1)),
 				Time:        t1,
 				Material:    ic.mat,
+				IsClosed:    true,
 			}, true
 		}
 		// evaluate and return second solution if in range
@@ -81,6 +82,7 @@ func (ic *InfiniteCylinder) Intersection(ray geometry.Ray, tMin, tMax float64, r
 				NormalAtHit: ic.normalAt(ray.PointAt(t2)),
 				Time:        t2,
 				Material:    ic.mat,
+				IsClosed:    true,
 			}, true
 		}
 	}
@@ -0,0 +1,196 @@
+package participatingvolume
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/geometry/primitive/aabb"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+// DensityField describes how dense a participating volume is throughout space
+type DensityField interface {
+	// Value returns the density at point p
+	Value(p geometry.Point) float64
+	// Max returns the greatest density anywhere, which bounds the steps taken when tracking through the volume
+	Max() float64
+}
+
+// ConstantDensity is the same density everywhere
+type ConstantDensity struct {
+	Density float64
+}
+
+// Setup sets up a constant density
+func (cd *ConstantDensity) Setup() (*ConstantDensity, error) {
+	if cd.Density <= 0.0 {
+		return nil, fmt.Errorf("Density must be greater than zero")
+	}
+	return cd, nil
+}
+
+// Value returns the density at point p
+func (cd *ConstantDensity) Value(p geometry.Point) float64 {
+	return cd.Density
+}
+
+// Max returns the greatest density anywhere
+func (cd *ConstantDensity) Max() float64 {
+	return cd.Density
+}
+
+// NoiseDensity varies with fractal Perlin noise, for wispy smoke and clouds
+type NoiseDensity struct {
+	Density   float64         // density where the noise is at its peak
+	Frequency float64         // noise features per unit of distance
+	Octaves   int             // layers of successively finer detail
+	Coverage  float64         // fraction of the noise's range which is dense at all, lower values giving sparser, more broken up volumes
+	Noise     *texture.Perlin // noise generator
+}
+
+// Setup sets up a noise density
+func (nd *NoiseDensity) Setup() (*NoiseDensity, error) {
+	if nd.Density <= 0.0 {
+		return nil, fmt.Errorf("Density must be greater than zero")
+	}
+	if nd.Frequency <= 0.0 {
+		return nil, fmt.Errorf("Frequency must be greater than zero")
+	}
+	if nd.Octaves < 1 {
+		return nil, fmt.Errorf("Octaves must be at least one")
+	}
+	if nd.Coverage <= 0.0 || nd.Coverage > 1.0 {
+		return nil, fmt.Errorf("Coverage must be greater than zero and at most 1")
+	}
+	if nd.Noise == nil {
+		return nil, fmt.Errorf("Noise must be given")
+	}
+	return nd, nil
+}
+
+// Value returns the density at point p
+func (nd *NoiseDensity) Value(p geometry.Point) float64 {
+	scaled := geometry.Point{
+		X: p.X * nd.Frequency,
+		Y: p.Y * nd.Frequency,
+		Z: p.Z * nd.Frequency,
+	}
+	// map the noise to [0, 1], and keep only the top of that range as the coverage shrinks
+	noise := 0.5*nd.Noise.FBM(scaled, nd.Octaves) + 0.5
+	fraction := (noise - (1.0 - nd.Coverage)) / nd.Coverage
+	return nd.Density * math.Max(0.0, math.Min(1.0, fraction))
+}
+
+// Max returns the greatest density anywhere
+func (nd *NoiseDensity) Max() float64 {
+	return nd.Density
+}
+
+// VoxelDensity is read from a grid of densities spanning a box, such as a simulation exported from other software
+// between voxel centers, the density is interpolated trilinearly
+type VoxelDensity struct {
+	Density    float64    // scale applied to every voxel
+	Resolution [3]int     // number of voxels along the x, y, and z axes
+	Values     []float64  // voxel densities, with x varying fastest, then y, then z
+	Box        *aabb.AABB // box the grid is stretched over
+	max        float64
+}
+
+// Setup sets up a voxel density
+func (vd *VoxelDensity) Setup() (*VoxelDensity, error) {
+	if vd.Density <= 0.0 {
+		return nil, fmt.Errorf("Density must be greater than zero")
+	}
+	if vd.Resolution[0] < 1 || vd.Resolution[1] < 1 || vd.Resolution[2] < 1 {
+		return nil, fmt.Errorf("Resolution must be at least one along each axis")
+	}
+	if len(vd.Values) != vd.Resolution[0]*vd.Resolution[1]*vd.Resolution[2] {
+		return nil, fmt.Errorf("Values must have one entry for each voxel")
+	}
+	if vd.Box == nil || vd.Box.B.X <= vd.Box.A.X || vd.Box.B.Y <= vd.Box.A.Y || vd.Box.B.Z <= vd.Box.A.Z {
+		return nil, fmt.Errorf("Box must have a positive size along each axis")
+	}
+	vd.max = 0.0
+	for _, value := range vd.Values {
+		if value < 0.0 || math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, fmt.Errorf("Values must be finite and greater than or equal to zero")
+		}
+		vd.max = math.Max(vd.max, value)
+	}
+	vd.max *= vd.Density
+	return vd, nil
+}
+
+// Value returns the density at point p
+func (vd *VoxelDensity) Value(p geometry.Point) float64 {
+	if p.X < vd.Box.A.X || p.X > vd.Box.B.X ||
+		p.Y < vd.Box.A.Y || p.Y > vd.Box.B.Y ||
+		p.Z < vd.Box.A.Z || p.Z > vd.Box.B.Z {
+		return 0.0
+	}
+	// find the voxel centers surrounding p, and how far p is between them
+	var low, high [3]int
+	var weight [3]float64
+	coordinates := [3]float64{
+		(p.X - vd.Box.A.X) / (vd.Box.B.X - vd.Box.A.X),
+		(p.Y - vd.Box.A.Y) / (vd.Box.B.Y - vd.Box.A.Y),
+		(p.Z - vd.Box.A.Z) / (vd.Box.B.Z - vd.Box.A.Z),
+	}
+	for axis := 0; axis < 3; axis++ {
+		position := coordinates[axis]*float64(vd.Resolution[axis]) - 0.5
+		floor := math.Floor(position)
+		weight[axis] = position - floor
+		low[axis] = clampIndex(int(floor), vd.Resolution[axis])
+		high[axis] = clampIndex(int(floor)+1, vd.Resolution[axis])
+	}
+	value := 0.0
+	for corner := 0; corner < 8; corner++ {
+		cornerWeight := 1.0
+		var index [3]int
+		for axis := 0; axis < 3; axis++ {
+			if corner&(1<<uint(axis)) != 0 {
+				index[axis] = high[axis]
+				cornerWeight *= weight[axis]
+			} else {
+				index[axis] = low[axis]
+				cornerWeight *= 1.0 - weight[axis]
+			}
+		}
+		value += cornerWeight * vd.Values[(index[2]*vd.Resolution[1]+index[1])*vd.Resolution[0]+index[0]]
+	}
+	return vd.Density * value
+}
+
+// Max returns the greatest density anywhere
+func (vd *VoxelDensity) Max() float64 {
+	return vd.max
+}
+
+// clampIndex keeps a voxel index within a grid of the given resolution, so the outer voxels extend to the box's faces
+func clampIndex(index, resolution int) int {
+	if index < 0 {
+		return 0
+	}
+	if index >= resolution {
+		return resolution - 1
+	}
+	return index
+}
+
+// DecodeVoxels reads voxel densities stored as consecutive little-endian 32-bit floats
+func DecodeVoxels(data []byte) ([]float64, error) {
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("voxel data must be a whole number of 32-bit floats")
+	}
+	values := make([]float64, len(data)/4)
+	for i := range values {
+		value := float64(math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:])))
+		if value < 0.0 || math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, fmt.Errorf("voxel densities must be finite and greater than or equal to zero")
+		}
+		values[i] = value
+	}
+	return values, nil
+}
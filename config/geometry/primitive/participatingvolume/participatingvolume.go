@@ -11,70 +11,118 @@ import (
 	"github.com/paulwrubel/photolum/config/shading/material"
 )
 
+// boundaryEpsilon is how far past a boundary hit the search for the next one starts
+const boundaryEpsilon = 0.0001
+
 // ParticipatingVolume represents a participating volume geometry primitive (smoke, fog, fire, etc.)
+// the density of the particles inside may vary from place to place
 type ParticipatingVolume struct {
-	Density                float64
-	Primitive              primitive.Primitive
-	negativeInverseDensity float64
-	mat                    material.Material
+	Density   DensityField
+	Primitive primitive.Primitive
+	box       *aabb.AABB
+	mat       material.Material
 }
 
 // Setup sets up a participating volume
 func (pv *ParticipatingVolume) Setup() (*ParticipatingVolume, error) {
-	if pv.Density <= 0.0 {
-		return nil, fmt.Errorf("Density must be greater than zero")
+	if pv.Density == nil {
+		return nil, fmt.Errorf("Density must be given")
+	}
+	if pv.Density.Max() <= 0.0 {
+		return nil, fmt.Errorf("Density must be greater than zero somewhere")
 	}
-	pv.negativeInverseDensity = -1.0 / pv.Density
+	box, ok := pv.Primitive.BoundingBox(0, 0)
+	if !ok {
+		return nil, fmt.Errorf("Primitive must be bounded")
+	}
+	pv.box = box
 	return pv, nil
 }
 
 // Intersection computer the intersection of this primitive and a given ray
+// collisions are found by delta tracking: tentative collisions are sampled as if the volume were as dense
+// as it gets everywhere, and each is a real collision with probability given by how dense it actually is there
 func (pv *ParticipatingVolume) Intersection(ray geometry.Ray, tMin, tMax float64, rng *rand.Rand) (*material.RayHit, bool) {
-
-	// hit first part of surface
-	rayHit1, wasHit := pv.Primitive.Intersection(ray, -math.MaxFloat64, math.MaxFloat64, rng)
-	if !wasHit {
-		return nil, false
-	}
-	// hit second part of surface
-	rayHit2, wasHit := pv.Primitive.Intersection(ray, rayHit1.Time+0.0001, math.MaxFloat64, rng)
-	if !wasHit {
+	entry, exit, ok := pv.span(ray, tMin, tMax, rng)
+	if !ok {
 		return nil, false
 	}
 
-	if rayHit1.Time < tMin {
-		rayHit1.Time = tMin
-	}
-	if rayHit2.Time > tMax {
-		rayHit2.Time = tMax
-	}
-
-	if rayHit1.Time >= rayHit2.Time {
-		return nil, false
+	majorant := pv.Density.Max()
+	rayMagnitude := ray.Direction.Magnitude()
+	time := entry
+	for {
+		time -= math.Log(1.0-rng.Float64()) / (majorant * rayMagnitude)
+		if time >= exit {
+			return nil, false
+		}
+		density := pv.Density.Value(ray.PointAt(time))
+		if rng.Float64()*majorant < density {
+			return &material.RayHit{
+				Ray:         ray,
+				NormalAtHit: geometry.VectorRight, // arbitrary, because volumetric materials don't use it
+				Time:        time,
+				U:           density / majorant, // so textures can follow the density, like the hotter core of a fire
+				V:           0.0,
+				Material:    pv.mat,
+				Medium:      pv,
+			}, true
+		}
 	}
+}
 
-	if rayHit1.Time < 0 {
-		rayHit1.Time = 0
+// Transmittance estimates the fraction of light travelling along ray between tMin and tMax
+// which passes through the volume without colliding with anything
+func (pv *ParticipatingVolume) Transmittance(ray geometry.Ray, tMin, tMax float64, rng *rand.Rand) float64 {
+	entry, exit, ok := pv.span(ray, tMin, tMax, rng)
+	if !ok {
+		return 1.0
 	}
 
 	rayMagnitude := ray.Direction.Magnitude()
-	distanceInsideBoundary := (rayHit2.Time - rayHit1.Time) * rayMagnitude
-	hitDistance := pv.negativeInverseDensity * math.Log(rng.Float64())
+	// a uniform volume's transmittance is known exactly
+	if constant, ok := pv.Density.(*ConstantDensity); ok {
+		return math.Exp(-constant.Density * (exit - entry) * rayMagnitude)
+	}
+	// otherwise, it's estimated by ratio tracking: every tentative collision
+	// lets through the fraction of light which the real density there doesn't stop
+	majorant := pv.Density.Max()
+	transmittance := 1.0
+	time := entry
+	for {
+		time -= math.Log(1.0-rng.Float64()) / (majorant * rayMagnitude)
+		if time >= exit {
+			return transmittance
+		}
+		transmittance *= 1.0 - pv.Density.Value(ray.PointAt(time))/majorant
+	}
+}
 
-	if hitDistance > distanceInsideBoundary {
-		return nil, false
+// span returns the times between tMin and tMax during which the ray is inside the volume
+// the boundary is only searched for across the volume's bounding box, since the ray's origin may be inside it
+func (pv *ParticipatingVolume) span(ray geometry.Ray, tMin, tMax float64, rng *rand.Rand) (float64, float64, bool) {
+	boxEntry, boxExit, ok := pv.box.Span(ray)
+	if !ok || boxExit < tMin || boxEntry > tMax {
+		return 0.0, 0.0, false
 	}
 
-	time := rayHit1.Time + (hitDistance / rayMagnitude)
+	// hit first part of surface
+	rayHit1, wasHit := pv.Primitive.Intersection(ray, boxEntry-boundaryEpsilon, boxExit+boundaryEpsilon, rng)
+	if !wasHit {
+		return 0.0, 0.0, false
+	}
+	// hit second part of surface
+	rayHit2, wasHit := pv.Primitive.Intersection(ray, rayHit1.Time+boundaryEpsilon, boxExit+boundaryEpsilon, rng)
+	if !wasHit {
+		return 0.0, 0.0, false
+	}
 
-	return &material.RayHit{
-		Ray:         ray,
-		NormalAtHit: geometry.VectorRight, // arbitrary, because material is always isotropic
-		Time:        time,
-		U:           0.0,
-		V:           0.0,
-		Material:    pv.mat,
-	}, true
+	entry := math.Max(rayHit1.Time, tMin)
+	exit := math.Min(rayHit2.Time, tMax)
+	if entry >= exit {
+		return 0.0, 0.0, false
+	}
+	return entry, exit, true
 }
 
 // BoundingBox returns an AABB of this object
@@ -0,0 +1,122 @@
+package participatingvolume
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/geometry/primitive/aabb"
+	"github.com/paulwrubel/photolum/config/geometry/primitive/sphere"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+func basicVolume(t *testing.T, density DensityField) *ParticipatingVolume {
+	s, err := (&sphere.Sphere{
+		Center: geometry.Point{X: 0.0, Y: 0.0, Z: 0.0},
+		Radius: 1.0,
+	}).Setup()
+	if err != nil {
+		t.Fatalf("Unexpected error setting up sphere: %s\n", err)
+	}
+	pv, err := (&ParticipatingVolume{
+		Density:   density,
+		Primitive: s,
+	}).Setup()
+	if err != nil {
+		t.Fatalf("Unexpected error setting up participating volume: %s\n", err)
+	}
+	return pv
+}
+
+func TestParticipatingVolumeConstantTransmittance(t *testing.T) {
+	pv := basicVolume(t, &ConstantDensity{Density: 0.5})
+	rng := rand.New(rand.NewSource(0))
+	r := geometry.Ray{
+		Origin:    geometry.Point{X: -5.0, Y: 0.0, Z: 0.0},
+		Direction: geometry.Vector{X: 1.0, Y: 0.0, Z: 0.0},
+	}
+	transmittance := pv.Transmittance(r, 0.0001, math.MaxFloat64, rng)
+	if math.Abs(transmittance-math.Exp(-1.0)) > 1e-9 {
+		t.Errorf("Expected transmittance %f but got %f\n", math.Exp(-1.0), transmittance)
+	}
+}
+
+func TestParticipatingVolumeTransmittanceFromInside(t *testing.T) {
+	pv := basicVolume(t, &ConstantDensity{Density: 0.5})
+	rng := rand.New(rand.NewSource(0))
+	r := geometry.Ray{
+		Origin:    geometry.Point{X: 0.0, Y: 0.0, Z: 0.0},
+		Direction: geometry.Vector{X: 0.0, Y: 2.0, Z: 0.0},
+	}
+	transmittance := pv.Transmittance(r, 0.0001, math.MaxFloat64, rng)
+	if math.Abs(transmittance-math.Exp(-0.5)) > 1e-3 {
+		t.Errorf("Expected transmittance %f but got %f\n", math.Exp(-0.5), transmittance)
+	}
+}
+
+// ratio tracking through a varying density should agree on average with how often delta tracking passes through
+func TestParticipatingVolumeTrackingAgrees(t *testing.T) {
+	noise, err := (&NoiseDensity{
+		Density:   2.0,
+		Frequency: 2.0,
+		Octaves:   3,
+		Coverage:  0.7,
+		Noise:     texture.NewPerlin(7),
+	}).Setup()
+	if err != nil {
+		t.Fatalf("Unexpected error setting up noise density: %s\n", err)
+	}
+	pv := basicVolume(t, noise)
+	rng := rand.New(rand.NewSource(0))
+	r := geometry.Ray{
+		Origin:    geometry.Point{X: -5.0, Y: 0.1, Z: 0.2},
+		Direction: geometry.Vector{X: 1.0, Y: 0.0, Z: 0.0},
+	}
+	sampleCount := 20000
+	ratioSum := 0.0
+	passCount := 0
+	for i := 0; i < sampleCount; i++ {
+		ratioSum += pv.Transmittance(r, 0.0001, math.MaxFloat64, rng)
+		if _, wasHit := pv.Intersection(r, 0.0001, math.MaxFloat64, rng); !wasHit {
+			passCount++
+		}
+	}
+	ratio := ratioSum / float64(sampleCount)
+	delta := float64(passCount) / float64(sampleCount)
+	if math.Abs(ratio-delta) > 0.02 {
+		t.Errorf("Expected ratio tracking (%f) and delta tracking (%f) to agree\n", ratio, delta)
+	}
+}
+
+func TestVoxelDensityInterpolates(t *testing.T) {
+	vd, err := (&VoxelDensity{
+		Density:    2.0,
+		Resolution: [3]int{2, 1, 1},
+		Values:     []float64{0.0, 1.0},
+		Box: &aabb.AABB{
+			A: geometry.Point{X: 0.0, Y: 0.0, Z: 0.0},
+			B: geometry.Point{X: 2.0, Y: 1.0, Z: 1.0},
+		},
+	}).Setup()
+	if err != nil {
+		t.Fatalf("Unexpected error setting up voxel density: %s\n", err)
+	}
+	tests := []struct {
+		point    geometry.Point
+		expected float64
+	}{
+		{geometry.Point{X: 0.25, Y: 0.5, Z: 0.5}, 0.0},
+		{geometry.Point{X: 1.0, Y: 0.5, Z: 0.5}, 1.0},
+		{geometry.Point{X: 1.75, Y: 0.5, Z: 0.5}, 2.0},
+		{geometry.Point{X: 3.0, Y: 0.5, Z: 0.5}, 0.0},
+	}
+	for _, test := range tests {
+		if value := vd.Value(test.point); math.Abs(value-test.expected) > 1e-9 {
+			t.Errorf("Expected density %f at %v but got %f\n", test.expected, test.point, value)
+		}
+	}
+	if vd.Max() != 2.0 {
+		t.Errorf("Expected max density 2.0 but got %f\n", vd.Max())
+	}
+}
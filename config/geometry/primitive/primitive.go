@@ -17,3 +17,17 @@ type Primitive interface {
 	IsClosed() bool
 	Copy() Primitive
 }
+
+// Measurable is implemented by primitives which may know their own surface area
+type Measurable interface {
+	// SurfaceArea returns the area of the primitive's surface, and whether it's known
+	SurfaceArea() (float64, bool)
+}
+
+// SurfaceArea returns the surface area of any primitive, and whether it's known
+func SurfaceArea(p Primitive) (float64, bool) {
+	if m, ok := p.(Measurable); ok {
+		return m.SurfaceArea()
+	}
+	return 0.0, false
+}
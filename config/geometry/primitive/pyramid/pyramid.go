@@ -112,7 +112,11 @@ func (p *Pyramid) Setup() (*Pyramid, error) {
 // Intersection computer the intersection of this object and a given ray if it exists
 func (p *Pyramid) Intersection(ray geometry.Ray, tMin, tMax float64, rng *rand.Rand) (*material.RayHit, bool) {
 	if p.box.Intersection(ray, tMin, tMax) {
-		return p.list.Intersection(ray, tMin, tMax, rng)
+		// the faces are open primitives, but together they enclose the object
+		if rayHit, wasHit := p.list.Intersection(ray, tMin, tMax, rng); wasHit {
+			rayHit.IsClosed = true
+			return rayHit, true
+		}
 	}
 	return nil, false
 }
@@ -2,6 +2,7 @@ package rectangle
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 
 	"github.com/paulwrubel/photolum/config/geometry"
@@ -93,3 +94,16 @@ func Unit(xOffset, yOffset, zOffset float64) *Rectangle {
 	}).Setup()
 	return r
 }
+
+// SurfaceArea returns the area of the rectangle
+func (r *Rectangle) SurfaceArea() (float64, bool) {
+	dx := math.Abs(r.B.X - r.A.X)
+	dy := math.Abs(r.B.Y - r.A.Y)
+	dz := math.Abs(r.B.Z - r.A.Z)
+	if dx == 0.0 {
+		return dy * dz, true
+	} else if dy == 0.0 {
+		return dx * dz, true
+	}
+	return dx * dy, true
+}
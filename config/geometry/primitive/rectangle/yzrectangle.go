@@ -89,6 +89,8 @@ func (r *yzRectangle) Intersection(ray geometry.Ray, tMin, tMax float64, rng *ra
 	return &material.RayHit{
 		Ray:         ray,
 		NormalAtHit: r.normal,
+		Tangent:     geometry.Vector{X: 0.0, Y: 0.0, Z: 1.0},
+		Bitangent:   geometry.Vector{X: 0.0, Y: 1.0, Z: 0.0},
 		Time:        t,
 		U:           u,
 		V:           v,
@@ -69,19 +69,23 @@ func (s *Sphere) Intersection(ray geometry.Ray, tMin, tMax float64, rng *rand.Ra
 			u := 1 - (phi+math.Pi)/(2*math.Pi)
 			v := (theta + math.Pi/2) / math.Pi
 
+			tangent, bitangent := s.tangentsAt(unitHitPoint)
 			return &material.RayHit{
 				Ray:         ray,
 				NormalAtHit: s.normalAt(hitPoint),
+				Tangent:     tangent,
+				Bitangent:   bitangent,
 				Time:        t1,
 				U:           u,
 				V:           v,
 				Material:    s.mat,
+				IsClosed:    true,
 			}, true
 		}
 		// evaluate and return second solution if in range
 		t2 := (-b + root) / a
 		if t2 >= tMin && t2 <= tMax {
-			hitPoint := ray.PointAt(t1)
+			hitPoint := ray.PointAt(t2)
 			unitHitPoint := s.Center.To(hitPoint).DivScalar(s.Radius)
 
 			phi := math.Atan2(unitHitPoint.Z, unitHitPoint.X)
@@ -90,13 +94,17 @@ func (s *Sphere) Intersection(ray geometry.Ray, tMin, tMax float64, rng *rand.Ra
 			u := 1.0 - (phi+math.Pi)/(2*math.Pi)
 			v := (theta + math.Pi/2) / math.Pi
 
+			tangent, bitangent := s.tangentsAt(unitHitPoint)
 			return &material.RayHit{
 				Ray:         ray,
 				NormalAtHit: s.normalAt(ray.PointAt(t2)),
+				Tangent:     tangent,
+				Bitangent:   bitangent,
 				Time:        t2,
 				U:           u,
 				V:           v,
 				Material:    s.mat,
+				IsClosed:    true,
 			}, true
 		}
 	}
@@ -148,6 +156,21 @@ func (s *Sphere) normalAt(p geometry.Point) geometry.Vector {
 	return s.Center.To(p).Unit()
 }
 
+// tangentsAt returns the directions in which U and V increase at a point on the unit sphere
+// at the poles, where these are undefined, both are zero
+func (s *Sphere) tangentsAt(unitPoint geometry.Vector) (geometry.Vector, geometry.Vector) {
+	tangent := geometry.Vector{X: unitPoint.Z, Y: 0.0, Z: -unitPoint.X}
+	bitangent := geometry.Vector{
+		X: -unitPoint.X * unitPoint.Y,
+		Y: 1.0 - unitPoint.Y*unitPoint.Y,
+		Z: -unitPoint.Y * unitPoint.Z,
+	}
+	if tangent.Magnitude() == 0.0 || bitangent.Magnitude() == 0.0 {
+		return geometry.VectorZero, geometry.VectorZero
+	}
+	return tangent.Unit(), bitangent.Unit()
+}
+
 // Unit returns a unit sphere
 func Unit(xOffset, yOffset, zOffset float64) *Sphere {
 	s, _ := (&Sphere{
@@ -160,3 +183,8 @@ func Unit(xOffset, yOffset, zOffset float64) *Sphere {
 	}).Setup()
 	return s
 }
+
+// SurfaceArea returns the area of the sphere
+func (s *Sphere) SurfaceArea() (float64, bool) {
+	return 4.0 * math.Pi * s.Radius * s.Radius, true
+}
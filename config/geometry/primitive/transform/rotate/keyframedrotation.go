@@ -0,0 +1,225 @@
+package rotate
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/geometry/primitive"
+	"github.com/paulwrubel/photolum/config/geometry/primitive/aabb"
+	"github.com/paulwrubel/photolum/config/shading/material"
+
+	"github.com/go-gl/mathgl/mgl64"
+)
+
+// keyframedRotationBoundingSteps is how many orientations between each pair of keyframes are bounded
+// the bounds are padded by how far the primitive can swing between them
+const keyframedRotationBoundingSteps = 16
+
+// RotationKeyframe is the orientation of a KeyframedRotation at a moment
+type RotationKeyframe struct {
+	Moment     float64   `json:"moment"`
+	AxisAngles []float64 `json:"axis_angles"`
+}
+
+// KeyframedRotation is a primitive turning about the origin through the orientations of its keyframes,
+// at a steady rate between them, and held at the first and last before and after them
+// rays see it however it's turned at the moment they were cast
+type KeyframedRotation struct {
+	Keyframes   []RotationKeyframe `json:"keyframes"`
+	Order       string             `json:"order"`
+	Primitive   primitive.Primitive
+	quaternions []mgl64.Quat
+}
+
+// Setup sets up some internal fields of a rotation
+func (kr *KeyframedRotation) Setup() (*KeyframedRotation, error) {
+	if len(kr.Keyframes) == 0 {
+		return nil, fmt.Errorf("keyframed rotation has no keyframes")
+	}
+	kr.Order = strings.ToUpper(kr.Order)
+
+	rotationOrder, err := parseRotationOrder(kr.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(kr.Keyframes, func(i, j int) bool {
+		return kr.Keyframes[i].Moment < kr.Keyframes[j].Moment
+	})
+	kr.quaternions = make([]mgl64.Quat, len(kr.Keyframes))
+	for i, keyframe := range kr.Keyframes {
+		kr.quaternions[i] = anglesToQuat(keyframe.AxisAngles, rotationOrder)
+		// a quaternion and its negation are the same orientation, and the nearer one turns the short way round
+		if i > 0 && kr.quaternions[i-1].Dot(kr.quaternions[i]) < 0.0 {
+			kr.quaternions[i] = kr.quaternions[i].Scale(-1.0)
+		}
+	}
+	return kr, nil
+}
+
+// quaternionAt returns the orientation of the primitive at a moment
+func (kr *KeyframedRotation) quaternionAt(moment float64) mgl64.Quat {
+	next := sort.Search(len(kr.Keyframes), func(i int) bool {
+		return kr.Keyframes[i].Moment > moment
+	})
+	if next == 0 {
+		return kr.quaternions[0]
+	}
+	if next == len(kr.Keyframes) {
+		return kr.quaternions[next-1]
+	}
+	fraction := (moment - kr.Keyframes[next-1].Moment) / (kr.Keyframes[next].Moment - kr.Keyframes[next-1].Moment)
+	return mgl64.QuatSlerp(kr.quaternions[next-1], kr.quaternions[next], fraction)
+}
+
+// Intersection computer the intersection of this object and a given ray if it exists
+func (kr *KeyframedRotation) Intersection(ray geometry.Ray, tMin, tMax float64, rng *rand.Rand) (*material.RayHit, bool) {
+	quaternion := kr.quaternionAt(ray.Moment)
+	inverse := quaternion.Inverse()
+
+	rotatedRay := ray
+	rotatedRay.Origin = rotatePoint(inverse, ray.Origin)
+	rotatedRay.Direction = rotateVector(inverse, ray.Direction)
+
+	rayHit, wasHit := kr.Primitive.Intersection(rotatedRay, tMin, tMax, rng)
+	if wasHit {
+		return &material.RayHit{
+			Ray:         ray,
+			NormalAtHit: rotateVector(quaternion, rayHit.NormalAtHit),
+			Tangent:     rotateVector(quaternion, rayHit.Tangent),
+			Bitangent:   rotateVector(quaternion, rayHit.Bitangent),
+			Time:        rayHit.Time,
+			U:           rayHit.U,
+			V:           rayHit.V,
+			Material:    rayHit.Material,
+			IsClosed:    rayHit.IsClosed,
+		}, true
+	}
+	return nil, false
+}
+
+// BoundingBox returns an AABB for this object, covering everywhere it turns between moments t0 and t1
+// between two bounded orientations, each point swings along an arc no further from their boxes
+// than the arc bulges from its chord, so the boxes are padded by that much for the furthest point
+func (kr *KeyframedRotation) BoundingBox(t0, t1 float64) (*aabb.AABB, bool) {
+	box, ok := kr.Primitive.BoundingBox(t0, t1)
+	if !ok {
+		return nil, false
+	}
+	maxRadius := 0.0
+	for _, corner := range boxCorners(box) {
+		maxRadius = math.Max(maxRadius, geometry.Point{}.To(corner).Magnitude())
+	}
+
+	moments := []float64{t0}
+	for _, keyframe := range kr.Keyframes {
+		if keyframe.Moment > t0 && keyframe.Moment < t1 {
+			moments = append(moments, keyframe.Moment)
+		}
+	}
+	moments = append(moments, t1)
+
+	turnedBox := rotatedBox(kr.quaternionAt(t0), box)
+	for i := 1; i < len(moments); i++ {
+		start, end := moments[i-1], moments[i]
+		if end <= start {
+			continue
+		}
+		// the rotation between keyframes is steady, so each step turns by an equal angle
+		startQuaternion, endQuaternion := kr.quaternionAt(start), kr.quaternionAt(end)
+		cosHalfAngle := math.Min(1.0, math.Abs(startQuaternion.Dot(endQuaternion)))
+		stepAngle := 2.0 * math.Acos(cosHalfAngle) / keyframedRotationBoundingSteps
+		padding := maxRadius * (1.0 - math.Cos(stepAngle/2.0))
+		for step := 1; step <= keyframedRotationBoundingSteps; step++ {
+			moment := start + (end-start)*float64(step)/keyframedRotationBoundingSteps
+			turnedBox = aabb.SurroundingBox(turnedBox, rotatedBox(kr.quaternionAt(moment), box))
+		}
+		turnedBox = &aabb.AABB{
+			A: turnedBox.A.SubVector(geometry.Vector{X: padding, Y: padding, Z: padding}),
+			B: turnedBox.B.AddVector(geometry.Vector{X: padding, Y: padding, Z: padding}),
+		}
+	}
+	return turnedBox, true
+}
+
+// SetMaterial sets the material of this object
+func (kr *KeyframedRotation) SetMaterial(m material.Material) {
+	kr.Primitive.SetMaterial(m)
+}
+
+// IsInfinite returns whether this object is infinite
+func (kr *KeyframedRotation) IsInfinite() bool {
+	return kr.Primitive.IsInfinite()
+}
+
+// IsClosed returns whether this object is closed
+func (kr *KeyframedRotation) IsClosed() bool {
+	return kr.Primitive.IsClosed()
+}
+
+// Copy returns a shallow copy of this object
+func (kr *KeyframedRotation) Copy() primitive.Primitive {
+	newKR := *kr
+	return &newKR
+}
+
+// SurfaceArea returns the area of the rotated primitive, which is unchanged by the rotation
+func (kr *KeyframedRotation) SurfaceArea() (float64, bool) {
+	return primitive.SurfaceArea(kr.Primitive)
+}
+
+// rotateVector returns a vector rotated by a quaternion
+func rotateVector(q mgl64.Quat, v geometry.Vector) geometry.Vector {
+	rotatedMGL := q.Rotate(mgl64.Vec3{v.X, v.Y, v.Z})
+	return geometry.Vector{
+		X: rotatedMGL.X(),
+		Y: rotatedMGL.Y(),
+		Z: rotatedMGL.Z(),
+	}
+}
+
+// rotatePoint returns a point rotated about the origin by a quaternion
+func rotatePoint(q mgl64.Quat, p geometry.Point) geometry.Point {
+	rotatedMGL := q.Rotate(mgl64.Vec3{p.X, p.Y, p.Z})
+	return geometry.Point{
+		X: rotatedMGL.X(),
+		Y: rotatedMGL.Y(),
+		Z: rotatedMGL.Z(),
+	}
+}
+
+// boxCorners returns the eight corners of a box
+func boxCorners(box *aabb.AABB) []geometry.Point {
+	corners := make([]geometry.Point, 0, 8)
+	for i := 0.0; i < 2; i++ {
+		for j := 0.0; j < 2; j++ {
+			for k := 0.0; k < 2; k++ {
+				corners = append(corners, geometry.Point{
+					X: i*box.B.X + (1-i)*box.A.X,
+					Y: j*box.B.Y + (1-j)*box.A.Y,
+					Z: k*box.B.Z + (1-k)*box.A.Z,
+				})
+			}
+		}
+	}
+	return corners
+}
+
+// rotatedBox returns an AABB around a box rotated about the origin by a quaternion
+func rotatedBox(q mgl64.Quat, box *aabb.AABB) *aabb.AABB {
+	minPoint := geometry.PointMax
+	maxPoint := geometry.PointMax.Negate()
+	for _, corner := range boxCorners(box) {
+		rotatedCorner := rotatePoint(q, corner)
+		maxPoint = geometry.MaxComponents(maxPoint, rotatedCorner)
+		minPoint = geometry.MinComponents(minPoint, rotatedCorner)
+	}
+	return &aabb.AABB{
+		A: minPoint,
+		B: maxPoint,
+	}
+}
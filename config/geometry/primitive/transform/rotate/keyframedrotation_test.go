@@ -0,0 +1,74 @@
+package rotate
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/geometry/primitive/sphere"
+)
+
+func TestKeyframedRotationIntersection(t *testing.T) {
+	// a sphere off to the side swings a quarter turn about the Y axis
+	kr, err := (&KeyframedRotation{
+		Keyframes: []RotationKeyframe{
+			{Moment: 0.0, AxisAngles: []float64{0.0, 0.0, 0.0}},
+			{Moment: 1.0, AxisAngles: []float64{0.0, 90.0, 0.0}},
+		},
+		Order:     "xyz",
+		Primitive: sphere.Unit(5.0, 0.0, 0.0),
+	}).Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	halfway := 5.0 * math.Sqrt(0.5)
+	tests := []struct {
+		moment   float64
+		x, z     float64
+		expected bool
+	}{
+		{0.0, 5.0, 0.0, true},
+		{1.0, 0.0, -5.0, true},
+		{1.0, 5.0, 0.0, false},
+		{0.5, halfway, -halfway, true},
+		{0.5, 5.0, 0.0, false},
+	}
+	for _, test := range tests {
+		r := geometry.Ray{
+			Origin:    geometry.Point{X: test.x, Y: 10.0, Z: test.z},
+			Direction: geometry.Vector{Y: -1.0},
+			Moment:    test.moment,
+		}
+		if _, h := kr.Intersection(r, 1e-7, 1e9, nil); h != test.expected {
+			t.Errorf("Expected hit to be %t at (%f, %f) and moment %f but got %t\n", test.expected, test.x, test.z, test.moment, h)
+		}
+	}
+}
+
+func TestKeyframedRotationBoundingBox(t *testing.T) {
+	kr, _ := (&KeyframedRotation{
+		Keyframes: []RotationKeyframe{
+			{Moment: 0.0, AxisAngles: []float64{0.0, 0.0, 0.0}},
+			{Moment: 1.0, AxisAngles: []float64{0.0, 180.0, 0.0}},
+		},
+		Order:     "XYZ",
+		Primitive: sphere.Unit(5.0, 0.0, 0.0),
+	}).Setup()
+	box, ok := kr.BoundingBox(0.0, 1.0)
+	if !ok {
+		t.Fatal("Expected a bounding box")
+	}
+	// the sphere's center sweeps half a circle of radius 5 behind the origin, through every moment
+	for i := 0; i <= 1000; i++ {
+		angle := math.Pi * float64(i) / 1000.0
+		center := geometry.Point{X: 5.0 * math.Cos(angle), Z: -5.0 * math.Sin(angle)}
+		for _, p := range []geometry.Point{
+			{X: center.X + 0.5, Z: center.Z}, {X: center.X - 0.5, Z: center.Z},
+			{X: center.X, Z: center.Z + 0.5}, {X: center.X, Z: center.Z - 0.5},
+		} {
+			if p.X < box.A.X || p.X > box.B.X || p.Z < box.A.Z || p.Z > box.B.Z {
+				t.Fatalf("Expected the box %v to cover %v\n", box, p)
+			}
+		}
+	}
+}
@@ -28,44 +28,56 @@ type Quaternion struct {
 func (q *Quaternion) Setup() (*Quaternion, error) {
 	q.Order = strings.ToUpper(q.Order)
 
-	var rotationOrder mgl64.RotationOrder
-	switch q.Order {
+	rotationOrder, err := parseRotationOrder(q.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	q.quaternion = anglesToQuat(q.AxisAngles, rotationOrder)
+	q.inverse = q.quaternion.Inverse()
+	return q, nil
+}
+
+// parseRotationOrder returns the order in which to apply rotations about each axis
+func parseRotationOrder(order string) (mgl64.RotationOrder, error) {
+	switch order {
 	case "XYX":
-		rotationOrder = mgl64.XYX
+		return mgl64.XYX, nil
 	case "XYZ":
-		rotationOrder = mgl64.XYZ
+		return mgl64.XYZ, nil
 	case "XZX":
-		rotationOrder = mgl64.XZX
+		return mgl64.XZX, nil
 	case "XZY":
-		rotationOrder = mgl64.XZY
+		return mgl64.XZY, nil
 	case "YXY":
-		rotationOrder = mgl64.YXY
+		return mgl64.YXY, nil
 	case "YXZ":
-		rotationOrder = mgl64.YXZ
+		return mgl64.YXZ, nil
 	case "YZX":
-		rotationOrder = mgl64.YZX
+		return mgl64.YZX, nil
 	case "YZY":
-		rotationOrder = mgl64.YZY
+		return mgl64.YZY, nil
 	case "ZXY":
-		rotationOrder = mgl64.ZXY
+		return mgl64.ZXY, nil
 	case "ZXZ":
-		rotationOrder = mgl64.ZXZ
+		return mgl64.ZXZ, nil
 	case "ZYX":
-		rotationOrder = mgl64.ZYX
+		return mgl64.ZYX, nil
 	case "ZYZ":
-		rotationOrder = mgl64.ZYZ
+		return mgl64.ZYZ, nil
 	default:
-		return nil, fmt.Errorf("invalid order (%s) for quaternion", q.Order)
+		return 0, fmt.Errorf("invalid order (%s) for quaternion", order)
 	}
+}
 
-	q.quaternion = mgl64.AnglesToQuat(
-		mgl64.DegToRad(q.AxisAngles[0]),
-		mgl64.DegToRad(q.AxisAngles[1]),
-		mgl64.DegToRad(q.AxisAngles[2]),
-		rotationOrder,
+// anglesToQuat returns the rotation by angles in degrees about each axis, in order
+func anglesToQuat(axisAngles []float64, order mgl64.RotationOrder) mgl64.Quat {
+	return mgl64.AnglesToQuat(
+		mgl64.DegToRad(axisAngles[0]),
+		mgl64.DegToRad(axisAngles[1]),
+		mgl64.DegToRad(axisAngles[2]),
+		order,
 	)
-	q.inverse = q.quaternion.Inverse()
-	return q, nil
 }
 
 // Intersection computer the intersection of this object and a given ray if it exists
@@ -93,25 +105,31 @@ func (q *Quaternion) Intersection(ray geometry.Ray, tMin, tMax float64, rng *ran
 
 	rayHit, wasHit := q.Primitive.Intersection(rotatedRay, tMin, tMax, rng)
 	if wasHit {
-		rotatedNormalMGL := mgl64.Vec3{rayHit.NormalAtHit.X, rayHit.NormalAtHit.Y, rayHit.NormalAtHit.Z}
-		unrotatedNormalMGL := q.quaternion.Rotate(rotatedNormalMGL)
-		unrotatedNormal := geometry.Vector{
-			X: unrotatedNormalMGL.X(),
-			Y: unrotatedNormalMGL.Y(),
-			Z: unrotatedNormalMGL.Z(),
-		}
 		return &material.RayHit{
 			Ray:         ray,
-			NormalAtHit: unrotatedNormal,
+			NormalAtHit: q.unrotate(rayHit.NormalAtHit),
+			Tangent:     q.unrotate(rayHit.Tangent),
+			Bitangent:   q.unrotate(rayHit.Bitangent),
 			Time:        rayHit.Time,
 			U:           rayHit.U,
 			V:           rayHit.V,
 			Material:    rayHit.Material,
+			IsClosed:    rayHit.IsClosed,
 		}, true
 	}
 	return nil, false
 }
 
+// unrotate rotates a vector from the rotated primitive's space back into world space
+func (q *Quaternion) unrotate(v geometry.Vector) geometry.Vector {
+	unrotatedMGL := q.quaternion.Rotate(mgl64.Vec3{v.X, v.Y, v.Z})
+	return geometry.Vector{
+		X: unrotatedMGL.X(),
+		Y: unrotatedMGL.Y(),
+		Z: unrotatedMGL.Z(),
+	}
+}
+
 // BoundingBox returns an AABB for this object
 func (q *Quaternion) BoundingBox(t0, t1 float64) (*aabb.AABB, bool) {
 
@@ -168,3 +186,8 @@ func (q *Quaternion) Copy() primitive.Primitive {
 	newRX := *q
 	return &newRX
 }
+
+// SurfaceArea returns the area of the rotated primitive, which is unchanged by the rotation
+func (q *Quaternion) SurfaceArea() (float64, bool) {
+	return primitive.SurfaceArea(q.Primitive)
+}
@@ -45,21 +45,29 @@ func (rx *RotationX) Intersection(ray geometry.Ray, tMin, tMax float64, rng *ran
 
 	rayHit, wasHit := rx.Primitive.Intersection(rotatedRay, tMin, tMax, rng)
 	if wasHit {
-		unrotatedNormal := rayHit.NormalAtHit
-		unrotatedNormal.Y = rx.cosTheta*rayHit.NormalAtHit.Y - rx.sinTheta*rayHit.NormalAtHit.Z
-		unrotatedNormal.Z = rx.sinTheta*rayHit.NormalAtHit.Y + rx.cosTheta*rayHit.NormalAtHit.Z
 		return &material.RayHit{
 			Ray:         ray,
-			NormalAtHit: unrotatedNormal,
+			NormalAtHit: rx.unrotate(rayHit.NormalAtHit),
+			Tangent:     rx.unrotate(rayHit.Tangent),
+			Bitangent:   rx.unrotate(rayHit.Bitangent),
 			Time:        rayHit.Time,
 			U:           rayHit.U,
 			V:           rayHit.V,
 			Material:    rayHit.Material,
+			IsClosed:    rayHit.IsClosed,
 		}, true
 	}
 	return nil, false
 }
 
+// unrotate rotates a vector from the rotated primitive's space back into world space
+func (rx *RotationX) unrotate(v geometry.Vector) geometry.Vector {
+	unrotated := v
+	unrotated.Y = rx.cosTheta*v.Y - rx.sinTheta*v.Z
+	unrotated.Z = rx.sinTheta*v.Y + rx.cosTheta*v.Z
+	return unrotated
+}
+
 // BoundingBox returns an AABB for this object
 func (rx *RotationX) BoundingBox(t0, t1 float64) (*aabb.AABB, bool) {
 
@@ -116,3 +124,8 @@ func (rx *RotationX) Copy() primitive.Primitive {
 	newRX := *rx
 	return &newRX
 }
+
+// SurfaceArea returns the area of the rotated primitive, which is unchanged by the rotation
+func (rx *RotationX) SurfaceArea() (float64, bool) {
+	return primitive.SurfaceArea(rx.Primitive)
+}
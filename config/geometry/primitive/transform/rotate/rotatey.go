@@ -45,21 +45,29 @@ func (ry *RotationY) Intersection(ray geometry.Ray, tMin, tMax float64, rng *ran
 
 	rayHit, wasHit := ry.Primitive.Intersection(rotatedRay, tMin, tMax, rng)
 	if wasHit {
-		unrotatedNormal := rayHit.NormalAtHit
-		unrotatedNormal.X = ry.cosTheta*rayHit.NormalAtHit.X + ry.sinTheta*rayHit.NormalAtHit.Z
-		unrotatedNormal.Z = -ry.sinTheta*rayHit.NormalAtHit.X + ry.cosTheta*rayHit.NormalAtHit.Z
 		return &material.RayHit{
 			Ray:         ray,
-			NormalAtHit: unrotatedNormal,
+			NormalAtHit: ry.unrotate(rayHit.NormalAtHit),
+			Tangent:     ry.unrotate(rayHit.Tangent),
+			Bitangent:   ry.unrotate(rayHit.Bitangent),
 			Time:        rayHit.Time,
 			U:           rayHit.U,
 			V:           rayHit.V,
 			Material:    rayHit.Material,
+			IsClosed:    rayHit.IsClosed,
 		}, true
 	}
 	return nil, false
 }
 
+// unrotate rotates a vector from the rotated primitive's space back into world space
+func (ry *RotationY) unrotate(v geometry.Vector) geometry.Vector {
+	unrotated := v
+	unrotated.X = ry.cosTheta*v.X + ry.sinTheta*v.Z
+	unrotated.Z = -ry.sinTheta*v.X + ry.cosTheta*v.Z
+	return unrotated
+}
+
 // BoundingBox returns an AABB for this object
 func (ry *RotationY) BoundingBox(t0, t1 float64) (*aabb.AABB, bool) {
 
@@ -116,3 +124,8 @@ func (ry *RotationY) Copy() primitive.Primitive {
 	newRY := *ry
 	return &newRY
 }
+
+// SurfaceArea returns the area of the rotated primitive, which is unchanged by the rotation
+func (ry *RotationY) SurfaceArea() (float64, bool) {
+	return primitive.SurfaceArea(ry.Primitive)
+}
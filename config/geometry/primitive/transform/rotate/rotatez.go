@@ -45,21 +45,29 @@ func (rz *RotationZ) Intersection(ray geometry.Ray, tMin, tMax float64, rng *ran
 
 	rayHit, wasHit := rz.Primitive.Intersection(rotatedRay, tMin, tMax, rng)
 	if wasHit {
-		unrotatedNormal := rayHit.NormalAtHit
-		unrotatedNormal.X = rz.cosTheta*rayHit.NormalAtHit.X - rz.sinTheta*rayHit.NormalAtHit.Y
-		unrotatedNormal.Y = rz.sinTheta*rayHit.NormalAtHit.X + rz.cosTheta*rayHit.NormalAtHit.Y
 		return &material.RayHit{
 			Ray:         ray,
-			NormalAtHit: unrotatedNormal,
+			NormalAtHit: rz.unrotate(rayHit.NormalAtHit),
+			Tangent:     rz.unrotate(rayHit.Tangent),
+			Bitangent:   rz.unrotate(rayHit.Bitangent),
 			Time:        rayHit.Time,
 			U:           rayHit.U,
 			V:           rayHit.V,
 			Material:    rayHit.Material,
+			IsClosed:    rayHit.IsClosed,
 		}, true
 	}
 	return nil, false
 }
 
+// unrotate rotates a vector from the rotated primitive's space back into world space
+func (rz *RotationZ) unrotate(v geometry.Vector) geometry.Vector {
+	unrotated := v
+	unrotated.X = rz.cosTheta*v.X - rz.sinTheta*v.Y
+	unrotated.Y = rz.sinTheta*v.X + rz.cosTheta*v.Y
+	return unrotated
+}
+
 // BoundingBox returns an AABB for this object
 func (rz *RotationZ) BoundingBox(t0, t1 float64) (*aabb.AABB, bool) {
 
@@ -116,3 +124,8 @@ func (rz *RotationZ) Copy() primitive.Primitive {
 	newRZ := *rz
 	return &newRZ
 }
+
+// SurfaceArea returns the area of the rotated primitive, which is unchanged by the rotation
+func (rz *RotationZ) SurfaceArea() (float64, bool) {
+	return primitive.SurfaceArea(rz.Primitive)
+}
@@ -0,0 +1,122 @@
+package translate
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/geometry/primitive"
+	"github.com/paulwrubel/photolum/config/geometry/primitive/aabb"
+	"github.com/paulwrubel/photolum/config/shading/material"
+)
+
+// TranslationKeyframe is the displacement of a KeyframedTranslation at a moment
+type TranslationKeyframe struct {
+	Moment       float64         `json:"moment"`
+	Displacement geometry.Vector `json:"displacement"`
+}
+
+// KeyframedTranslation is a primitive moving through the displacements of its keyframes,
+// linearly between them, and held at the first and last before and after them
+// rays see it wherever it is at the moment they were cast
+type KeyframedTranslation struct {
+	Keyframes []TranslationKeyframe `json:"keyframes"`
+	Primitive primitive.Primitive
+}
+
+// Setup sets up a KeyframedTranslation's internal fields
+func (kt *KeyframedTranslation) Setup() (*KeyframedTranslation, error) {
+	if len(kt.Keyframes) == 0 {
+		return nil, fmt.Errorf("keyframed translation has no keyframes")
+	}
+	sort.SliceStable(kt.Keyframes, func(i, j int) bool {
+		return kt.Keyframes[i].Moment < kt.Keyframes[j].Moment
+	})
+	return kt, nil
+}
+
+// displacementAt returns the displacement of the primitive at a moment
+func (kt *KeyframedTranslation) displacementAt(moment float64) geometry.Vector {
+	next := sort.Search(len(kt.Keyframes), func(i int) bool {
+		return kt.Keyframes[i].Moment > moment
+	})
+	if next == 0 {
+		return kt.Keyframes[0].Displacement
+	}
+	if next == len(kt.Keyframes) {
+		return kt.Keyframes[next-1].Displacement
+	}
+	previous := kt.Keyframes[next-1]
+	fraction := (moment - previous.Moment) / (kt.Keyframes[next].Moment - previous.Moment)
+	return previous.Displacement.MultScalar(1.0 - fraction).Add(kt.Keyframes[next].Displacement.MultScalar(fraction))
+}
+
+// Intersection computer the intersection of this object and a given ray if it exists
+func (kt *KeyframedTranslation) Intersection(ray geometry.Ray, tMin, tMax float64, rng *rand.Rand) (*material.RayHit, bool) {
+	displacement := kt.displacementAt(ray.Moment)
+
+	// translate the ray to the object
+	ray.Origin = ray.Origin.SubVector(displacement)
+
+	rh, ok := kt.Primitive.Intersection(ray, tMin, tMax, rng)
+	if ok {
+		rh.Ray.Origin = rh.Ray.Origin.AddVector(displacement)
+	}
+	return rh, ok
+}
+
+// BoundingBox returns an AABB for this object, covering everywhere it moves between moments t0 and t1
+// it moves in straight lines between keyframes, so its boxes at t0, t1, and the keyframes between cover its path
+func (kt *KeyframedTranslation) BoundingBox(t0, t1 float64) (*aabb.AABB, bool) {
+	box, ok := kt.Primitive.BoundingBox(t0, t1)
+	if !ok {
+		return nil, false
+	}
+	moments := []float64{t0, t1}
+	for _, keyframe := range kt.Keyframes {
+		if keyframe.Moment > t0 && keyframe.Moment < t1 {
+			moments = append(moments, keyframe.Moment)
+		}
+	}
+	var movedBox *aabb.AABB
+	for _, moment := range moments {
+		displacement := kt.displacementAt(moment)
+		displacedBox := &aabb.AABB{
+			A: box.A.AddVector(displacement),
+			B: box.B.AddVector(displacement),
+		}
+		if movedBox == nil {
+			movedBox = displacedBox
+		} else {
+			movedBox = aabb.SurroundingBox(movedBox, displacedBox)
+		}
+	}
+	return movedBox, true
+}
+
+// SetMaterial sets the material of this object
+func (kt *KeyframedTranslation) SetMaterial(m material.Material) {
+	kt.Primitive.SetMaterial(m)
+}
+
+// IsInfinite returns whether this object is infinite
+func (kt *KeyframedTranslation) IsInfinite() bool {
+	return kt.Primitive.IsInfinite()
+}
+
+// IsClosed returns whether this object is closed
+func (kt *KeyframedTranslation) IsClosed() bool {
+	return kt.Primitive.IsClosed()
+}
+
+// Copy returns a shallow copy of this object
+func (kt *KeyframedTranslation) Copy() primitive.Primitive {
+	newKT := *kt
+	return &newKT
+}
+
+// SurfaceArea returns the area of the translated primitive, which is unchanged by the translation
+func (kt *KeyframedTranslation) SurfaceArea() (float64, bool) {
+	return primitive.SurfaceArea(kt.Primitive)
+}
@@ -0,0 +1,66 @@
+package translate
+
+import (
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/geometry/primitive/sphere"
+)
+
+func TestKeyframedTranslationIntersection(t *testing.T) {
+	kt, err := (&KeyframedTranslation{
+		Keyframes: []TranslationKeyframe{
+			{Moment: 1.0, Displacement: geometry.Vector{X: 4.0}},
+			{Moment: 0.0, Displacement: geometry.Vector{}},
+		},
+		Primitive: sphere.Unit(0.0, 0.0, 0.0),
+	}).Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		moment   float64
+		x        float64
+		expected bool
+	}{
+		{0.0, 0.0, true},
+		{0.0, 2.0, false},
+		{0.5, 2.0, true},
+		{0.5, 0.0, false},
+		{2.0, 4.0, true},
+		{-1.0, 0.0, true},
+	}
+	for _, test := range tests {
+		r := geometry.Ray{
+			Origin:    geometry.Point{X: test.x, Z: 5.0},
+			Direction: geometry.Vector{Z: -1.0},
+			Moment:    test.moment,
+		}
+		if _, h := kt.Intersection(r, 1e-7, 1e9, nil); h != test.expected {
+			t.Errorf("Expected hit to be %t at x = %f and moment %f but got %t\n", test.expected, test.x, test.moment, h)
+		}
+	}
+}
+
+func TestKeyframedTranslationBoundingBox(t *testing.T) {
+	// there and back again, so the box covers the far keyframe within the interval
+	kt, _ := (&KeyframedTranslation{
+		Keyframes: []TranslationKeyframe{
+			{Moment: 0.0, Displacement: geometry.Vector{}},
+			{Moment: 1.0, Displacement: geometry.Vector{Y: 10.0}},
+			{Moment: 2.0, Displacement: geometry.Vector{}},
+		},
+		Primitive: sphere.Unit(0.0, 0.0, 0.0),
+	}).Setup()
+	box, ok := kt.BoundingBox(0.0, 2.0)
+	if !ok {
+		t.Fatal("Expected a bounding box")
+	}
+	if box.A.Y > -0.5 || box.B.Y < 10.5 {
+		t.Errorf("Expected the box to span y from -0.5 to 10.5 but got %v\n", box)
+	}
+	box, _ = kt.BoundingBox(0.0, 0.0)
+	if box.B.Y > 0.5+1e-6 {
+		t.Errorf("Expected the box at moment 0 to end at y = 0.5 but got %v\n", box)
+	}
+}
@@ -67,3 +67,8 @@ func (t *Translation) Copy() primitive.Primitive {
 	newT := *t
 	return &newT
 }
+
+// SurfaceArea returns the area of the translated primitive, which is unchanged by the translation
+func (t *Translation) SurfaceArea() (float64, bool) {
+	return primitive.SurfaceArea(t.Primitive)
+}
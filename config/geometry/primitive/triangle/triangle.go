@@ -19,9 +19,15 @@ type Triangle struct {
 	ANormal geometry.Vector `json:"a_normal"`
 	BNormal geometry.Vector `json:"b_normal"`
 	CNormal geometry.Vector `json:"c_normal"`
+	AUV     [2]float64      `json:"a_uv"` // texture coordinates at A
+	BUV     [2]float64      `json:"b_uv"` // texture coordinates at B
+	CUV     [2]float64      `json:"c_uv"` // texture coordinates at C
 	//normal   geometry.Vector // normal of the Triangle's surface
 	IsCulled bool `json:"is_culled"` // whether or not the Triangle is culled, or single-sided
 	mat      material.Material
+
+	tangent   geometry.Vector
+	bitangent geometry.Vector
 }
 
 // Data holds information needed to contruct a Triangle
@@ -55,6 +61,21 @@ func (t *Triangle) Setup() (*Triangle, error) {
 	} else {
 		t.CNormal = t.CNormal.Unit()
 	}
+
+	// the tangents follow the texture coordinates if there are any,
+	// or else the edge from A to B
+	ab := t.A.To(t.B)
+	ac := t.A.To(t.C)
+	du1, dv1 := t.BUV[0]-t.AUV[0], t.BUV[1]-t.AUV[1]
+	du2, dv2 := t.CUV[0]-t.AUV[0], t.CUV[1]-t.AUV[1]
+	determinant := du1*dv2 - du2*dv1
+	if determinant != 0.0 {
+		t.tangent = ab.MultScalar(dv2).Sub(ac.MultScalar(dv1)).DivScalar(determinant).Unit()
+		t.bitangent = ac.MultScalar(du1).Sub(ab.MultScalar(du2)).DivScalar(determinant).Unit()
+	} else {
+		t.tangent = ab.Unit()
+		t.bitangent = faceNormal.Cross(t.tangent)
+	}
 	return t, nil
 }
 
@@ -96,9 +117,11 @@ func (t *Triangle) Intersection(ray geometry.Ray, tMin, tMax float64, rng *rand.
 		return &material.RayHit{
 			Ray:         ray,
 			NormalAtHit: t.normalAt(barycentricAlpha, baryCentricBeta, barycentryGamma),
+			Tangent:     t.tangent,
+			Bitangent:   t.bitangent,
 			Time:        time,
-			U:           0,
-			V:           0,
+			U:           barycentricAlpha*t.AUV[0] + baryCentricBeta*t.BUV[0] + barycentryGamma*t.CUV[0],
+			V:           barycentricAlpha*t.AUV[1] + baryCentricBeta*t.BUV[1] + barycentryGamma*t.CUV[1],
 			Material:    t.mat,
 		}, true
 	}
@@ -172,3 +195,8 @@ func Unit(xOffset, yOffset, zOffset float64) *Triangle {
 	}).Setup()
 	return t
 }
+
+// SurfaceArea returns the area of the triangle
+func (t *Triangle) SurfaceArea() (float64, bool) {
+	return t.A.To(t.B).Cross(t.A.To(t.C)).Magnitude() / 2.0, true
+}
@@ -1,9 +1,12 @@
 package geometry
 
 // Ray defines elements of a parametric ray equation
+// the moment is when, within the camera's shutter interval, the ray was cast, and is kept by the rays it scatters into
+// it's separate from the time along the ray, which is the parameter of the equation
 type Ray struct {
-	Origin    Point  `json:"origin"`
-	Direction Vector `json:"direction"`
+	Origin    Point   `json:"origin"`
+	Direction Vector  `json:"direction"`
+	Moment    float64 `json:"moment"`
 }
 
 // RayZero defines the zero ray
@@ -60,6 +60,16 @@ func RandomInUnitSphere(rng *rand.Rand) Vector {
 	}
 }
 
+// RandomOnUnitSphere returns a new unit Vector pointing in a uniformly random direction
+func RandomOnUnitSphere(rng *rand.Rand) Vector {
+	for {
+		v := RandomInUnitSphere(rng)
+		if m := v.Magnitude(); m > 0.0 {
+			return v.DivScalar(m)
+		}
+	}
+}
+
 // Magnitude return euclidean length of Vector
 func (v Vector) Magnitude() float64 {
 	return math.Sqrt(v.X*v.X + v.Y*v.Y + v.Z*v.Z)
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"image"
+
+	"github.com/paulwrubel/photolum/config/filter"
 	"github.com/paulwrubel/photolum/config/shading"
 	"github.com/paulwrubel/photolum/enumeration/filetype"
 )
@@ -22,5 +25,14 @@ type Parameters struct {
 	BackgroundColor          shading.Color     // color to return when nothing is intersected
 	TMin                     float64           // minimum ray "time" to count intersection
 	TMax                     float64           // maximum ray "time" to count intersection
+	ReconstructionFilter     filter.Filter     // filter used to splat samples into neighbouring pixels
+	UseAdaptiveSampling      bool              // should the program only sample pixels whose estimated error is above a threshold?
+	AdaptiveThreshold        float64           // relative error below which a pixel is considered converged
+	AdaptiveMinimumSamples   int               // amount of samples a pixel needs before it can be considered converged
+	NoiseTarget              float64           // mean relative error at which to stop rendering early, or zero to always run every round
+	Seed                     int64             // base seed from which every random stream in the render is derived
+	Region                   image.Rectangle   // part of the image to trace, in pixels with y increasing upwards
+	IsCropped                bool              // should only the region be output, rather than the whole image?
+	BaseImage                image.Image       // image the region is composited onto, or nil to leave the rest of the image blank
 	Scene                    *Scene            // Scene reference
 }
@@ -0,0 +1,119 @@
+package sampling
+
+import "sort"
+
+// Distribution1D is a piecewise-constant probability distribution over [0, 1)
+// built from a list of non-negative weights, one per equally-sized segment
+type Distribution1D struct {
+	weights  []float64
+	cdf      []float64
+	integral float64
+}
+
+// Distribution2D is a piecewise-constant probability distribution over [0, 1)^2,
+// sampled by first choosing a row from the marginal distribution and then a column within that row
+type Distribution2D struct {
+	conditionals []*Distribution1D
+	marginal     *Distribution1D
+}
+
+// NewDistribution1D creates a distribution proportional to the given weights
+// if every weight is zero, the distribution is uniform
+func NewDistribution1D(weights []float64) *Distribution1D {
+	n := len(weights)
+	d := &Distribution1D{
+		weights: make([]float64, n),
+		cdf:     make([]float64, n+1),
+	}
+	copy(d.weights, weights)
+	for i := 0; i < n; i++ {
+		d.cdf[i+1] = d.cdf[i] + d.weights[i]/float64(n)
+	}
+	d.integral = d.cdf[n]
+	if d.integral == 0.0 {
+		for i := 1; i <= n; i++ {
+			d.cdf[i] = float64(i) / float64(n)
+		}
+	} else {
+		for i := 1; i <= n; i++ {
+			d.cdf[i] /= d.integral
+		}
+	}
+	return d
+}
+
+// Integral returns the average of the weights the distribution was built from
+func (d *Distribution1D) Integral() float64 {
+	return d.integral
+}
+
+// Sample maps a uniform value u in [0, 1) to a value in [0, 1) distributed proportionally to the weights,
+// returning that value, its probability density, and the segment it lies in
+func (d *Distribution1D) Sample(u float64) (float64, float64, int) {
+	n := len(d.weights)
+	// find the last cdf entry less than or equal to u
+	i := sort.Search(n+1, func(i int) bool { return d.cdf[i] > u }) - 1
+	if i < 0 {
+		i = 0
+	} else if i > n-1 {
+		i = n - 1
+	}
+	// find the offset within the segment
+	du := u - d.cdf[i]
+	if width := d.cdf[i+1] - d.cdf[i]; width > 0.0 {
+		du /= width
+	}
+	return (float64(i) + du) / float64(n), d.segmentPDF(i), i
+}
+
+// PDF returns the probability density of the distribution at x
+func (d *Distribution1D) PDF(x float64) float64 {
+	return d.segmentPDF(d.segment(x))
+}
+
+func (d *Distribution1D) segmentPDF(i int) float64 {
+	if d.integral == 0.0 {
+		return 1.0
+	}
+	return d.weights[i] / d.integral
+}
+
+func (d *Distribution1D) segment(x float64) int {
+	n := len(d.weights)
+	i := int(x * float64(n))
+	if i < 0 {
+		return 0
+	} else if i > n-1 {
+		return n - 1
+	}
+	return i
+}
+
+// NewDistribution2D creates a distribution proportional to a grid of weights,
+// given in rows from top to bottom, each of width entries
+func NewDistribution2D(weights []float64, width, height int) *Distribution2D {
+	d := &Distribution2D{
+		conditionals: make([]*Distribution1D, height),
+	}
+	rowIntegrals := make([]float64, height)
+	for y := 0; y < height; y++ {
+		d.conditionals[y] = NewDistribution1D(weights[y*width : (y+1)*width])
+		rowIntegrals[y] = d.conditionals[y].Integral()
+	}
+	d.marginal = NewDistribution1D(rowIntegrals)
+	return d
+}
+
+// Sample maps two uniform values in [0, 1) to a point (u, v) in [0, 1)^2,
+// returning the point and its probability density
+func (d *Distribution2D) Sample(u1, u2 float64) (float64, float64, float64) {
+	v, pdfV, row := d.marginal.Sample(u2)
+	u, pdfU, _ := d.conditionals[row].Sample(u1)
+	return u, v, pdfU * pdfV
+}
+
+// PDF returns the probability density of the distribution at (u, v)
+func (d *Distribution2D) PDF(u, v float64) float64 {
+	row := d.marginal.segment(v)
+	return d.conditionals[row].PDF(u) * d.marginal.PDF(v)
+}
@@ -0,0 +1,56 @@
+package sampling
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistribution1DSample(t *testing.T) {
+	d := NewDistribution1D([]float64{1.0, 3.0})
+	tests := []struct {
+		u        float64
+		expected float64
+		pdf      float64
+		segment  int
+	}{
+		{0.0, 0.0, 0.5, 0},
+		{0.125, 0.25, 0.5, 0},
+		{0.25, 0.5, 1.5, 1},
+		{0.625, 0.75, 1.5, 1},
+	}
+	for _, test := range tests {
+		x, pdf, segment := d.Sample(test.u)
+		if math.Abs(x-test.expected) > 1e-9 || math.Abs(pdf-test.pdf) > 1e-9 || segment != test.segment {
+			t.Errorf("Sample(%f): expected (%f, %f, %d) but got (%f, %f, %d)\n",
+				test.u, test.expected, test.pdf, test.segment, x, pdf, segment)
+		}
+		if p := d.PDF(x); math.Abs(p-pdf) > 1e-9 {
+			t.Errorf("PDF(%f): expected %f but got %f\n", x, pdf, p)
+		}
+	}
+}
+
+func TestDistribution1DZeroWeights(t *testing.T) {
+	d := NewDistribution1D([]float64{0.0, 0.0, 0.0, 0.0})
+	x, pdf, _ := d.Sample(0.3)
+	if math.Abs(x-0.3) > 1e-9 || pdf != 1.0 {
+		t.Errorf("Expected a uniform distribution but got (%f, %f)\n", x, pdf)
+	}
+}
+
+func TestDistribution2DSkipsEmptyCells(t *testing.T) {
+	// only the bottom right cell has any weight
+	d := NewDistribution2D([]float64{0.0, 0.0, 0.0, 2.0}, 2, 2)
+	for _, u := range []float64{0.0, 0.3, 0.7, 0.99} {
+		x, y, pdf := d.Sample(u, 1.0-u)
+		if x < 0.5 || y < 0.5 {
+			t.Errorf("Expected sample in the weighted cell but got (%f, %f)\n", x, y)
+		}
+		if math.Abs(pdf-4.0) > 1e-9 {
+			t.Errorf("Expected pdf 4 but got %f\n", pdf)
+		}
+	}
+	if pdf := d.PDF(0.25, 0.25); pdf != 0.0 {
+		t.Errorf("Expected pdf 0 in empty cell but got %f\n", pdf)
+	}
+}
@@ -0,0 +1,70 @@
+package sampling
+
+// Source is a small, fast pseudo-random number source (xoshiro256**)
+// It implements rand.Source64, and unlike the standard library's source
+// it is cheap enough to re-seed for every pixel
+type Source struct {
+	s0 uint64
+	s1 uint64
+	s2 uint64
+	s3 uint64
+}
+
+// NewSource returns a new Source seeded with seed
+func NewSource(seed int64) *Source {
+	src := &Source{}
+	src.Seed(seed)
+	return src
+}
+
+// Seed resets the Source's state deterministically from seed
+func (src *Source) Seed(seed int64) {
+	// the state is expanded with splitmix64, as recommended by the xoshiro authors
+	x := uint64(seed)
+	src.s0 = splitMix64(&x)
+	src.s1 = splitMix64(&x)
+	src.s2 = splitMix64(&x)
+	src.s3 = splitMix64(&x)
+}
+
+// Uint64 returns the next pseudo-random 64-bit value
+func (src *Source) Uint64() uint64 {
+	result := rotateLeft(src.s1*5, 7) * 9
+	t := src.s1 << 17
+	src.s2 ^= src.s0
+	src.s3 ^= src.s1
+	src.s1 ^= src.s2
+	src.s0 ^= src.s3
+	src.s2 ^= t
+	src.s3 = rotateLeft(src.s3, 45)
+	return result
+}
+
+// Int63 returns the next pseudo-random non-negative 63-bit value
+func (src *Source) Int63() int64 {
+	return int64(src.Uint64() >> 1)
+}
+
+// DeriveSeed deterministically combines a base seed with any number of stream indices
+// (such as a round number and a pixel index) into a new, well-distributed seed
+func DeriveSeed(base int64, indices ...int) int64 {
+	x := uint64(base)
+	h := splitMix64(&x)
+	for _, index := range indices {
+		x = h ^ uint64(index)
+		h = splitMix64(&x)
+	}
+	return int64(h)
+}
+
+func splitMix64(x *uint64) uint64 {
+	*x += 0x9E3779B97F4A7C15
+	z := *x
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return z ^ (z >> 31)
+}
+
+func rotateLeft(x uint64, k uint) uint64 {
+	return (x << k) | (x >> (64 - k))
+}
@@ -0,0 +1,56 @@
+package sampling
+
+import (
+	"math/rand"
+	"testing"
+)
+
+var sourceValue uint64
+
+func TestSourceReproducible(t *testing.T) {
+	a := rand.New(NewSource(42))
+	b := rand.New(NewSource(42))
+	for i := 0; i < 100; i++ {
+		x, y := a.Float64(), b.Float64()
+		if x != y {
+			t.Fatalf("Expected identical streams but got %f and %f at index %d\n", x, y, i)
+		}
+	}
+}
+
+func TestSourceReseed(t *testing.T) {
+	rng := rand.New(NewSource(7))
+	first := rng.Float64()
+	rng.Float64()
+	rng.Seed(7)
+	again := rng.Float64()
+	if first != again {
+		t.Errorf("Expected re-seeded stream to restart (%f) but got %f\n", first, again)
+	}
+}
+
+func TestDeriveSeedDistinctStreams(t *testing.T) {
+	seen := map[int64]bool{}
+	for round := 0; round < 4; round++ {
+		for pixel := 0; pixel < 256; pixel++ {
+			seed := DeriveSeed(1, round, pixel)
+			if seen[seed] {
+				t.Fatalf("Expected distinct seeds but got a repeat at round %d pixel %d\n", round, pixel)
+			}
+			seen[seed] = true
+		}
+	}
+	if DeriveSeed(1, 2, 3) != DeriveSeed(1, 2, 3) {
+		t.Errorf("Expected DeriveSeed to be deterministic\n")
+	}
+}
+
+func BenchmarkSourceUint64(b *testing.B) {
+	src := NewSource(1)
+	var v uint64
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v = src.Uint64()
+	}
+	sourceValue = v
+}
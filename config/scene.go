@@ -1,8 +1,17 @@
 package config
 
-import "github.com/paulwrubel/photolum/config/geometry/primitive"
+import (
+	"github.com/paulwrubel/photolum/config/environment"
+	"github.com/paulwrubel/photolum/config/geometry/primitive"
+	"github.com/paulwrubel/photolum/config/geometry/primitive/aabb"
+	"github.com/paulwrubel/photolum/config/shading/material"
+)
 
 type Scene struct {
-	Camera  *Camera             // Camera reference
-	Objects primitive.Primitive // reference to Objects in the scene
+	Camera      *Camera                     // Camera reference
+	Objects     primitive.Primitive         // reference to Objects in the scene
+	Environment environment.Environment     // light arriving from outside the scene
+	Media       []material.Medium           // volumes which dim light passing through them
+	Fog         *material.HomogeneousMedium // medium filling the space between objects, or nil for clear air
+	FogBounds   *aabb.AABB                  // space the fog fills around the bounded objects, or nil if it fills none
 }
@@ -89,6 +89,11 @@ func (c Color) ScaleDown(s float64) Color {
 	return c
 }
 
+// Luminance returns the relative luminance of a linear Color (Rec. 709 primaries)
+func (c Color) Luminance() float64 {
+	return 0.2126*c.Red + 0.7152*c.Green + 0.0722*c.Blue
+}
+
 // ToRGBA converts our Color into an RGBA representation from the color library
 func (c Color) ToRGBA() color.RGBA {
 	return color.RGBA{
@@ -0,0 +1,34 @@
+package material
+
+import (
+	"math"
+
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// Absorber is implemented by transmissive materials whose interior absorbs light
+type Absorber interface {
+	// Transmittance returns the fraction of light which survives travelling the given distance inside the material
+	Transmittance(distance float64) shading.Color
+}
+
+// Absorption describes how strongly the interior of a transmissive material absorbs light, following the Beer-Lambert law
+// it's given as the color which remains of white light after travelling a certain distance through the material
+type Absorption struct {
+	TransmittanceColor    shading.Color `json:"transmittance_color"`
+	TransmittanceDistance float64       `json:"transmittance_distance"`
+}
+
+// Transmittance returns the fraction of light which survives travelling the given distance through the material
+// a nil Absorption absorbs nothing
+func (a *Absorption) Transmittance(distance float64) shading.Color {
+	if a == nil {
+		return shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0}
+	}
+	exponent := distance / a.TransmittanceDistance
+	return shading.Color{
+		Red:   math.Pow(a.TransmittanceColor.Red, exponent),
+		Green: math.Pow(a.TransmittanceColor.Green, exponent),
+		Blue:  math.Pow(a.TransmittanceColor.Blue, exponent),
+	}
+}
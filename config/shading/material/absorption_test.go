@@ -0,0 +1,27 @@
+package material
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+func TestAbsorptionTransmittance(t *testing.T) {
+	a := &Absorption{
+		TransmittanceColor:    shading.Color{Red: 0.5, Green: 0.8, Blue: 1.0},
+		TransmittanceDistance: 2.0,
+	}
+	// at the reference distance, exactly the transmittance color survives
+	if c := a.Transmittance(2.0); c != a.TransmittanceColor {
+		t.Errorf("Expected %v but got %v\n", a.TransmittanceColor, c)
+	}
+	// twice as far through the material, the surviving light is squared
+	if c := a.Transmittance(4.0); math.Abs(c.Red-0.25) > 1e-9 || math.Abs(c.Green-0.64) > 1e-9 || c.Blue != 1.0 {
+		t.Errorf("Expected {0.25 0.64 1} but got %v\n", c)
+	}
+	var clear *Absorption
+	if c := clear.Transmittance(10.0); c != (shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0}) {
+		t.Errorf("Expected no absorption but got %v\n", c)
+	}
+}
@@ -0,0 +1,141 @@
+package material
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+// Conductor is an implementation of a Material
+// It represents a metal as a GGX microfacet surface, with a per-channel complex refractive index
+type Conductor struct {
+	*NormalMap
+	ReflectanceTexture texture.Texture `json:"-"`
+	EmittanceTexture   texture.Texture `json:"-"`
+	RoughnessU         float64         `json:"roughness_u"` // roughness along the surface's tangent
+	RoughnessV         float64         `json:"roughness_v"` // roughness along the surface's bitangent
+	Eta                shading.Color   `json:"eta"`         // real part of the refractive index
+	K                  shading.Color   `json:"k"`           // imaginary part of the refractive index (absorption)
+
+	distribution ggx
+}
+
+// ConductorPreset holds the complex refractive index of a real metal
+type ConductorPreset struct {
+	Eta shading.Color
+	K   shading.Color
+}
+
+// ConductorPresets are the refractive indices of common metals, sampled at red, green, and blue wavelengths
+var ConductorPresets = map[string]ConductorPreset{
+	"GOLD": {
+		Eta: shading.Color{Red: 0.143, Green: 0.374, Blue: 1.442},
+		K:   shading.Color{Red: 3.983, Green: 2.385, Blue: 1.603},
+	},
+	"COPPER": {
+		Eta: shading.Color{Red: 0.200, Green: 0.924, Blue: 1.102},
+		K:   shading.Color{Red: 3.912, Green: 2.452, Blue: 2.142},
+	},
+	"ALUMINIUM": {
+		Eta: shading.Color{Red: 1.657, Green: 0.880, Blue: 0.521},
+		K:   shading.Color{Red: 9.224, Green: 6.270, Blue: 4.837},
+	},
+}
+
+// Setup validates the conductor and sets up its microfacet distribution
+func (c *Conductor) Setup() (*Conductor, error) {
+	if c.RoughnessU < 0.0 || c.RoughnessU > 1.0 || c.RoughnessV < 0.0 || c.RoughnessV > 1.0 {
+		return nil, fmt.Errorf("conductor roughness is outside of the range [0, 1]")
+	}
+	if c.Eta.Red <= 0.0 || c.Eta.Green <= 0.0 || c.Eta.Blue <= 0.0 {
+		return nil, fmt.Errorf("conductor eta is 0 or negative")
+	}
+	if c.K.Red < 0.0 || c.K.Green < 0.0 || c.K.Blue < 0.0 {
+		return nil, fmt.Errorf("conductor k is negative")
+	}
+	c.distribution = newGGX(c.RoughnessU, c.RoughnessV)
+	return c, nil
+}
+
+// Reflectance returns the reflective color at texture coordinates (u, v)
+// for a conductor, this tints the color given by its refractive index
+func (c *Conductor) Reflectance(u, v float64, p geometry.Point) shading.Color {
+	return c.ReflectanceTexture.Value(u, v, p)
+}
+
+// Emittance returns the emissive color at texture coordinates (u, v)
+func (c *Conductor) Emittance(u, v float64, p geometry.Point) shading.Color {
+	return c.EmittanceTexture.Value(u, v, p)
+}
+
+// IsSpecular returns whether this material is specular in nature (vs. diffuse)
+// This is currently unused and is likely to be deprecated in the future
+func (c *Conductor) IsSpecular() bool {
+	return c.distribution.isSmooth()
+}
+
+// Scatter returns an incoming ray given a RayHit representing the outgoing ray
+// rays are reflected about a microfacet normal sampled from those visible from the outgoing direction
+func (c *Conductor) Scatter(rayHit RayHit, rng *rand.Rand) (geometry.Ray, shading.Color, bool) {
+	hitPoint := rayHit.Ray.PointAt(rayHit.Time)
+	f, wo := c.localFrame(rayHit)
+	if wo.Z <= 0.0 {
+		return geometry.RayZero, shading.ColorBlack, false
+	}
+
+	var h geometry.Vector
+	if c.distribution.isSmooth() {
+		h = geometry.Vector{X: 0.0, Y: 0.0, Z: 1.0}
+	} else {
+		h = c.distribution.sampleVisibleNormal(wo, rng.Float64(), rng.Float64())
+	}
+	wi := reflect(wo, h)
+	if wi.Z <= 0.0 {
+		return geometry.RayZero, shading.ColorBlack, false
+	}
+
+	attenuation := fresnelConductor(wo.Dot(h), c.Eta, c.K).MultColor(c.Reflectance(rayHit.U, rayHit.V, rayHit.Point()))
+	if !c.distribution.isSmooth() {
+		// sampling visible normals leaves only the shadowing of the incoming direction to account for
+		attenuation = attenuation.MultScalar(c.distribution.g2(wo, wi) / c.distribution.g1(wo))
+	}
+	return geometry.Ray{
+		Origin:    hitPoint,
+		Direction: f.toWorld(wi),
+		Moment:    rayHit.Ray.Moment,
+	}, attenuation, true
+}
+
+// Evaluate returns the BSDF times the cosine term for light arriving from direction, and its sampling density
+func (c *Conductor) Evaluate(rayHit RayHit, direction geometry.Vector) (shading.Color, float64) {
+	if c.distribution.isSmooth() {
+		// a perfect mirror only reflects in a single direction, which can't be hit by chance
+		return shading.ColorBlack, 0.0
+	}
+	f, wo := c.localFrame(rayHit)
+	wi := f.toLocal(direction.Unit())
+	if wo.Z <= 0.0 || wi.Z <= 0.0 {
+		return shading.ColorBlack, 0.0
+	}
+	h := wo.Add(wi).Unit()
+	d := c.distribution.d(h)
+	fresnel := fresnelConductor(wo.Dot(h), c.Eta, c.K).MultColor(c.Reflectance(rayHit.U, rayHit.V, rayHit.Point()))
+	// the cosine of the incoming direction cancels with the BSDF's denominator
+	bsdfCosine := fresnel.MultScalar(d * c.distribution.g2(wo, wi) / (4.0 * wo.Z))
+	return bsdfCosine, c.distribution.reflectionPDF(wo, h)
+}
+
+// localFrame returns the shading frame at the hit, facing the outgoing direction and following the surface's tangent,
+// along with the outgoing direction in local coordinates
+func (c *Conductor) localFrame(rayHit RayHit) (frame, geometry.Vector) {
+	outgoing := rayHit.Ray.Direction.Unit().Negate()
+	normal := rayHit.NormalAtHit
+	if outgoing.Dot(normal) < 0.0 {
+		normal = normal.Negate()
+	}
+	f := newTangentFrame(normal, rayHit.Tangent)
+	return f, f.toLocal(outgoing)
+}
@@ -0,0 +1,101 @@
+package material
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+func TestConductorSampleMatchesEvaluate(t *testing.T) {
+	gold := ConductorPresets["GOLD"]
+	c, err := (&Conductor{
+		ReflectanceTexture: &texture.Color{Color: shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0}},
+		EmittanceTexture:   &texture.Color{Color: shading.ColorBlack},
+		RoughnessU:         0.3,
+		RoughnessV:         0.6,
+		Eta:                gold.Eta,
+		K:                  gold.K,
+	}).Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rayHit := RayHit{
+		Ray: geometry.Ray{
+			Origin:    geometry.Point{X: -1.0, Y: 1.0, Z: 0.3},
+			Direction: geometry.Vector{X: 1.0, Y: -1.0, Z: -0.3},
+		},
+		NormalAtHit: geometry.VectorUp,
+		Time:        1.0,
+	}
+
+	rng := rand.New(rand.NewSource(0))
+	for s := 0; s < 64; s++ {
+		ray, attenuation, ok := c.Scatter(rayHit, rng)
+		if !ok {
+			continue
+		}
+		bsdfCosine, pdf := c.Evaluate(rayHit, ray.Direction)
+		if pdf <= 0.0 {
+			t.Fatalf("Expected a positive density for sampled direction %v\n", ray.Direction)
+		}
+		expected := bsdfCosine.DivScalar(pdf)
+		if math.Abs(expected.Red-attenuation.Red) > 1e-6 || math.Abs(expected.Blue-attenuation.Blue) > 1e-6 {
+			t.Errorf("Expected attenuation %v but got %v\n", expected, attenuation)
+		}
+	}
+}
+
+func TestConductorAnisotropyFollowsTangent(t *testing.T) {
+	c, err := (&Conductor{
+		ReflectanceTexture: &texture.Color{Color: shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0}},
+		EmittanceTexture:   &texture.Color{Color: shading.ColorBlack},
+		RoughnessU:         0.1,
+		RoughnessV:         0.6,
+		Eta:                shading.Color{Red: 1.5, Green: 1.5, Blue: 1.5},
+		K:                  shading.Color{Red: 3.0, Green: 3.0, Blue: 3.0},
+	}).Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a quarter turn about the normal, taking +X to -Z
+	turn := func(v geometry.Vector) geometry.Vector {
+		return geometry.Vector{X: v.Z, Y: v.Y, Z: -v.X}
+	}
+	rayHit := func(direction, tangent geometry.Vector) RayHit {
+		return RayHit{
+			Ray: geometry.Ray{
+				Origin:    geometry.Point{}.SubVector(direction),
+				Direction: direction,
+			},
+			NormalAtHit: geometry.VectorUp,
+			Tangent:     tangent,
+			Time:        1.0,
+		}
+	}
+	incoming := geometry.Vector{X: 0.3, Y: -1.0, Z: 0.1}
+	outgoing := geometry.Vector{X: 0.6, Y: 1.0, Z: 0.1}
+
+	original, _ := c.Evaluate(rayHit(incoming, geometry.VectorRight), outgoing)
+	turned, _ := c.Evaluate(rayHit(turn(incoming), turn(geometry.VectorRight)), turn(outgoing))
+	if math.Abs(original.Red-turned.Red) > 1e-9 {
+		t.Errorf("Expected turning the tangent to turn the lobe with it, but got %f and %f\n", original.Red, turned.Red)
+	}
+	// turning the directions alone samples the lobe across its other axis
+	unturned, _ := c.Evaluate(rayHit(turn(incoming), geometry.VectorRight), turn(outgoing))
+	if math.Abs(original.Red-unturned.Red) < 1e-3 {
+		t.Errorf("Expected the lobe to differ along the bitangent, but got %f both ways\n", original.Red)
+	}
+}
+
+func TestFresnelConductorAtNormalIncidence(t *testing.T) {
+	// at normal incidence, the reflectance reduces to ((n-1)^2 + k^2) / ((n+1)^2 + k^2)
+	eta, k := 0.2, 3.9
+	expected := ((eta-1)*(eta-1) + k*k) / ((eta+1)*(eta+1) + k*k)
+	if r := fresnelConductorChannel(1.0, eta, k); math.Abs(r-expected) > 1e-9 {
+		t.Errorf("Expected %f but got %f\n", expected, r)
+	}
+}
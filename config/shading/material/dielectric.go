@@ -12,19 +12,32 @@ import (
 // Dielectric is an implementation of a Material
 // It represents a partially reflective, partially transmissive material, such as glass
 type Dielectric struct {
-	ReflectanceTexture texture.Texture `json:"-"`
-	EmittanceTexture   texture.Texture `json:"-"`
-	RefractiveIndex    float64         `json:"refractive_index"`
+	*NormalMap
+	ReflectanceTexture texture.Texture    `json:"-"`
+	EmittanceTexture   texture.Texture    `json:"-"`
+	RefractiveIndex    float64            `json:"refractive_index"`
+	Absorption         *Absorption        `json:"absorption"` // absorption of the interior, or nil for clear glass
+	Medium             *HomogeneousMedium `json:"medium"`     // medium scattering light inside, or nil if it travels straight through
 }
 
 // Reflectance returns the reflective color at texture coordinates (u, v)
-func (d Dielectric) Reflectance(u, v float64) shading.Color {
-	return d.ReflectanceTexture.Value(u, v)
+func (d Dielectric) Reflectance(u, v float64, p geometry.Point) shading.Color {
+	return d.ReflectanceTexture.Value(u, v, p)
 }
 
 // Emittance returns the emissive color at texture coordinates (u, v)
-func (d Dielectric) Emittance(u, v float64) shading.Color {
-	return d.EmittanceTexture.Value(u, v)
+func (d Dielectric) Emittance(u, v float64, p geometry.Point) shading.Color {
+	return d.EmittanceTexture.Value(u, v, p)
+}
+
+// Transmittance returns the fraction of light which survives travelling the given distance inside the material
+func (d Dielectric) Transmittance(distance float64) shading.Color {
+	return d.Absorption.Transmittance(distance)
+}
+
+// ScatterInterior samples whether light travelling along ray inside the material scatters before reaching the boundary
+func (d Dielectric) ScatterInterior(ray geometry.Ray, tBoundary float64, rng *rand.Rand) (geometry.Ray, shading.Color, bool) {
+	return d.Medium.Sample(ray, tBoundary, rng)
 }
 
 // IsSpecular returns whether this material is specular in nature (vs. diffuse)
@@ -34,7 +47,7 @@ func (d Dielectric) IsSpecular() bool {
 }
 
 // Scatter returns an incoming ray given a RayHit representing the outgoing ray
-func (d Dielectric) Scatter(rayHit RayHit, rng *rand.Rand) (geometry.Ray, bool) {
+func (d Dielectric) Scatter(rayHit RayHit, rng *rand.Rand) (geometry.Ray, shading.Color, bool) {
 	hitPoint := rayHit.Ray.PointAt(rayHit.Time)
 	normal := rayHit.NormalAtHit
 	reflectionVector := rayHit.Ray.Direction.Unit().ReflectAround(normal)
@@ -62,13 +75,15 @@ func (d Dielectric) Scatter(rayHit RayHit, rng *rand.Rand) (geometry.Ray, bool)
 		return geometry.Ray{
 			Origin:    hitPoint,
 			Direction: reflectionVector,
-		}, true
+			Moment:    rayHit.Ray.Moment,
+		}, d.Reflectance(rayHit.U, rayHit.V, rayHit.Point()), true
 	}
 	// fmt.Println("refract!")
 	return geometry.Ray{
 		Origin:    hitPoint,
 		Direction: refractedVector,
-	}, true
+		Moment:    rayHit.Ray.Moment,
+	}, d.Reflectance(rayHit.U, rayHit.V, rayHit.Point()), true
 
 }
 
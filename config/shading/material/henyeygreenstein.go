@@ -0,0 +1,79 @@
+package material
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+// HenyeyGreenstein represents a volumetric material which scatters light mostly forwards or backwards
+// (clouds and fog scatter strongly forwards, giving the bright lining around a backlit cloud)
+type HenyeyGreenstein struct {
+	ReflectanceTexture texture.Texture `json:"-"`
+	EmittanceTexture   texture.Texture `json:"-"`
+	Anisotropy         float64         `json:"anisotropy"` // mean cosine of the scattering angle, from -1 (backwards) through 0 (isotropic) to 1 (forwards)
+}
+
+// Reflectance returns the reflective color at texture coordinates (u, v)
+func (hg HenyeyGreenstein) Reflectance(u, v float64, p geometry.Point) shading.Color {
+	return hg.ReflectanceTexture.Value(u, v, p)
+}
+
+// Emittance returns the emissive color at texture coordinates (u, v)
+func (hg HenyeyGreenstein) Emittance(u, v float64, p geometry.Point) shading.Color {
+	return hg.EmittanceTexture.Value(u, v, p)
+}
+
+// IsSpecular returns whether this material is specular in nature (vs. diffuse)
+// This is currently unused and is likely to be deprecated in the future
+func (hg HenyeyGreenstein) IsSpecular() bool {
+	return false
+}
+
+// Scatter returns an incoming ray given a RayHit representing the outgoing ray
+// directions are sampled exactly in proportion to the phase function, so the attenuation is only the reflectance
+func (hg HenyeyGreenstein) Scatter(rayHit RayHit, rng *rand.Rand) (geometry.Ray, shading.Color, bool) {
+	return geometry.Ray{
+		Origin:    rayHit.Ray.PointAt(rayHit.Time),
+		Direction: sampleHenyeyGreenstein(rayHit.Ray.Direction, hg.Anisotropy, rng),
+		Moment:    rayHit.Ray.Moment,
+	}, hg.Reflectance(rayHit.U, rayHit.V, rayHit.Point()), true
+}
+
+// Evaluate returns the phase function for light arriving from direction, and its sampling density
+func (hg HenyeyGreenstein) Evaluate(rayHit RayHit, direction geometry.Vector) (shading.Color, float64) {
+	cosTheta := rayHit.Ray.Direction.Unit().Dot(direction.Unit())
+	pdf := henyeyGreenstein(cosTheta, hg.Anisotropy)
+	return hg.Reflectance(rayHit.U, rayHit.V, rayHit.Point()).MultScalar(pdf), pdf
+}
+
+// sampleHenyeyGreenstein samples a direction in proportion to the Henyey-Greenstein phase function,
+// for light which was travelling in the forward direction
+func sampleHenyeyGreenstein(forward geometry.Vector, g float64, rng *rand.Rand) geometry.Vector {
+	var cosTheta float64
+	if math.Abs(g) < 1e-3 {
+		cosTheta = 1.0 - 2.0*rng.Float64()
+	} else {
+		term := (1.0 - g*g) / (1.0 - g + 2.0*g*rng.Float64())
+		cosTheta = (1.0 + g*g - term*term) / (2.0 * g)
+	}
+	cosTheta = math.Max(-1.0, math.Min(1.0, cosTheta))
+	sinTheta := math.Sqrt(math.Max(0.0, 1.0-cosTheta*cosTheta))
+	phi := 2.0 * math.Pi * rng.Float64()
+
+	// the angle is measured from the direction the light was travelling in
+	return newFrame(forward).toWorld(geometry.Vector{
+		X: sinTheta * math.Cos(phi),
+		Y: sinTheta * math.Sin(phi),
+		Z: cosTheta,
+	})
+}
+
+// henyeyGreenstein returns the Henyey-Greenstein phase function, for light turned through an angle with the given cosine
+func henyeyGreenstein(cosTheta, g float64) float64 {
+	denominator := 1.0 + g*g - 2.0*g*cosTheta
+	return (1.0 - g*g) / (4.0 * math.Pi * denominator * math.Sqrt(denominator))
+}
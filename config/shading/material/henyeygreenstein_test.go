@@ -0,0 +1,50 @@
+package material
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+func TestHenyeyGreensteinIntegratesToOne(t *testing.T) {
+	for _, g := range []float64{-0.7, 0.0, 0.3, 0.9} {
+		// integrate over the sphere, which only varies with the polar angle
+		stepCount := 100000
+		integral := 0.0
+		for i := 0; i < stepCount; i++ {
+			cosTheta := -1.0 + 2.0*(float64(i)+0.5)/float64(stepCount)
+			integral += henyeyGreenstein(cosTheta, g) * 2.0 * math.Pi * 2.0 / float64(stepCount)
+		}
+		if math.Abs(integral-1.0) > 1e-3 {
+			t.Errorf("Expected phase function with g = %f to integrate to 1 but got %f\n", g, integral)
+		}
+	}
+}
+
+func TestHenyeyGreensteinMeanCosine(t *testing.T) {
+	hg := HenyeyGreenstein{
+		ReflectanceTexture: &texture.Color{Color: shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0}},
+		Anisotropy:         0.6,
+	}
+	rayHit := RayHit{
+		Ray: geometry.Ray{
+			Origin:    geometry.Point{X: 0.0, Y: 0.0, Z: 0.0},
+			Direction: geometry.Vector{X: 0.0, Y: 0.0, Z: 2.0},
+		},
+		Time: 1.0,
+	}
+	rng := rand.New(rand.NewSource(0))
+	sampleCount := 100000
+	cosineSum := 0.0
+	for i := 0; i < sampleCount; i++ {
+		scattered, _, _ := hg.Scatter(rayHit, rng)
+		cosineSum += scattered.Direction.Unit().Z
+	}
+	if mean := cosineSum / float64(sampleCount); math.Abs(mean-hg.Anisotropy) > 0.01 {
+		t.Errorf("Expected mean scattering cosine %f but got %f\n", hg.Anisotropy, mean)
+	}
+}
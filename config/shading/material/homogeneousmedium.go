@@ -0,0 +1,91 @@
+package material
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+// InteriorScatterer is implemented by transmissive materials whose interior is filled with a scattering medium
+type InteriorScatterer interface {
+	// ScatterInterior samples whether light travelling along ray inside the material scatters before reaching
+	// the boundary at time tBoundary, returning the scattered ray if it does, and the weight of the sample either way
+	ScatterInterior(ray geometry.Ray, tBoundary float64, rng *rand.Rand) (geometry.Ray, shading.Color, bool)
+}
+
+// HomogeneousMedium is a medium of uniform density which absorbs and scatters light travelling through it,
+// like the cloudiness of milky glass or murky water, or haze filling the air
+type HomogeneousMedium struct {
+	AbsorptionCoefficient shading.Color `json:"absorption_coefficient"` // fraction of light absorbed per unit distance
+	ScatteringCoefficient shading.Color `json:"scattering_coefficient"` // fraction of light scattered per unit distance
+	Anisotropy            float64       `json:"anisotropy"`             // Henyey-Greenstein anisotropy of the scattering
+}
+
+// Sample samples whether light travelling along ray scatters before time tBoundary,
+// returning the scattered ray if it does, and the weight of the sample either way
+// distances are sampled using the mean of the channels' extinction, and the weight corrects for each channel's own
+// a nil HomogeneousMedium never scatters, and lets all light through
+func (hm *HomogeneousMedium) Sample(ray geometry.Ray, tBoundary float64, rng *rand.Rand) (geometry.Ray, shading.Color, bool) {
+	white := shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0}
+	if hm == nil {
+		return geometry.Ray{}, white, false
+	}
+	extinction := hm.AbsorptionCoefficient.Add(hm.ScatteringCoefficient)
+	meanExtinction := (extinction.Red + extinction.Green + extinction.Blue) / 3.0
+	if meanExtinction == 0.0 {
+		return geometry.Ray{}, white, false
+	}
+
+	rayMagnitude := ray.Direction.Magnitude()
+	boundaryDistance := tBoundary * rayMagnitude
+	distance := -math.Log(1.0-rng.Float64()) / meanExtinction
+	if distance >= boundaryDistance {
+		// the light passed through, which it does with probability exp(-meanExtinction * boundaryDistance)
+		return geometry.Ray{}, relativeTransmittance(extinction, meanExtinction, boundaryDistance), false
+	}
+	// the light scattered here, a distance sampled with density meanExtinction * exp(-meanExtinction * distance)
+	weight := hm.ScatteringCoefficient.
+		MultColor(relativeTransmittance(extinction, meanExtinction, distance)).
+		MultScalar(1.0 / meanExtinction)
+	return geometry.Ray{
+		Origin:    ray.PointAt(distance / rayMagnitude),
+		Direction: sampleHenyeyGreenstein(ray.Direction, hm.Anisotropy, rng),
+		Moment:    ray.Moment,
+	}, weight, true
+}
+
+// Transmittance returns the fraction of each channel of light let through over distance
+// a nil HomogeneousMedium lets all light through
+func (hm *HomogeneousMedium) Transmittance(distance float64) shading.Color {
+	if hm == nil {
+		return shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0}
+	}
+	extinction := hm.AbsorptionCoefficient.Add(hm.ScatteringCoefficient)
+	return shading.Color{
+		Red:   math.Exp(-extinction.Red * distance),
+		Green: math.Exp(-extinction.Green * distance),
+		Blue:  math.Exp(-extinction.Blue * distance),
+	}
+}
+
+// Phase returns the material describing how the medium scatters light, for sampling light sources directly
+func (hm *HomogeneousMedium) Phase() HenyeyGreenstein {
+	return HenyeyGreenstein{
+		ReflectanceTexture: &texture.Color{Color: shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0}},
+		EmittanceTexture:   &texture.Color{Color: shading.ColorBlack},
+		Anisotropy:         hm.Anisotropy,
+	}
+}
+
+// relativeTransmittance returns the transmittance of each channel over distance,
+// divided by the transmittance at the mean extinction used to sample it
+func relativeTransmittance(extinction shading.Color, meanExtinction, distance float64) shading.Color {
+	return shading.Color{
+		Red:   math.Exp(-(extinction.Red - meanExtinction) * distance),
+		Green: math.Exp(-(extinction.Green - meanExtinction) * distance),
+		Blue:  math.Exp(-(extinction.Blue - meanExtinction) * distance),
+	}
+}
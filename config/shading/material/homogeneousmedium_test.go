@@ -0,0 +1,48 @@
+package material
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// the weights of the samples which pass through should average out to each channel's own transmittance
+func TestHomogeneousMediumPassingWeightIsUnbiased(t *testing.T) {
+	hm := &HomogeneousMedium{
+		AbsorptionCoefficient: shading.Color{Red: 0.1, Green: 0.5, Blue: 1.0},
+		ScatteringCoefficient: shading.Color{Red: 0.4, Green: 0.2, Blue: 0.0},
+	}
+	r := geometry.Ray{
+		Origin:    geometry.Point{X: 0.0, Y: 0.0, Z: 0.0},
+		Direction: geometry.Vector{X: 0.0, Y: 2.0, Z: 0.0},
+	}
+	rng := rand.New(rand.NewSource(0))
+	sampleCount := 200000
+	sum := shading.ColorBlack
+	for i := 0; i < sampleCount; i++ {
+		if _, weight, scattered := hm.Sample(r, 0.5, rng); !scattered {
+			sum = sum.Add(weight)
+		}
+	}
+	mean := sum.MultScalar(1.0 / float64(sampleCount))
+	expected := shading.Color{Red: math.Exp(-0.5), Green: math.Exp(-0.7), Blue: math.Exp(-1.0)}
+	if math.Abs(mean.Red-expected.Red) > 0.01 || math.Abs(mean.Green-expected.Green) > 0.01 ||
+		math.Abs(mean.Blue-expected.Blue) > 0.01 {
+		t.Errorf("Expected mean passing weight %v but got %v\n", expected, mean)
+	}
+}
+
+func TestHomogeneousMediumNilLetsEverythingThrough(t *testing.T) {
+	var hm *HomogeneousMedium
+	r := geometry.Ray{
+		Origin:    geometry.Point{X: 0.0, Y: 0.0, Z: 0.0},
+		Direction: geometry.Vector{X: 1.0, Y: 0.0, Z: 0.0},
+	}
+	_, weight, scattered := hm.Sample(r, 10.0, rand.New(rand.NewSource(0)))
+	if scattered || weight != (shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0}) {
+		t.Errorf("Expected light to pass through unchanged but got %v, %t\n", weight, scattered)
+	}
+}
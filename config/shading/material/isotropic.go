@@ -1,6 +1,7 @@
 package material
 
 import (
+	"math"
 	"math/rand"
 
 	"github.com/paulwrubel/photolum/config/geometry"
@@ -16,13 +17,13 @@ type Isotropic struct {
 }
 
 // Reflectance returns the reflective color at texture coordinates (u, v)
-func (i Isotropic) Reflectance(u, v float64) shading.Color {
-	return i.ReflectanceTexture.Value(u, v)
+func (i Isotropic) Reflectance(u, v float64, p geometry.Point) shading.Color {
+	return i.ReflectanceTexture.Value(u, v, p)
 }
 
 // Emittance returns the emissive color at texture coordinates (u, v)
-func (i Isotropic) Emittance(u, v float64) shading.Color {
-	return i.EmittanceTexture.Value(u, v)
+func (i Isotropic) Emittance(u, v float64, p geometry.Point) shading.Color {
+	return i.EmittanceTexture.Value(u, v, p)
 }
 
 // IsSpecular returns whether this material is specular in nature (vs. diffuse)
@@ -32,11 +33,18 @@ func (i Isotropic) IsSpecular() bool {
 }
 
 // Scatter returns an incoming ray given a RayHit representing the outgoing ray
-func (i Isotropic) Scatter(rayHit RayHit, rng *rand.Rand) (geometry.Ray, bool) {
+func (i Isotropic) Scatter(rayHit RayHit, rng *rand.Rand) (geometry.Ray, shading.Color, bool) {
 	hitPoint := rayHit.Ray.PointAt(rayHit.Time)
 	direction := geometry.RandomInUnitSphere(rng)
 	return geometry.Ray{
 		Origin:    hitPoint,
 		Direction: direction,
-	}, true
+		Moment:    rayHit.Ray.Moment,
+	}, i.Reflectance(rayHit.U, rayHit.V, rayHit.Point()), true
+}
+
+// Evaluate returns the phase function for light arriving from direction, and its sampling density
+func (i Isotropic) Evaluate(rayHit RayHit, direction geometry.Vector) (shading.Color, float64) {
+	pdf := 1.0 / (4.0 * math.Pi)
+	return i.Reflectance(rayHit.U, rayHit.V, rayHit.Point()).MultScalar(pdf), pdf
 }
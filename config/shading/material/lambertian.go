@@ -1,6 +1,7 @@
 package material
 
 import (
+	"math"
 	"math/rand"
 
 	"github.com/paulwrubel/photolum/config/geometry"
@@ -11,18 +12,19 @@ import (
 // Lambertian represents an approximation to a ideally-diffuse material
 // (which is not physically accurate)
 type Lambertian struct {
+	*NormalMap
 	ReflectanceTexture texture.Texture `json:"-"`
 	EmittanceTexture   texture.Texture `json:"-"`
 }
 
 // Reflectance returns the reflective color at texture coordinates (u, v)
-func (l Lambertian) Reflectance(u, v float64) shading.Color {
-	return l.ReflectanceTexture.Value(u, v)
+func (l Lambertian) Reflectance(u, v float64, p geometry.Point) shading.Color {
+	return l.ReflectanceTexture.Value(u, v, p)
 }
 
 // Emittance returns the emissive color at texture coordinates (u, v)
-func (l Lambertian) Emittance(u, v float64) shading.Color {
-	return l.EmittanceTexture.Value(u, v)
+func (l Lambertian) Emittance(u, v float64, p geometry.Point) shading.Color {
+	return l.EmittanceTexture.Value(u, v, p)
 }
 
 // IsSpecular returns whether this material is specular in nature (vs. diffuse)
@@ -32,11 +34,23 @@ func (l Lambertian) IsSpecular() bool {
 }
 
 // Scatter returns an incoming ray given a RayHit representing the outgoing ray
-func (l Lambertian) Scatter(rayHit RayHit, rng *rand.Rand) (geometry.Ray, bool) {
+// directions are cosine-distributed about the normal
+func (l Lambertian) Scatter(rayHit RayHit, rng *rand.Rand) (geometry.Ray, shading.Color, bool) {
 	hitPoint := rayHit.Ray.PointAt(rayHit.Time)
-	target := hitPoint.AddVector(rayHit.NormalAtHit).AddVector(geometry.RandomInUnitSphere(rng))
+	target := hitPoint.AddVector(rayHit.NormalAtHit).AddVector(geometry.RandomOnUnitSphere(rng))
 	return geometry.Ray{
 		Origin:    hitPoint,
 		Direction: hitPoint.To(target),
-	}, true
+		Moment:    rayHit.Ray.Moment,
+	}, l.Reflectance(rayHit.U, rayHit.V, rayHit.Point()), true
+}
+
+// Evaluate returns the BSDF times the cosine term for light arriving from direction, and its sampling density
+func (l Lambertian) Evaluate(rayHit RayHit, direction geometry.Vector) (shading.Color, float64) {
+	cosine := rayHit.NormalAtHit.Unit().Dot(direction.Unit())
+	if cosine <= 0.0 {
+		return shading.ColorBlack, 0.0
+	}
+	pdf := cosine / math.Pi
+	return l.Reflectance(rayHit.U, rayHit.V, rayHit.Point()).MultScalar(pdf), pdf
 }
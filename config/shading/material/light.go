@@ -0,0 +1,123 @@
+package material
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+// lightFalloffSteps is the number of steps used to integrate a light's falloff over the hemisphere
+const lightFalloffSteps = 256
+
+// Light is an implementation of a Material
+// It's a purely emissive surface, which reflects nothing. Its brightness is either given directly as radiance,
+// or as the total power it emits, in which case its emittance texture only tints it
+type Light struct {
+	EmittanceTexture  texture.Texture `json:"-"`
+	Power             float64         `json:"power"`                // total emitted power, or zero to use the emittance texture as radiance
+	IsTwoSided        bool            `json:"is_two_sided"`         // whether light is emitted from the back of the surface as well as the front
+	SpotAngle         float64         `json:"spot_angle"`           // angle from the normal beyond which no light is emitted, in degrees
+	SpotBlend         float64         `json:"spot_blend"`           // fraction of the spot angle over which the light fades out
+	IsVisibleToCamera bool            `json:"is_visible_to_camera"` // whether the light itself shows up when looked at directly
+
+	cosOuter   float64
+	cosInner   float64
+	powerScale float64
+}
+
+// Setup validates the light and prepares its falloff profile
+func (l *Light) Setup() (*Light, error) {
+	if l.Power < 0.0 {
+		return nil, fmt.Errorf("light power is negative")
+	}
+	if l.SpotAngle <= 0.0 || l.SpotAngle > 90.0 {
+		return nil, fmt.Errorf("light spot angle is outside of the range (0, 90]")
+	}
+	if l.SpotBlend < 0.0 || l.SpotBlend > 1.0 {
+		return nil, fmt.Errorf("light spot blend is outside of the range [0, 1]")
+	}
+	l.cosOuter = math.Cos(l.SpotAngle * math.Pi / 180.0)
+	l.cosInner = math.Cos(l.SpotAngle * (1.0 - l.SpotBlend) * math.Pi / 180.0)
+	return l, nil
+}
+
+// SetSurfaceArea sets the area of the surface the light is attached to,
+// which is needed to spread its power over the surface
+func (l *Light) SetSurfaceArea(area float64) error {
+	if area <= 0.0 {
+		return fmt.Errorf("light surface area is zero or negative")
+	}
+	// each side emits the radiance times the integral of its projected falloff over the hemisphere
+	projectedSolidAngle := 0.0
+	step := (math.Pi / 2.0) / lightFalloffSteps
+	for i := 0; i < lightFalloffSteps; i++ {
+		theta := (float64(i) + 0.5) * step
+		projectedSolidAngle += l.falloff(math.Cos(theta)) * math.Cos(theta) * math.Sin(theta) * step
+	}
+	projectedSolidAngle *= 2.0 * math.Pi
+	sides := 1.0
+	if l.IsTwoSided {
+		sides = 2.0
+	}
+	l.powerScale = l.Power / (area * sides * projectedSolidAngle)
+	return nil
+}
+
+// Reflectance returns the reflective color at texture coordinates (u, v)
+// lights reflect no light at all
+func (l *Light) Reflectance(u, v float64, p geometry.Point) shading.Color {
+	return shading.ColorBlack
+}
+
+// Emittance returns the emissive color at texture coordinates (u, v), straight out from the front of the surface
+func (l *Light) Emittance(u, v float64, p geometry.Point) shading.Color {
+	color := l.EmittanceTexture.Value(u, v, p)
+	if l.Power == 0.0 {
+		return color
+	}
+	luminance := color.Luminance()
+	if luminance <= 0.0 {
+		return shading.ColorBlack
+	}
+	return color.MultScalar(l.powerScale / luminance)
+}
+
+// EmittanceToward returns the emissive color towards the origin of the ray which hit the light
+func (l *Light) EmittanceToward(rayHit RayHit) shading.Color {
+	cosine := rayHit.Ray.Direction.Unit().Negate().Dot(rayHit.NormalAtHit.Unit())
+	if cosine < 0.0 {
+		if !l.IsTwoSided {
+			return shading.ColorBlack
+		}
+		cosine = -cosine
+	}
+	return l.Emittance(rayHit.U, rayHit.V, rayHit.Point()).MultScalar(l.falloff(cosine))
+}
+
+// IsSpecular returns whether this material is specular in nature (vs. diffuse)
+// This is currently unused and is likely to be deprecated in the future
+func (l *Light) IsSpecular() bool {
+	return false
+}
+
+// Scatter returns an incoming ray given a RayHit representing the outgoing ray
+// lights never scatter
+func (l *Light) Scatter(rayHit RayHit, rng *rand.Rand) (geometry.Ray, shading.Color, bool) {
+	return geometry.RayZero, shading.ColorBlack, false
+}
+
+// falloff returns the fraction of the light's radiance emitted at an angle with the given cosine to the normal
+func (l *Light) falloff(cosine float64) float64 {
+	if cosine >= l.cosInner {
+		return 1.0
+	}
+	if cosine <= l.cosOuter {
+		return 0.0
+	}
+	t := (cosine - l.cosOuter) / (l.cosInner - l.cosOuter)
+	return t * t * (3.0 - 2.0*t)
+}
@@ -0,0 +1,29 @@
+package material
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+func TestLightPowerWithoutFalloff(t *testing.T) {
+	l, err := (&Light{
+		EmittanceTexture: &texture.Color{Color: shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0}},
+		Power:            100.0,
+		SpotAngle:        90.0,
+	}).Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.SetSurfaceArea(2.0); err != nil {
+		t.Fatal(err)
+	}
+	// a diffuse emitter of radiance L and area A emits a power of L * A * pi
+	expected := 100.0 / (2.0 * math.Pi)
+	if radiance := l.Emittance(0.0, 0.0, geometry.Point{}).Luminance(); math.Abs(radiance-expected) > 1e-3*expected {
+		t.Errorf("Expected radiance %f but got %f\n", expected, radiance)
+	}
+}
@@ -8,19 +8,38 @@ import (
 )
 
 // Material described the implementation of a surface material
+// Reflectance and Emittance are given both the texture coordinates and the world position of the point being shaded
+// Scatter returns the incoming ray along with the attenuation of the light travelling along it
 type Material interface {
-	Reflectance(u, v float64) shading.Color
-	Emittance(u, v float64) shading.Color
+	Reflectance(u, v float64, p geometry.Point) shading.Color
+	Emittance(u, v float64, p geometry.Point) shading.Color
 	IsSpecular() bool
-	Scatter(RayHit, *rand.Rand) (geometry.Ray, bool)
+	Scatter(RayHit, *rand.Rand) (geometry.Ray, shading.Color, bool)
+}
+
+// Evaluator is implemented by materials which aren't purely specular
+// It lets the tracer sample light sources directly and weigh the result against the material's own sampling
+type Evaluator interface {
+	// Evaluate returns the BSDF times the cosine term for light arriving from the given direction,
+	// and the probability density with which Scatter would have chosen that direction
+	Evaluate(rayHit RayHit, direction geometry.Vector) (shading.Color, float64)
 }
 
 // RayHit is a loose gathering of information about a ray's intersection with a surface
 type RayHit struct {
 	Ray         geometry.Ray
 	NormalAtHit geometry.Vector
+	Tangent     geometry.Vector // direction in which U increases, or zero if the surface has no tangent frame
+	Bitangent   geometry.Vector // direction in which V increases, or zero if the surface has no tangent frame
 	Time        float64
 	U           float64 // texture coordinate U
 	V           float64 // texture coordinate V
 	Material    Material
+	Medium      Medium // volume the hit is a collision inside of, or nil if the hit is on a surface
+	IsClosed    bool   // whether the surface hit encloses a volume, so that hits on its back are from inside it
+}
+
+// Point returns the position of the hit in world space, for textures which vary through space
+func (rh RayHit) Point() geometry.Point {
+	return rh.Ray.PointAt(rh.Time)
 }
@@ -0,0 +1,14 @@
+package material
+
+import (
+	"math/rand"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+)
+
+// Medium is implemented by volumes which light passes partly through, scattering off the particles inside
+type Medium interface {
+	// Transmittance estimates the fraction of light travelling along ray between tMin and tMax
+	// which passes through the volume without colliding with anything
+	Transmittance(ray geometry.Ray, tMin, tMax float64, rng *rand.Rand) float64
+}
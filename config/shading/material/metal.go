@@ -11,19 +11,20 @@ import (
 // Metal is an implementation of a Material
 // It represents a perfect or near-perfect specularly reflective material
 type Metal struct {
+	*NormalMap
 	ReflectanceTexture texture.Texture `json:"-"`
 	EmittanceTexture   texture.Texture `json:"-"`
 	Fuzziness          float64         `json:"fuzziness"`
 }
 
 // Reflectance returns the reflective color at texture coordinates (u, v)
-func (m Metal) Reflectance(u, v float64) shading.Color {
-	return m.ReflectanceTexture.Value(u, v)
+func (m Metal) Reflectance(u, v float64, p geometry.Point) shading.Color {
+	return m.ReflectanceTexture.Value(u, v, p)
 }
 
 // Emittance returns the emissive color at texture coordinates (u, v)
-func (m Metal) Emittance(u, v float64) shading.Color {
-	return m.EmittanceTexture.Value(u, v)
+func (m Metal) Emittance(u, v float64, p geometry.Point) shading.Color {
+	return m.EmittanceTexture.Value(u, v, p)
 }
 
 // IsSpecular returns whether this material is specular in nature (vs. diffuse)
@@ -33,7 +34,7 @@ func (m Metal) IsSpecular() bool {
 }
 
 // Scatter returns an incoming ray given a RayHit representing the outgoing ray
-func (m Metal) Scatter(rayHit RayHit, rng *rand.Rand) (geometry.Ray, bool) {
+func (m Metal) Scatter(rayHit RayHit, rng *rand.Rand) (geometry.Ray, shading.Color, bool) {
 	hitPoint := rayHit.Ray.PointAt(rayHit.Time)
 	normal := rayHit.NormalAtHit
 
@@ -43,7 +44,8 @@ func (m Metal) Scatter(rayHit RayHit, rng *rand.Rand) (geometry.Ray, bool) {
 		return geometry.Ray{
 			Origin:    hitPoint,
 			Direction: reflectionVector,
-		}, true
+			Moment:    rayHit.Ray.Moment,
+		}, m.Reflectance(rayHit.U, rayHit.V, rayHit.Point()), true
 	}
-	return geometry.RayZero, false
+	return geometry.RayZero, shading.ColorBlack, false
 }
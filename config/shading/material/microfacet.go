@@ -0,0 +1,200 @@
+package material
+
+import (
+	"math"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// minimumAlpha is the GGX width below which a surface is treated as perfectly smooth
+const minimumAlpha = 1e-3
+
+// frame is an orthonormal basis around a surface normal, used to express directions in local coordinates
+// where the normal is the Z axis
+type frame struct {
+	tangent   geometry.Vector
+	bitangent geometry.Vector
+	normal    geometry.Vector
+}
+
+// newFrame creates a basis around the normal
+// the tangent is arbitrary, but consistent for a given normal
+func newFrame(normal geometry.Vector) frame {
+	n := normal.Unit()
+	var tangent geometry.Vector
+	if math.Abs(n.X) > 0.9 {
+		tangent = geometry.VectorUp.Cross(n).Unit()
+	} else {
+		tangent = geometry.VectorRight.Cross(n).Unit()
+	}
+	return frame{
+		tangent:   tangent,
+		bitangent: n.Cross(tangent),
+		normal:    n,
+	}
+}
+
+// newTangentFrame creates a basis around the normal, with its tangent along the surface's tangent
+// made perpendicular to the normal, falling back to an arbitrary tangent where the surface has none
+func newTangentFrame(normal, tangent geometry.Vector) frame {
+	n := normal.Unit()
+	t := tangent.Sub(n.MultScalar(n.Dot(tangent)))
+	if tangent == geometry.VectorZero || t.Magnitude() < 1e-9 {
+		return newFrame(n)
+	}
+	t = t.Unit()
+	return frame{
+		tangent:   t,
+		bitangent: n.Cross(t),
+		normal:    n,
+	}
+}
+
+func (f frame) toLocal(v geometry.Vector) geometry.Vector {
+	return geometry.Vector{
+		X: v.Dot(f.tangent),
+		Y: v.Dot(f.bitangent),
+		Z: v.Dot(f.normal),
+	}
+}
+
+func (f frame) toWorld(v geometry.Vector) geometry.Vector {
+	return f.tangent.MultScalar(v.X).Add(f.bitangent.MultScalar(v.Y)).Add(f.normal.MultScalar(v.Z))
+}
+
+// ggx is an anisotropic GGX (Trowbridge-Reitz) microfacet distribution in local coordinates
+type ggx struct {
+	alphaX float64
+	alphaY float64
+}
+
+// newGGX creates a distribution from perceptual roughness values in [0, 1]
+func newGGX(roughnessX, roughnessY float64) ggx {
+	return ggx{
+		alphaX: math.Max(roughnessX*roughnessX, 1e-4),
+		alphaY: math.Max(roughnessY*roughnessY, 1e-4),
+	}
+}
+
+func (g ggx) isSmooth() bool {
+	return math.Max(g.alphaX, g.alphaY) < minimumAlpha
+}
+
+// d returns the density of microfacet normals around the half vector h
+func (g ggx) d(h geometry.Vector) float64 {
+	if h.Z <= 0.0 {
+		return 0.0
+	}
+	e := (h.X*h.X)/(g.alphaX*g.alphaX) + (h.Y*h.Y)/(g.alphaY*g.alphaY) + h.Z*h.Z
+	return 1.0 / (math.Pi * g.alphaX * g.alphaY * e * e)
+}
+
+// lambda is the Smith auxiliary function for direction w
+func (g ggx) lambda(w geometry.Vector) float64 {
+	if w.Z == 0.0 {
+		return math.Inf(1)
+	}
+	tan2 := (g.alphaX*g.alphaX*w.X*w.X + g.alphaY*g.alphaY*w.Y*w.Y) / (w.Z * w.Z)
+	return (-1.0 + math.Sqrt(1.0+tan2)) / 2.0
+}
+
+// g1 returns the Smith masking term for a single direction
+func (g ggx) g1(w geometry.Vector) float64 {
+	return 1.0 / (1.0 + g.lambda(w))
+}
+
+// g2 returns the height-correlated Smith masking-shadowing term for a pair of directions
+func (g ggx) g2(wo, wi geometry.Vector) float64 {
+	return 1.0 / (1.0 + g.lambda(wo) + g.lambda(wi))
+}
+
+// sampleVisibleNormal samples a microfacet normal from the distribution of normals visible from wo
+// following Heitz, "Sampling the GGX Distribution of Visible Normals" (2018)
+func (g ggx) sampleVisibleNormal(wo geometry.Vector, u1, u2 float64) geometry.Vector {
+	// stretch the view direction to the hemisphere configuration
+	vh := geometry.Vector{X: g.alphaX * wo.X, Y: g.alphaY * wo.Y, Z: wo.Z}.Unit()
+	lengthSquared := vh.X*vh.X + vh.Y*vh.Y
+	t1 := geometry.Vector{X: 1.0, Y: 0.0, Z: 0.0}
+	if lengthSquared > 0.0 {
+		t1 = geometry.Vector{X: -vh.Y, Y: vh.X, Z: 0.0}.DivScalar(math.Sqrt(lengthSquared))
+	}
+	t2 := vh.Cross(t1)
+	// sample the projected area
+	r := math.Sqrt(u1)
+	phi := 2.0 * math.Pi * u2
+	p1 := r * math.Cos(phi)
+	p2 := r * math.Sin(phi)
+	s := 0.5 * (1.0 + vh.Z)
+	p2 = (1.0-s)*math.Sqrt(1.0-p1*p1) + s*p2
+	nh := t1.MultScalar(p1).Add(t2.MultScalar(p2)).Add(vh.MultScalar(math.Sqrt(math.Max(0.0, 1.0-p1*p1-p2*p2))))
+	// and unstretch
+	return geometry.Vector{X: g.alphaX * nh.X, Y: g.alphaY * nh.Y, Z: math.Max(1e-6, nh.Z)}.Unit()
+}
+
+// reflectionPDF returns the density with which sampleVisibleNormal, followed by a reflection about the normal,
+// chooses the direction wi
+func (g ggx) reflectionPDF(wo, h geometry.Vector) float64 {
+	return g.g1(wo) * g.d(h) / (4.0 * wo.Z)
+}
+
+// reflect reflects w about the normal n, with both pointing away from the surface
+func reflect(w, n geometry.Vector) geometry.Vector {
+	return n.MultScalar(2.0 * w.Dot(n)).Sub(w)
+}
+
+// refract refracts w through the microfacet normal n, with both pointing away from the surface
+// eta is the ratio of the refractive index on w's side to that on the other side
+// it reports false on total internal reflection
+func refract(w, n geometry.Vector, eta float64) (geometry.Vector, bool) {
+	cosI := w.Dot(n)
+	sin2T := eta * eta * math.Max(0.0, 1.0-cosI*cosI)
+	if sin2T >= 1.0 {
+		return geometry.VectorZero, false
+	}
+	cosT := math.Sqrt(1.0 - sin2T)
+	return w.Negate().MultScalar(eta).Add(n.MultScalar(eta*cosI - cosT)), true
+}
+
+// fresnelDielectric returns the exact reflectance of unpolarized light at the boundary of two dielectrics
+// arriving at an angle with the given cosine to the normal, from the side with refractive index etaI
+func fresnelDielectric(cosI, etaI, etaT float64) float64 {
+	cosI = math.Max(0.0, math.Min(1.0, cosI))
+	sinT := etaI / etaT * math.Sqrt(math.Max(0.0, 1.0-cosI*cosI))
+	if sinT >= 1.0 {
+		return 1.0
+	}
+	cosT := math.Sqrt(math.Max(0.0, 1.0-sinT*sinT))
+	parallel := (etaT*cosI - etaI*cosT) / (etaT*cosI + etaI*cosT)
+	perpendicular := (etaI*cosI - etaT*cosT) / (etaI*cosI + etaT*cosT)
+	return (parallel*parallel + perpendicular*perpendicular) / 2.0
+}
+
+// fresnelConductor returns the reflectance of a conductor with complex refractive index eta + ik, per channel,
+// for light arriving at an angle with the given cosine to the normal
+func fresnelConductor(cosine float64, eta, k shading.Color) shading.Color {
+	return shading.Color{
+		Red:   fresnelConductorChannel(cosine, eta.Red, k.Red),
+		Green: fresnelConductorChannel(cosine, eta.Green, k.Green),
+		Blue:  fresnelConductorChannel(cosine, eta.Blue, k.Blue),
+	}
+}
+
+func fresnelConductorChannel(cosine, eta, k float64) float64 {
+	cos2 := math.Min(1.0, cosine*cosine)
+	sin2 := 1.0 - cos2
+	eta2 := eta * eta
+	k2 := k * k
+
+	t0 := eta2 - k2 - sin2
+	a2PlusB2 := math.Sqrt(t0*t0 + 4.0*eta2*k2)
+	t1 := a2PlusB2 + cos2
+	a := math.Sqrt(math.Max(0.0, 0.5*(a2PlusB2+t0)))
+	t2 := 2.0 * cosine * a
+	rs := (t1 - t2) / (t1 + t2)
+
+	t3 := cos2*a2PlusB2 + sin2*sin2
+	t4 := t2 * sin2
+	rp := rs * (t3 - t4) / (t3 + t4)
+	return 0.5 * (rp + rs)
+}
@@ -0,0 +1,61 @@
+package material
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+// Mix is an implementation of a Material
+// It blends two other materials by choosing one of them at random at each hit, weighted by a texture
+type Mix struct {
+	First         Material        `json:"-"`
+	Second        Material        `json:"-"`
+	WeightTexture texture.Texture `json:"-"` // chance of choosing the second material, given by the texture's luminance
+}
+
+// Choose picks one of the mixed materials at texture coordinates (u, v)
+// nested mixes are chosen through as well, so the result is never itself a Mix
+func (m *Mix) Choose(u, v float64, p geometry.Point, rng *rand.Rand) Material {
+	chosen := m.First
+	if rng.Float64() < m.weight(u, v, p) {
+		chosen = m.Second
+	}
+	if mix, ok := chosen.(*Mix); ok {
+		return mix.Choose(u, v, p, rng)
+	}
+	return chosen
+}
+
+// Reflectance returns the reflective color at texture coordinates (u, v)
+// this is the blend of both materials' reflectances
+func (m *Mix) Reflectance(u, v float64, p geometry.Point) shading.Color {
+	w := m.weight(u, v, p)
+	return m.First.Reflectance(u, v, p).MultScalar(1.0 - w).Add(m.Second.Reflectance(u, v, p).MultScalar(w))
+}
+
+// Emittance returns the emissive color at texture coordinates (u, v)
+// this is the blend of both materials' emittances
+func (m *Mix) Emittance(u, v float64, p geometry.Point) shading.Color {
+	w := m.weight(u, v, p)
+	return m.First.Emittance(u, v, p).MultScalar(1.0 - w).Add(m.Second.Emittance(u, v, p).MultScalar(w))
+}
+
+// IsSpecular returns whether this material is specular in nature (vs. diffuse)
+// This is currently unused and is likely to be deprecated in the future
+func (m *Mix) IsSpecular() bool {
+	return m.First.IsSpecular() && m.Second.IsSpecular()
+}
+
+// Scatter returns an incoming ray given a RayHit representing the outgoing ray
+// one of the mixed materials is chosen, and scatters the ray itself
+func (m *Mix) Scatter(rayHit RayHit, rng *rand.Rand) (geometry.Ray, shading.Color, bool) {
+	return m.Choose(rayHit.U, rayHit.V, rayHit.Point(), rng).Scatter(rayHit, rng)
+}
+
+func (m *Mix) weight(u, v float64, p geometry.Point) float64 {
+	return math.Max(0.0, math.Min(1.0, m.WeightTexture.Value(u, v, p).Luminance()))
+}
@@ -0,0 +1,29 @@
+package material
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+func TestMixChoosesThroughNestedMixes(t *testing.T) {
+	first := Lambertian{}
+	second := Metal{}
+	gray := func(value float64) texture.Texture {
+		return &texture.Color{Color: shading.Color{Red: value, Green: value, Blue: value}}
+	}
+	inner := &Mix{First: first, Second: second, WeightTexture: gray(1.0)}
+	outer := &Mix{First: first, Second: inner, WeightTexture: gray(1.0)}
+
+	rng := rand.New(rand.NewSource(0))
+	if chosen := outer.Choose(0.0, 0.0, geometry.Point{}, rng); chosen != Material(second) {
+		t.Errorf("Expected the nested mix's second material but got %v\n", chosen)
+	}
+	outer.WeightTexture = gray(0.0)
+	if chosen := outer.Choose(0.0, 0.0, geometry.Point{}, rng); chosen != Material(first) {
+		t.Errorf("Expected the first material but got %v\n", chosen)
+	}
+}
@@ -0,0 +1,57 @@
+package material
+
+import (
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+// bumpDelta is the step in texture coordinates used to find the slope of a bump map
+const bumpDelta = 1.0 / 1024.0
+
+// NormalMapper is implemented by materials which may shade with a normal other than the surface's own
+type NormalMapper interface {
+	// ShadingNormal returns the normal to shade the hit with
+	ShadingNormal(rayHit RayHit) geometry.Vector
+}
+
+// NormalMap perturbs the normal a surface is shaded with, to give it detail finer than its geometry
+// it's embedded in surface materials, where a nil NormalMap leaves the normal as it is
+type NormalMap struct {
+	NormalTexture texture.Texture `json:"-"`             // tangent-space normals, with each axis mapped from [-1, 1] to a color channel in [0, 1]
+	BumpTexture   texture.Texture `json:"-"`             // heights, given by the texture's luminance
+	BumpStrength  float64         `json:"bump_strength"` // amount to scale the slope of the heights by
+}
+
+// ShadingNormal returns the normal to shade the hit with
+// surfaces without a tangent frame can't be perturbed, and keep their geometric normal
+func (nm *NormalMap) ShadingNormal(rayHit RayHit) geometry.Vector {
+	normal := rayHit.NormalAtHit.Unit()
+	if nm == nil || rayHit.Tangent == geometry.VectorZero || rayHit.Bitangent == geometry.VectorZero {
+		return normal
+	}
+	// make the tangent frame orthonormal around the normal, keeping its handedness
+	tangent := rayHit.Tangent.Sub(normal.MultScalar(normal.Dot(rayHit.Tangent)))
+	if tangent.Magnitude() == 0.0 {
+		return normal
+	}
+	tangent = tangent.Unit()
+	bitangent := normal.Cross(tangent)
+	if bitangent.Dot(rayHit.Bitangent) < 0.0 {
+		bitangent = bitangent.Negate()
+	}
+
+	if nm.NormalTexture != nil {
+		c := nm.NormalTexture.Value(rayHit.U, rayHit.V, rayHit.Point())
+		normal = tangent.MultScalar(2.0*c.Red - 1.0).Add(
+			bitangent.MultScalar(2.0*c.Green - 1.0)).Add(
+			normal.MultScalar(2.0*c.Blue - 1.0)).Unit()
+	}
+	if nm.BumpTexture != nil {
+		height := nm.BumpTexture.Value(rayHit.U, rayHit.V, rayHit.Point()).Luminance()
+		slopeU := (nm.BumpTexture.Value(rayHit.U+bumpDelta, rayHit.V, rayHit.Point()).Luminance() - height) / bumpDelta
+		slopeV := (nm.BumpTexture.Value(rayHit.U, rayHit.V+bumpDelta, rayHit.Point()).Luminance() - height) / bumpDelta
+		normal = normal.Sub(tangent.MultScalar(nm.BumpStrength * slopeU)).Sub(
+			bitangent.MultScalar(nm.BumpStrength * slopeV)).Unit()
+	}
+	return normal
+}
@@ -0,0 +1,36 @@
+package material
+
+import (
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+func TestNormalMapFlatNormalIsUnchanged(t *testing.T) {
+	rayHit := RayHit{
+		NormalAtHit: geometry.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+		Tangent:     geometry.Vector{X: 1.0, Y: 0.0, Z: 0.0},
+		Bitangent:   geometry.Vector{X: 0.0, Y: 0.0, Z: -1.0},
+	}
+	nm := &NormalMap{
+		NormalTexture: &texture.Color{Color: shading.Color{Red: 0.5, Green: 0.5, Blue: 1.0}},
+		BumpTexture:   &texture.Color{Color: shading.Color{Red: 0.3, Green: 0.3, Blue: 0.3}},
+		BumpStrength:  1.0,
+	}
+	if n := nm.ShadingNormal(rayHit); n.Sub(rayHit.NormalAtHit).Magnitude() > 1e-9 {
+		t.Errorf("Expected %v but got %v\n", rayHit.NormalAtHit, n)
+	}
+
+	// tilting the mapped normal towards the tangent tilts the shading normal along it
+	nm.NormalTexture = &texture.Color{Color: shading.Color{Red: 1.0, Green: 0.5, Blue: 0.5}}
+	if n := nm.ShadingNormal(rayHit); n.Sub(rayHit.Tangent).Magnitude() > 1e-9 {
+		t.Errorf("Expected %v but got %v\n", rayHit.Tangent, n)
+	}
+
+	var unmapped *NormalMap
+	if n := unmapped.ShadingNormal(rayHit); n != rayHit.NormalAtHit {
+		t.Errorf("Expected %v but got %v\n", rayHit.NormalAtHit, n)
+	}
+}
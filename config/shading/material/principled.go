@@ -0,0 +1,300 @@
+package material
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+// principledMinimumRoughness keeps the specular lobes wide enough to be evaluated,
+// so that every lobe can take part in light sampling
+const principledMinimumRoughness = 0.05
+
+// principledClearcoatRoughness is the fixed roughness of the clearcoat layer
+const principledClearcoatRoughness = 0.1
+
+// Principled is an implementation of a Material
+// It's an "uber" material loosely following Burley, "Physically-Based Shading at Disney" (2012),
+// layering a clearcoat over a mix of diffuse, sheen, specular, metallic, and transmissive lobes.
+// All scalar parameters are in [0, 1], and are given by the luminance of their textures
+type Principled struct {
+	*NormalMap
+	BaseColorTexture    texture.Texture `json:"-"`
+	MetallicTexture     texture.Texture `json:"-"`
+	RoughnessTexture    texture.Texture `json:"-"`
+	SpecularTexture     texture.Texture `json:"-"` // 0.5 corresponds to a refractive index of 1.5
+	ClearcoatTexture    texture.Texture `json:"-"`
+	SheenTexture        texture.Texture `json:"-"`
+	TransmissionTexture texture.Texture `json:"-"`
+	EmittanceTexture    texture.Texture `json:"-"`
+	RefractiveIndex     float64         `json:"refractive_index"` // refractive index of the transmissive lobe
+}
+
+// principledLobes holds the parameters of a Principled material at a single point, along with the weights
+// of its lobes and the probabilities with which each is sampled
+type principledLobes struct {
+	baseColor    shading.Color
+	roughness    float64
+	metallic     float64
+	specular     float64
+	clearcoat    float64
+	sheen        float64
+	transmission float64
+
+	base ggx
+	coat ggx
+
+	diffuseWeight      float64
+	transmissionWeight float64
+
+	pDiffuse      float64
+	pSpecular     float64
+	pClearcoat    float64
+	pTransmission float64
+}
+
+// Setup validates the principled material
+func (p *Principled) Setup() (*Principled, error) {
+	if p.BaseColorTexture == nil || p.MetallicTexture == nil || p.RoughnessTexture == nil ||
+		p.SpecularTexture == nil || p.ClearcoatTexture == nil || p.SheenTexture == nil ||
+		p.TransmissionTexture == nil || p.EmittanceTexture == nil {
+		return nil, fmt.Errorf("principled material is missing a texture")
+	}
+	if p.RefractiveIndex <= 1.0 {
+		return nil, fmt.Errorf("principled refractive index is 1 or less")
+	}
+	return p, nil
+}
+
+// Reflectance returns the reflective color at texture coordinates (u, v)
+// for a principled material, this is its base color together with the reflectance of its specular layers,
+// which are seen even on a black base
+func (p *Principled) Reflectance(u, v float64, point geometry.Point) shading.Color {
+	l := p.lobes(u, v, point)
+	specular := (1.0-l.metallic)*0.08*l.specular + 0.04*l.clearcoat
+	return l.baseColor.Add(shading.Color{Red: specular, Green: specular, Blue: specular})
+}
+
+// Emittance returns the emissive color at texture coordinates (u, v)
+func (p *Principled) Emittance(u, v float64, point geometry.Point) shading.Color {
+	return p.EmittanceTexture.Value(u, v, point)
+}
+
+// IsSpecular returns whether this material is specular in nature (vs. diffuse)
+// This is currently unused and is likely to be deprecated in the future
+func (p *Principled) IsSpecular() bool {
+	return false
+}
+
+// IsTransmissive returns whether any light can be transmitted through the material
+func (p *Principled) IsTransmissive() bool {
+	if c, ok := p.TransmissionTexture.(*texture.Color); ok {
+		return c.Color.Luminance() > 0.0
+	}
+	return true
+}
+
+// Scatter returns an incoming ray given a RayHit representing the outgoing ray
+// one of the lobes is chosen at random, and the direction it samples is then weighed against all of them
+func (p *Principled) Scatter(rayHit RayHit, rng *rand.Rand) (geometry.Ray, shading.Color, bool) {
+	hitPoint := rayHit.Ray.PointAt(rayHit.Time)
+	l := p.lobes(rayHit.U, rayHit.V, rayHit.Point())
+	outgoing := rayHit.Ray.Direction.Unit().Negate()
+
+	// rays leaving the object have been transmitted into it, and only see the boundary of the transmissive lobe
+	if outgoing.Dot(rayHit.NormalAtHit) < 0.0 {
+		f := newFrame(rayHit.NormalAtHit.Negate())
+		wo := f.toLocal(outgoing)
+		h := l.base.sampleVisibleNormal(wo, rng.Float64(), rng.Float64())
+		var wi geometry.Vector
+		if rng.Float64() < fresnelDielectric(wo.Dot(h), p.RefractiveIndex, 1.0) {
+			wi = reflect(wo, h)
+			if wi.Z <= 0.0 {
+				return geometry.RayZero, shading.ColorBlack, false
+			}
+		} else {
+			var ok bool
+			wi, ok = refract(wo, h, p.RefractiveIndex)
+			if !ok || wi.Z >= 0.0 {
+				return geometry.RayZero, shading.ColorBlack, false
+			}
+		}
+		weight := l.base.g2(wo, wi) / l.base.g1(wo)
+		return geometry.Ray{
+			Origin:    hitPoint,
+			Direction: f.toWorld(wi),
+			Moment:    rayHit.Ray.Moment,
+		}, shading.Color{Red: weight, Green: weight, Blue: weight}, true
+	}
+
+	f := newFrame(rayHit.NormalAtHit)
+	wo := f.toLocal(outgoing)
+	if wo.Z <= 0.0 {
+		return geometry.RayZero, shading.ColorBlack, false
+	}
+
+	var wi geometry.Vector
+	choice := rng.Float64()
+	switch {
+	case choice < l.pDiffuse:
+		wi = geometry.Vector{X: 0.0, Y: 0.0, Z: 1.0}.Add(geometry.RandomOnUnitSphere(rng))
+		if wi.Magnitude() == 0.0 {
+			return geometry.RayZero, shading.ColorBlack, false
+		}
+		wi = wi.Unit()
+	case choice < l.pDiffuse+l.pSpecular:
+		wi = reflect(wo, l.base.sampleVisibleNormal(wo, rng.Float64(), rng.Float64()))
+	case choice < l.pDiffuse+l.pSpecular+l.pClearcoat:
+		wi = reflect(wo, l.coat.sampleVisibleNormal(wo, rng.Float64(), rng.Float64()))
+	default:
+		h := l.base.sampleVisibleNormal(wo, rng.Float64(), rng.Float64())
+		if rng.Float64() < fresnelDielectric(wo.Dot(h), 1.0, p.RefractiveIndex) {
+			wi = reflect(wo, h)
+			break
+		}
+		refracted, ok := refract(wo, h, 1.0/p.RefractiveIndex)
+		if !ok || refracted.Z >= 0.0 {
+			return geometry.RayZero, shading.ColorBlack, false
+		}
+		// as with a rough dielectric, the Fresnel term and the microfacet distribution cancel out of the weight
+		weight := l.transmissionWeight * l.coatTransmittance(wo) / l.pTransmission *
+			l.base.g2(wo, refracted) / l.base.g1(wo)
+		return geometry.Ray{
+			Origin:    hitPoint,
+			Direction: f.toWorld(refracted),
+			Moment:    rayHit.Ray.Moment,
+		}, l.baseColor.MultScalar(weight), true
+	}
+	if wi.Z <= 0.0 {
+		return geometry.RayZero, shading.ColorBlack, false
+	}
+
+	bsdfCosine, pdf := p.evaluateLocal(l, wo, wi)
+	if pdf <= 0.0 {
+		return geometry.RayZero, shading.ColorBlack, false
+	}
+	return geometry.Ray{
+		Origin:    hitPoint,
+		Direction: f.toWorld(wi),
+		Moment:    rayHit.Ray.Moment,
+	}, bsdfCosine.DivScalar(pdf), true
+}
+
+// Evaluate returns the BSDF times the cosine term for light arriving from direction, and its sampling density
+// only reflection is evaluated, as light from outside can't be sampled through the object itself
+func (p *Principled) Evaluate(rayHit RayHit, direction geometry.Vector) (shading.Color, float64) {
+	outgoing := rayHit.Ray.Direction.Unit().Negate()
+	if outgoing.Dot(rayHit.NormalAtHit) <= 0.0 {
+		return shading.ColorBlack, 0.0
+	}
+	f := newFrame(rayHit.NormalAtHit)
+	wo := f.toLocal(outgoing)
+	wi := f.toLocal(direction.Unit())
+	if wo.Z <= 0.0 || wi.Z <= 0.0 {
+		return shading.ColorBlack, 0.0
+	}
+	return p.evaluateLocal(p.lobes(rayHit.U, rayHit.V, rayHit.Point()), wo, wi)
+}
+
+// evaluateLocal sums the reflective lobes for a pair of directions above the surface, in local coordinates,
+// along with the density with which Scatter chooses wi
+func (p *Principled) evaluateLocal(l principledLobes, wo, wi geometry.Vector) (shading.Color, float64) {
+	h := wo.Add(wi).Unit()
+	cosD := math.Max(0.0, wi.Dot(h))
+
+	// diffuse, with Burley's retro-reflection at grazing angles, and sheen on top
+	fd90 := 0.5 + 2.0*l.roughness*cosD*cosD
+	retro := (1.0 + (fd90-1.0)*math.Pow(1.0-wi.Z, 5.0)) * (1.0 + (fd90-1.0)*math.Pow(1.0-wo.Z, 5.0))
+	sheenColor := shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0}.Add(l.tint()).MultScalar(0.5)
+	diffuse := l.baseColor.MultScalar(retro / math.Pi).Add(
+		sheenColor.MultScalar(l.sheen * math.Pow(1.0-cosD, 5.0))).MultScalar(l.diffuseWeight * wi.Z)
+
+	// specular reflection, from the dielectric base and the metal alike
+	// the cosine of the incoming direction cancels with the BSDF's denominator
+	baseMicrofacet := l.base.d(h) * l.base.g2(wo, wi) / (4.0 * wo.Z)
+	dielectricF0 := 0.08 * l.specular
+	specularFresnel := schlickColor(l.baseColor, cosD).MultScalar(l.metallic).Add(
+		schlickColor(shading.Color{Red: dielectricF0, Green: dielectricF0, Blue: dielectricF0}, cosD).MultScalar(
+			(1.0 - l.metallic) * (1.0 - l.transmission)))
+	specular := specularFresnel.MultScalar(baseMicrofacet)
+
+	// light reflected off the transmissive lobe rather than refracted into it
+	transmissionFresnel := fresnelDielectric(wo.Dot(h), 1.0, p.RefractiveIndex)
+	transmissionReflection := l.transmissionWeight * transmissionFresnel * baseMicrofacet
+
+	// the clearcoat sits above everything else, which only sees the light it lets through
+	// its reflectance at normal incidence of 0.04 is that of a refractive index of 1.5
+	coatFresnel := schlick(cosD, 1.5)
+	coat := l.clearcoat * coatFresnel * l.coat.d(h) * l.coat.g2(wo, wi) / (4.0 * wo.Z)
+
+	bsdfCosine := diffuse.Add(specular).Add(shading.Color{
+		Red:   transmissionReflection,
+		Green: transmissionReflection,
+		Blue:  transmissionReflection,
+	}).MultScalar(l.coatTransmittance(wo)).Add(shading.Color{Red: coat, Green: coat, Blue: coat})
+
+	pdf := l.pDiffuse*wi.Z/math.Pi +
+		l.pSpecular*l.base.reflectionPDF(wo, h) +
+		l.pClearcoat*l.coat.reflectionPDF(wo, h) +
+		l.pTransmission*transmissionFresnel*l.base.reflectionPDF(wo, h)
+	return bsdfCosine, pdf
+}
+
+// lobes returns the material's parameters at texture coordinates (u, v)
+func (p *Principled) lobes(u, v float64, point geometry.Point) principledLobes {
+	l := principledLobes{
+		baseColor:    p.BaseColorTexture.Value(u, v, point).Clamp(0.0, 1.0),
+		roughness:    math.Max(principledMinimumRoughness, scalar(p.RoughnessTexture, u, v, point)),
+		metallic:     scalar(p.MetallicTexture, u, v, point),
+		specular:     scalar(p.SpecularTexture, u, v, point),
+		clearcoat:    scalar(p.ClearcoatTexture, u, v, point),
+		sheen:        scalar(p.SheenTexture, u, v, point),
+		transmission: scalar(p.TransmissionTexture, u, v, point),
+	}
+	l.base = newGGX(l.roughness, l.roughness)
+	l.coat = newGGX(principledClearcoatRoughness, principledClearcoatRoughness)
+	l.diffuseWeight = (1.0 - l.metallic) * (1.0 - l.transmission)
+	l.transmissionWeight = (1.0 - l.metallic) * l.transmission
+
+	// lobes are sampled roughly in proportion to how much light they reflect
+	diffuse := l.diffuseWeight
+	specular := l.metallic + 0.25*(1.0-l.metallic)*(1.0-l.transmission)
+	clearcoat := 0.25 * l.clearcoat
+	transmission := l.transmissionWeight
+	total := diffuse + specular + clearcoat + transmission
+	l.pDiffuse = diffuse / total
+	l.pSpecular = specular / total
+	l.pClearcoat = clearcoat / total
+	l.pTransmission = transmission / total
+	return l
+}
+
+// tint returns the hue of the base color, with its brightness normalized away
+func (l principledLobes) tint() shading.Color {
+	luminance := l.baseColor.Luminance()
+	if luminance <= 0.0 {
+		return shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0}
+	}
+	return l.baseColor.DivScalar(luminance)
+}
+
+// coatTransmittance returns the fraction of light which passes through the clearcoat towards the outgoing direction
+func (l principledLobes) coatTransmittance(wo geometry.Vector) float64 {
+	return 1.0 - l.clearcoat*schlick(wo.Z, 1.5)
+}
+
+// scalar returns the luminance of a texture at (u, v), clamped to [0, 1]
+func scalar(t texture.Texture, u, v float64, p geometry.Point) float64 {
+	return math.Max(0.0, math.Min(1.0, t.Value(u, v, p).Luminance()))
+}
+
+// schlickColor returns Schlick's approximation of the Fresnel reflectance, per channel,
+// given the reflectance at normal incidence and the cosine of the angle of incidence
+func schlickColor(f0 shading.Color, cosine float64) shading.Color {
+	weight := math.Pow(1.0-math.Max(0.0, math.Min(1.0, cosine)), 5.0)
+	return f0.MultScalar(1.0 - weight).Add(shading.Color{Red: weight, Green: weight, Blue: weight})
+}
@@ -0,0 +1,55 @@
+package material
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+func TestPrincipledScatterMatchesEvaluate(t *testing.T) {
+	constant := func(value float64) texture.Texture {
+		return &texture.Color{Color: shading.Color{Red: value, Green: value, Blue: value}}
+	}
+	p, err := (&Principled{
+		BaseColorTexture:    &texture.Color{Color: shading.Color{Red: 0.8, Green: 0.3, Blue: 0.1}},
+		MetallicTexture:     constant(0.3),
+		RoughnessTexture:    constant(0.4),
+		SpecularTexture:     constant(0.5),
+		ClearcoatTexture:    constant(0.5),
+		SheenTexture:        constant(0.2),
+		TransmissionTexture: constant(0.0),
+		EmittanceTexture:    constant(0.0),
+		RefractiveIndex:     1.5,
+	}).Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rayHit := RayHit{
+		Ray: geometry.Ray{
+			Origin:    geometry.Point{X: 1.0, Y: 1.0, Z: 0.0},
+			Direction: geometry.Vector{X: -1.0, Y: -1.0, Z: 0.0},
+		},
+		NormalAtHit: geometry.Vector{X: 0.0, Y: 1.0, Z: 0.0},
+		Time:        1.0,
+	}
+	rng := rand.New(rand.NewSource(0))
+	for s := 0; s < 64; s++ {
+		scattered, attenuation, ok := p.Scatter(rayHit, rng)
+		if !ok {
+			continue
+		}
+		bsdfCosine, pdf := p.Evaluate(rayHit, scattered.Direction)
+		if pdf <= 0.0 {
+			t.Fatalf("Expected a positive density for scattered direction %v\n", scattered.Direction)
+		}
+		expected := bsdfCosine.DivScalar(pdf)
+		if math.Abs(expected.Red-attenuation.Red) > 1e-9 || math.Abs(expected.Blue-attenuation.Blue) > 1e-9 {
+			t.Errorf("Expected attenuation %v but got %v\n", expected, attenuation)
+		}
+	}
+}
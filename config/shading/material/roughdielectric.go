@@ -0,0 +1,131 @@
+package material
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+// RoughDielectric is an implementation of a Material
+// It represents frosted or sandblasted glass as a GGX microfacet surface which both reflects and transmits,
+// following Walter et al., "Microfacet Models for Refraction through Rough Surfaces" (2007)
+type RoughDielectric struct {
+	*NormalMap
+	ReflectanceTexture texture.Texture    `json:"-"`
+	EmittanceTexture   texture.Texture    `json:"-"`
+	RoughnessTexture   texture.Texture    `json:"-"` // roughness in [0, 1], given by the texture's luminance
+	RefractiveIndex    float64            `json:"refractive_index"`
+	Absorption         *Absorption        `json:"absorption"` // absorption of the interior, or nil for clear glass
+	Medium             *HomogeneousMedium `json:"medium"`     // medium scattering light inside, or nil if it travels straight through
+}
+
+// Reflectance returns the reflective color at texture coordinates (u, v)
+func (rd RoughDielectric) Reflectance(u, v float64, p geometry.Point) shading.Color {
+	return rd.ReflectanceTexture.Value(u, v, p)
+}
+
+// Emittance returns the emissive color at texture coordinates (u, v)
+func (rd RoughDielectric) Emittance(u, v float64, p geometry.Point) shading.Color {
+	return rd.EmittanceTexture.Value(u, v, p)
+}
+
+// Transmittance returns the fraction of light which survives travelling the given distance inside the material
+func (rd RoughDielectric) Transmittance(distance float64) shading.Color {
+	return rd.Absorption.Transmittance(distance)
+}
+
+// ScatterInterior samples whether light travelling along ray inside the material scatters before reaching the boundary
+func (rd RoughDielectric) ScatterInterior(ray geometry.Ray, tBoundary float64, rng *rand.Rand) (geometry.Ray, shading.Color, bool) {
+	return rd.Medium.Sample(ray, tBoundary, rng)
+}
+
+// IsSpecular returns whether this material is specular in nature (vs. diffuse)
+// This is currently unused and is likely to be deprecated in the future
+func (rd RoughDielectric) IsSpecular() bool {
+	return false
+}
+
+// Scatter returns an incoming ray given a RayHit representing the outgoing ray
+// a microfacet normal is sampled from those visible from the outgoing direction, and the ray is then
+// either reflected about it or refracted through it, in proportion to the exact Fresnel reflectance
+func (rd RoughDielectric) Scatter(rayHit RayHit, rng *rand.Rand) (geometry.Ray, shading.Color, bool) {
+	hitPoint := rayHit.Ray.PointAt(rayHit.Time)
+	f, wo, etaI, etaT := rd.localFrame(rayHit)
+	distribution := rd.distribution(rayHit)
+
+	var h geometry.Vector
+	if distribution.isSmooth() {
+		h = geometry.Vector{X: 0.0, Y: 0.0, Z: 1.0}
+	} else {
+		h = distribution.sampleVisibleNormal(wo, rng.Float64(), rng.Float64())
+	}
+
+	var wi geometry.Vector
+	if rng.Float64() < fresnelDielectric(wo.Dot(h), etaI, etaT) {
+		wi = reflect(wo, h)
+		if wi.Z <= 0.0 {
+			return geometry.RayZero, shading.ColorBlack, false
+		}
+	} else {
+		var ok bool
+		wi, ok = refract(wo, h, etaI/etaT)
+		if !ok || wi.Z >= 0.0 {
+			return geometry.RayZero, shading.ColorBlack, false
+		}
+	}
+
+	// choosing between reflection and refraction by the Fresnel term cancels it out of the weight,
+	// and sampling visible normals leaves only the shadowing of the incoming direction to account for
+	attenuation := rd.Reflectance(rayHit.U, rayHit.V, rayHit.Point())
+	if !distribution.isSmooth() {
+		attenuation = attenuation.MultScalar(distribution.g2(wo, wi) / distribution.g1(wo))
+	}
+	return geometry.Ray{
+		Origin:    hitPoint,
+		Direction: f.toWorld(wi),
+		Moment:    rayHit.Ray.Moment,
+	}, attenuation, true
+}
+
+// Evaluate returns the BSDF times the cosine term for light arriving from direction, and its sampling density
+// only the reflective lobe is evaluated, as light from outside can't be sampled through the object itself
+func (rd RoughDielectric) Evaluate(rayHit RayHit, direction geometry.Vector) (shading.Color, float64) {
+	distribution := rd.distribution(rayHit)
+	if distribution.isSmooth() {
+		return shading.ColorBlack, 0.0
+	}
+	f, wo, etaI, etaT := rd.localFrame(rayHit)
+	wi := f.toLocal(direction.Unit())
+	if wo.Z <= 0.0 || wi.Z <= 0.0 {
+		return shading.ColorBlack, 0.0
+	}
+	h := wo.Add(wi).Unit()
+	fresnel := fresnelDielectric(wo.Dot(h), etaI, etaT)
+	bsdfCosine := rd.Reflectance(rayHit.U, rayHit.V, rayHit.Point()).MultScalar(
+		fresnel * distribution.d(h) * distribution.g2(wo, wi) / (4.0 * wo.Z))
+	return bsdfCosine, fresnel * distribution.reflectionPDF(wo, h)
+}
+
+// distribution returns the microfacet distribution at the hit
+func (rd RoughDielectric) distribution(rayHit RayHit) ggx {
+	roughness := math.Max(0.0, math.Min(1.0, rd.RoughnessTexture.Value(rayHit.U, rayHit.V, rayHit.Point()).Luminance()))
+	return newGGX(roughness, roughness)
+}
+
+// localFrame returns the shading frame at the hit, facing the outgoing direction,
+// along with the outgoing direction in local coordinates and the refractive indices on either side of the surface
+func (rd RoughDielectric) localFrame(rayHit RayHit) (frame, geometry.Vector, float64, float64) {
+	outgoing := rayHit.Ray.Direction.Unit().Negate()
+	normal := rayHit.NormalAtHit
+	etaI, etaT := 1.0, rd.RefractiveIndex
+	// rays leaving the object arrive from inside
+	if outgoing.Dot(normal) < 0.0 {
+		normal = normal.Negate()
+		etaI, etaT = etaT, etaI
+	}
+	f := newFrame(normal)
+	return f, f.toLocal(outgoing), etaI, etaT
+}
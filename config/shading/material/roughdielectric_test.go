@@ -0,0 +1,18 @@
+package material
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFresnelDielectric(t *testing.T) {
+	// at normal incidence, the reflectance reduces to ((n1-n2) / (n1+n2))^2
+	expected := math.Pow((1.0-1.5)/(1.0+1.5), 2.0)
+	if r := fresnelDielectric(1.0, 1.0, 1.5); math.Abs(r-expected) > 1e-9 {
+		t.Errorf("Expected %f but got %f\n", expected, r)
+	}
+	// beyond the critical angle, everything is reflected
+	if r := fresnelDielectric(0.3, 1.5, 1.0); r != 1.0 {
+		t.Errorf("Expected total internal reflection but got %f\n", r)
+	}
+}
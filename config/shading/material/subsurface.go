@@ -0,0 +1,73 @@
+package material
+
+import (
+	"math/rand"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+)
+
+// Subsurface is an implementation of a Material
+// It represents a translucent material which scatters light beneath its surface, such as skin, wax, or marble
+// light refracts in through a smooth boundary and takes a random walk through the interior until it refracts back out,
+// so the material must be on closed geometry, and walks longer than the maximum bounces are cut short
+type Subsurface struct {
+	*NormalMap
+	ReflectanceTexture texture.Texture `json:"-"` // tints light crossing the surface
+	EmittanceTexture   texture.Texture `json:"-"`
+	RefractiveIndex    float64         `json:"refractive_index"`
+	Albedo             shading.Color   `json:"albedo"`         // fraction of light surviving each scattering event inside
+	MeanFreePath       shading.Color   `json:"mean_free_path"` // average distance light travels inside between scattering events
+	Anisotropy         float64         `json:"anisotropy"`     // Henyey-Greenstein anisotropy of the scattering inside
+}
+
+// Reflectance returns the reflective color at texture coordinates (u, v)
+func (s Subsurface) Reflectance(u, v float64, p geometry.Point) shading.Color {
+	return s.ReflectanceTexture.Value(u, v, p)
+}
+
+// Emittance returns the emissive color at texture coordinates (u, v)
+func (s Subsurface) Emittance(u, v float64, p geometry.Point) shading.Color {
+	return s.EmittanceTexture.Value(u, v, p)
+}
+
+// IsSpecular returns whether this material is specular in nature (vs. diffuse)
+// This is currently unused and is likely to be deprecated in the future
+func (s Subsurface) IsSpecular() bool {
+	return true
+}
+
+// Scatter returns an incoming ray given a RayHit representing the outgoing ray
+// the surface itself is a smooth boundary, which either reflects light or lets it into or out of the interior
+func (s Subsurface) Scatter(rayHit RayHit, rng *rand.Rand) (geometry.Ray, shading.Color, bool) {
+	return Dielectric{
+		ReflectanceTexture: s.ReflectanceTexture,
+		EmittanceTexture:   s.EmittanceTexture,
+		RefractiveIndex:    s.RefractiveIndex,
+	}.Scatter(rayHit, rng)
+}
+
+// ScatterInterior samples whether light travelling along ray inside the material scatters before reaching the boundary
+func (s Subsurface) ScatterInterior(ray geometry.Ray, tBoundary float64, rng *rand.Rand) (geometry.Ray, shading.Color, bool) {
+	return s.Medium().Sample(ray, tBoundary, rng)
+}
+
+// Medium returns the medium filling the interior, with an extinction of the reciprocal of the mean free path,
+// split between scattering and absorption by the albedo
+func (s Subsurface) Medium() *HomogeneousMedium {
+	extinction := shading.Color{
+		Red:   1.0 / s.MeanFreePath.Red,
+		Green: 1.0 / s.MeanFreePath.Green,
+		Blue:  1.0 / s.MeanFreePath.Blue,
+	}
+	return &HomogeneousMedium{
+		AbsorptionCoefficient: extinction.MultColor(shading.Color{
+			Red:   1.0 - s.Albedo.Red,
+			Green: 1.0 - s.Albedo.Green,
+			Blue:  1.0 - s.Albedo.Blue,
+		}),
+		ScatteringCoefficient: extinction.MultColor(s.Albedo),
+		Anisotropy:            s.Anisotropy,
+	}
+}
@@ -0,0 +1,32 @@
+package material
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// the interior's extinction should be the reciprocal of the mean free path, split between scattering and absorption by the albedo
+func TestSubsurfaceMediumFromMeanFreePathAndAlbedo(t *testing.T) {
+	s := Subsurface{
+		Albedo:       shading.Color{Red: 0.9, Green: 0.5, Blue: 0.0},
+		MeanFreePath: shading.Color{Red: 2.0, Green: 0.5, Blue: 0.25},
+		Anisotropy:   0.3,
+	}
+	hm := s.Medium()
+	expectedAbsorption := shading.Color{Red: 0.05, Green: 1.0, Blue: 4.0}
+	expectedScattering := shading.Color{Red: 0.45, Green: 1.0, Blue: 0.0}
+	for _, c := range []struct{ got, expected shading.Color }{
+		{hm.AbsorptionCoefficient, expectedAbsorption},
+		{hm.ScatteringCoefficient, expectedScattering},
+	} {
+		if math.Abs(c.got.Red-c.expected.Red) > 1e-9 || math.Abs(c.got.Green-c.expected.Green) > 1e-9 ||
+			math.Abs(c.got.Blue-c.expected.Blue) > 1e-9 {
+			t.Errorf("Expected coefficient %v but got %v\n", c.expected, c.got)
+		}
+	}
+	if hm.Anisotropy != 0.3 {
+		t.Errorf("Expected anisotropy 0.3 but got %f\n", hm.Anisotropy)
+	}
+}
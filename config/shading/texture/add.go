@@ -0,0 +1,17 @@
+package texture
+
+import (
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// Add holds information about a texture node whose color is the sum of two other textures
+type Add struct {
+	First  Texture `json:"-"`
+	Second Texture `json:"-"`
+}
+
+// Value returns the sum of both textures' colors
+func (at *Add) Value(u, v float64, p geometry.Point) shading.Color {
+	return at.First.Value(u, v, p).Add(at.Second.Value(u, v, p))
+}
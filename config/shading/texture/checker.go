@@ -0,0 +1,31 @@
+package texture
+
+import (
+	"math"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// Checker holds information about a texture alternating between two colors in a grid,
+// of squares over the texture coordinates, or of cubes through space if it's solid
+type Checker struct {
+	FirstColor  shading.Color `json:"first_color"`
+	SecondColor shading.Color `json:"second_color"`
+	Scale       float64       `json:"scale"` // squares per unit of texture coordinate, or cubes per unit of distance
+	IsSolid     bool          `json:"is_solid"`
+}
+
+// Value returns the color of the square or cube containing the given point
+func (ct *Checker) Value(u, v float64, p geometry.Point) shading.Color {
+	var cell float64
+	if ct.IsSolid {
+		cell = math.Floor(p.X*ct.Scale) + math.Floor(p.Y*ct.Scale) + math.Floor(p.Z*ct.Scale)
+	} else {
+		cell = math.Floor(u*ct.Scale) + math.Floor(v*ct.Scale)
+	}
+	if int64(cell)&1 == 0 {
+		return ct.FirstColor
+	}
+	return ct.SecondColor
+}
@@ -0,0 +1,31 @@
+package texture
+
+import (
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+func TestCheckerAlternates(t *testing.T) {
+	black := shading.ColorBlack
+	white := shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0}
+	tests := []struct {
+		checker  Checker
+		u, v     float64
+		p        geometry.Point
+		expected shading.Color
+	}{
+		{Checker{black, white, 2.0, false}, 0.1, 0.1, geometry.Point{}, black},
+		{Checker{black, white, 2.0, false}, 0.6, 0.1, geometry.Point{}, white},
+		{Checker{black, white, 2.0, false}, 0.6, 0.6, geometry.Point{}, black},
+		{Checker{black, white, 1.0, true}, 0.0, 0.0, geometry.Point{X: 0.5, Y: 0.5, Z: 0.5}, black},
+		{Checker{black, white, 1.0, true}, 0.0, 0.0, geometry.Point{X: -0.5, Y: 0.5, Z: 0.5}, white},
+		{Checker{black, white, 1.0, true}, 0.0, 0.0, geometry.Point{X: -0.5, Y: -0.5, Z: 0.5}, black},
+	}
+	for _, test := range tests {
+		if color := test.checker.Value(test.u, test.v, test.p); color != test.expected {
+			t.Errorf("Expected %v at (%f, %f), %v but got %v\n", test.expected, test.u, test.v, test.p, color)
+		}
+	}
+}
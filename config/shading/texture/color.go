@@ -1,6 +1,9 @@
 package texture
 
-import "github.com/paulwrubel/photolum/config/shading"
+import (
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
 
 // Color holds information about a solid-colored texture
 type Color struct {
@@ -9,6 +12,6 @@ type Color struct {
 
 // Value returns a color at a given texture coordinate
 // this value is always the same, as the color is solid
-func (ct *Color) Value(u, v float64) shading.Color {
+func (ct *Color) Value(u, v float64, p geometry.Point) shading.Color {
 	return ct.Color
 }
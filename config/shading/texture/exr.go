@@ -0,0 +1,474 @@
+package texture
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+)
+
+// exrMaximumCompressionRatio bounds how many bytes of pixels each byte of an OpenEXR file may decompress to,
+// a little beyond what zlib can reach, so a small file can't claim an enormous image
+const exrMaximumCompressionRatio = 1100
+
+// exrMagic is the signature an OpenEXR file begins with
+var exrMagic = []byte{0x76, 0x2f, 0x31, 0x01}
+
+// flags in the version field of an OpenEXR file
+const (
+	exrFlagTiled     = 0x200
+	exrFlagDeep      = 0x800
+	exrFlagMultipart = 0x1000
+)
+
+// compression methods of an OpenEXR file, of which only the lossless methods below PXR24 are supported
+const (
+	exrCompressionNone = 0
+	exrCompressionRLE  = 1
+	exrCompressionZIPS = 2
+	exrCompressionZIP  = 3
+	exrCompressionPIZ  = 4
+)
+
+// pixel types of an OpenEXR channel
+const (
+	exrPixelUint  = 0
+	exrPixelHalf  = 1
+	exrPixelFloat = 2
+)
+
+// exrChannel describes one channel of an OpenEXR image
+type exrChannel struct {
+	name      string
+	pixelType int32
+	xSampling int32
+	ySampling int32
+}
+
+// size returns the number of bytes each sample of the channel takes
+func (ec exrChannel) size() int {
+	if ec.pixelType == exrPixelHalf {
+		return 2
+	}
+	return 4
+}
+
+// exrHeader holds the attributes of an OpenEXR image needed to decode it
+type exrHeader struct {
+	channels    []exrChannel
+	compression byte
+	minX, minY  int
+	maxX, maxY  int
+	isTiled     bool
+	tileWidth   int
+	tileHeight  int
+}
+
+// exrReader reads little-endian values from an OpenEXR file, remembering the first read past its end
+// reads after that return nil or zero, and sizes read from the file are never allocated
+type exrReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (er *exrReader) bytes(n int) []byte {
+	if er.err != nil || n < 0 || n > len(er.data)-er.pos {
+		er.err = fmt.Errorf("exr data is truncated")
+		return nil
+	}
+	b := er.data[er.pos : er.pos+n]
+	er.pos += n
+	return b
+}
+
+func (er *exrReader) int32() int32 {
+	b := er.bytes(4)
+	if b == nil {
+		return 0
+	}
+	return int32(binary.LittleEndian.Uint32(b))
+}
+
+func (er *exrReader) uint64() uint64 {
+	b := er.bytes(8)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
+// string reads a null-terminated string
+func (er *exrReader) string() string {
+	if er.err != nil {
+		return ""
+	}
+	end := bytes.IndexByte(er.data[er.pos:], 0)
+	if end < 0 {
+		er.err = fmt.Errorf("exr data is truncated")
+		return ""
+	}
+	s := string(er.data[er.pos : er.pos+end])
+	er.pos += end + 1
+	return s
+}
+
+// isEXR returns whether data looks like an OpenEXR image
+func isEXR(data []byte) bool {
+	return bytes.HasPrefix(data, exrMagic)
+}
+
+// decodeEXR decodes a single-part OpenEXR image, stored in either scanlines or tiles, into linear texels
+// the red, green, and blue channels are read, or a luminance channel for grayscale images
+func decodeEXR(data []byte) (raster, error) {
+	er := &exrReader{data: data, pos: len(exrMagic)}
+	version := er.int32()
+	if version&0xff != 2 {
+		return raster{}, fmt.Errorf("unsupported exr version %d", version&0xff)
+	}
+	if version&(exrFlagDeep|exrFlagMultipart) != 0 {
+		return raster{}, fmt.Errorf("deep and multi-part exr images are not supported")
+	}
+	header, err := readEXRHeader(er)
+	if err != nil {
+		return raster{}, err
+	}
+	header.isTiled = version&exrFlagTiled != 0
+
+	width, height := header.maxX-header.minX+1, header.maxY-header.minY+1
+	if width <= 0 || height <= 0 {
+		return raster{}, fmt.Errorf("invalid exr data window")
+	}
+	sources, err := exrSourceChannels(header.channels)
+	if err != nil {
+		return raster{}, err
+	}
+	// every pixel takes some bytes of the file, even compressed, so the data window can't be larger than it allows
+	pixelSize := 0
+	for _, channel := range header.channels {
+		pixelSize += channel.size()
+	}
+	maximumPixels := len(data) * exrMaximumCompressionRatio / pixelSize
+	if width > maximumPixels || height > maximumPixels/width {
+		return raster{}, fmt.Errorf("exr data window is too large for the file")
+	}
+	decoded := raster{
+		width:  width,
+		height: height,
+		texels: make([]float32, 3*width*height),
+	}
+
+	// the offset table lists where each chunk of pixels begins, which for tiles starts with those of the full resolution
+	var chunkCount, blockHeight int
+	if header.isTiled {
+		if header.tileWidth <= 0 || header.tileHeight <= 0 {
+			return raster{}, fmt.Errorf("invalid exr tile size")
+		}
+		chunkCount = ((width + header.tileWidth - 1) / header.tileWidth) * ((height + header.tileHeight - 1) / header.tileHeight)
+	} else {
+		blockHeight, err = exrLinesPerBlock(header.compression)
+		if err != nil {
+			return raster{}, err
+		}
+		chunkCount = (height + blockHeight - 1) / blockHeight
+	}
+	if chunkCount > (len(data)-er.pos)/8 {
+		return raster{}, fmt.Errorf("exr offset table is truncated")
+	}
+	offsets := make([]uint64, chunkCount)
+	for i := range offsets {
+		offsets[i] = er.uint64()
+	}
+	if er.err != nil {
+		return raster{}, er.err
+	}
+
+	for _, offset := range offsets {
+		if offset > uint64(len(data)) {
+			return raster{}, fmt.Errorf("invalid exr chunk offset")
+		}
+		chunk := &exrReader{data: data, pos: int(offset)}
+		// each chunk is a block of lines, or a tile, which is placed within the data window
+		var x, y, blockWidth, lines int
+		if header.isTiled {
+			tileX, tileY := int(chunk.int32()), int(chunk.int32())
+			levelX, levelY := chunk.int32(), chunk.int32()
+			if levelX != 0 || levelY != 0 {
+				continue
+			}
+			x, y = tileX*header.tileWidth, tileY*header.tileHeight
+			if tileX < 0 || tileY < 0 || x >= width || y >= height {
+				return raster{}, fmt.Errorf("invalid exr tile coordinates")
+			}
+			blockWidth = int(math.Min(float64(header.tileWidth), float64(width-x)))
+			lines = int(math.Min(float64(header.tileHeight), float64(height-y)))
+		} else {
+			y = int(chunk.int32()) - header.minY
+			if y < 0 || y >= height {
+				return raster{}, fmt.Errorf("invalid exr scanline coordinates")
+			}
+			blockWidth = width
+			lines = int(math.Min(float64(blockHeight), float64(height-y)))
+		}
+		size := int(chunk.int32())
+		compressed := chunk.bytes(size)
+		if chunk.err != nil {
+			return raster{}, chunk.err
+		}
+		pixels, err := decompressEXRBlock(header.compression, compressed, header.channels, blockWidth, lines)
+		if err != nil {
+			return raster{}, err
+		}
+		storeEXRBlock(decoded, pixels, header.channels, sources, x, y, blockWidth, lines)
+	}
+	return decoded, nil
+}
+
+// readEXRHeader reads the attributes of an OpenEXR image, which end with an empty name
+func readEXRHeader(er *exrReader) (exrHeader, error) {
+	header := exrHeader{}
+	hasChannels, hasCompression, hasDataWindow := false, false, false
+	for {
+		name := er.string()
+		if er.err != nil {
+			return exrHeader{}, er.err
+		}
+		if name == "" {
+			break
+		}
+		er.string()
+		size := int(er.int32())
+		value := &exrReader{data: er.bytes(size)}
+		if er.err != nil {
+			return exrHeader{}, er.err
+		}
+		switch name {
+		case "channels":
+			for {
+				channelName := value.string()
+				if channelName == "" {
+					break
+				}
+				channel := exrChannel{name: channelName, pixelType: value.int32()}
+				value.bytes(4)
+				channel.xSampling, channel.ySampling = value.int32(), value.int32()
+				header.channels = append(header.channels, channel)
+			}
+			hasChannels = true
+		case "compression":
+			if b := value.bytes(1); b != nil {
+				header.compression = b[0]
+			}
+			hasCompression = true
+		case "dataWindow":
+			header.minX, header.minY = int(value.int32()), int(value.int32())
+			header.maxX, header.maxY = int(value.int32()), int(value.int32())
+			hasDataWindow = true
+		case "tiles":
+			header.tileWidth, header.tileHeight = int(value.int32()), int(value.int32())
+		}
+		if value.err != nil {
+			return exrHeader{}, fmt.Errorf("invalid exr %s attribute", name)
+		}
+	}
+	if !hasChannels || !hasCompression || !hasDataWindow {
+		return exrHeader{}, fmt.Errorf("exr header is missing required attributes")
+	}
+	for _, channel := range header.channels {
+		if channel.pixelType < exrPixelUint || channel.pixelType > exrPixelFloat {
+			return exrHeader{}, fmt.Errorf("invalid exr pixel type %d", channel.pixelType)
+		}
+		if channel.xSampling != 1 || channel.ySampling != 1 {
+			return exrHeader{}, fmt.Errorf("subsampled exr channels are not supported")
+		}
+	}
+	return header, nil
+}
+
+// exrSourceChannels returns the indices of the channels to read red, green, and blue from
+func exrSourceChannels(channels []exrChannel) ([3]int, error) {
+	find := func(name string) int {
+		for i, channel := range channels {
+			if channel.name == name {
+				return i
+			}
+		}
+		return -1
+	}
+	red, green, blue := find("R"), find("G"), find("B")
+	if red >= 0 && green >= 0 && blue >= 0 {
+		return [3]int{red, green, blue}, nil
+	}
+	if luminance := find("Y"); luminance >= 0 {
+		return [3]int{luminance, luminance, luminance}, nil
+	}
+	return [3]int{}, fmt.Errorf("exr image has neither R, G, and B channels nor a Y channel")
+}
+
+// exrLinesPerBlock returns the number of scanlines compressed together by a compression method
+func exrLinesPerBlock(compression byte) (int, error) {
+	switch compression {
+	case exrCompressionNone, exrCompressionRLE, exrCompressionZIPS:
+		return 1, nil
+	case exrCompressionZIP:
+		return 16, nil
+	case exrCompressionPIZ:
+		return 32, nil
+	default:
+		return 0, fmt.Errorf("unsupported exr compression %d", compression)
+	}
+}
+
+// decompressEXRBlock returns the pixels of a block, line by line, and channel by channel within each line
+// blocks which wouldn't have gotten smaller are stored as they are
+func decompressEXRBlock(compression byte, compressed []byte, channels []exrChannel, width, lines int) ([]byte, error) {
+	size := 0
+	for _, channel := range channels {
+		size += width * lines * channel.size()
+	}
+	if len(compressed) >= size {
+		return compressed[:size], nil
+	}
+	var pixels []byte
+	var err error
+	switch compression {
+	case exrCompressionRLE:
+		pixels, err = decompressEXRRunLength(compressed, size)
+		if err == nil {
+			pixels = unpredictEXR(pixels)
+		}
+	case exrCompressionZIPS, exrCompressionZIP:
+		pixels, err = decompressEXRZip(compressed, size)
+		if err == nil {
+			pixels = unpredictEXR(pixels)
+		}
+	case exrCompressionPIZ:
+		pixels, err = decompressPIZ(compressed, channels, width, lines)
+	default:
+		err = fmt.Errorf("unsupported exr compression %d", compression)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(pixels) != size {
+		return nil, fmt.Errorf("exr block decompressed to the wrong size")
+	}
+	return pixels, nil
+}
+
+// decompressEXRRunLength expands runs, each a signed count followed by either that many literal bytes if it's negative,
+// or one byte to repeat one more than that many times
+func decompressEXRRunLength(compressed []byte, size int) ([]byte, error) {
+	pixels := make([]byte, 0, size)
+	for len(compressed) > 0 {
+		count := int(int8(compressed[0]))
+		compressed = compressed[1:]
+		if count < 0 {
+			if -count > len(compressed) {
+				return nil, fmt.Errorf("exr run-length data is truncated")
+			}
+			pixels = append(pixels, compressed[:-count]...)
+			compressed = compressed[-count:]
+		} else {
+			if len(compressed) == 0 {
+				return nil, fmt.Errorf("exr run-length data is truncated")
+			}
+			for i := 0; i <= count; i++ {
+				pixels = append(pixels, compressed[0])
+			}
+			compressed = compressed[1:]
+		}
+	}
+	return pixels, nil
+}
+
+// decompressEXRZip inflates zlib compressed data, reading no more than one byte past the size expected
+// so that data inflating to far more than that is caught without holding all of it
+func decompressEXRZip(compressed []byte, size int) ([]byte, error) {
+	zr, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return ioutil.ReadAll(io.LimitReader(zr, int64(size)+1))
+}
+
+// unpredictEXR reverses the delta encoding of each byte from the last, and the split of even and odd bytes into halves,
+// which run-length and zip compression apply before compressing
+func unpredictEXR(data []byte) []byte {
+	for i := 1; i < len(data); i++ {
+		data[i] = byte(int(data[i-1]) + int(data[i]) - 128)
+	}
+	pixels := make([]byte, len(data))
+	half := (len(data) + 1) / 2
+	for i := range pixels {
+		if i%2 == 0 {
+			pixels[i] = data[i/2]
+		} else {
+			pixels[i] = data[half+i/2]
+		}
+	}
+	return pixels
+}
+
+// storeEXRBlock converts the samples of a block of pixels with its top left at (x, y) into texels of the raster
+func storeEXRBlock(decoded raster, pixels []byte, channels []exrChannel, sources [3]int, x, y, width, lines int) {
+	// find where each channel begins within a line
+	channelOffsets := make([]int, len(channels))
+	lineSize := 0
+	for i, channel := range channels {
+		channelOffsets[i] = lineSize
+		lineSize += width * channel.size()
+	}
+	for line := 0; line < lines; line++ {
+		for column := 0; column < width; column++ {
+			i := 3 * ((y+line)*decoded.width + x + column)
+			for component, source := range sources {
+				channel := channels[source]
+				offset := line*lineSize + channelOffsets[source] + column*channel.size()
+				decoded.texels[i+component] = exrSample(pixels[offset:], channel.pixelType)
+			}
+		}
+	}
+}
+
+// exrSample converts a sample of the given pixel type to a float
+// values which aren't finite are zeroed, so a stray infinity or NaN can't spread through a render
+func exrSample(b []byte, pixelType int32) float32 {
+	var value float32
+	switch pixelType {
+	case exrPixelUint:
+		value = float32(binary.LittleEndian.Uint32(b))
+	case exrPixelHalf:
+		value = halfToFloat(binary.LittleEndian.Uint16(b))
+	default:
+		value = math.Float32frombits(binary.LittleEndian.Uint32(b))
+	}
+	if math.IsInf(float64(value), 0) || math.IsNaN(float64(value)) {
+		return 0.0
+	}
+	return value
+}
+
+// halfToFloat converts a 16-bit half-precision float to a float
+func halfToFloat(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exponent := uint32(h>>10) & 0x1f
+	mantissa := uint32(h) & 0x3ff
+	switch exponent {
+	case 0:
+		// zero, or a subnormal number
+		value := float32(math.Ldexp(float64(mantissa), -24))
+		if sign != 0 {
+			return -value
+		}
+		return value
+	case 0x1f:
+		return math.Float32frombits(sign | 0x7f800000 | mantissa<<13)
+	default:
+		return math.Float32frombits(sign | (exponent+127-15)<<23 | mantissa<<13)
+	}
+}
@@ -0,0 +1,205 @@
+package texture
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"testing"
+)
+
+// exrTestWidth and exrTestHeight are the size of the test images, whose blue channel is stored as floats and the others as halves
+const (
+	exrTestWidth  = 8
+	exrTestHeight = 4
+)
+
+// exrTestPixel returns the red, green, and blue of the test images at column x and row y
+func exrTestPixel(x, y int) (float32, float32, float32) {
+	return 1.0 + float32(7-x)/1024.0, 1.0, 1.0 + float32(3-y)/128.0
+}
+
+// exrTestPIZBlock is the test image compressed as a single PIZ block
+const exrTestPIZBlock = "8007f007ff00000000000000000000000000000000000000000000000000000000000000000000000000000000000000" +
+	"000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000" +
+	"00000000000000000000000000000000000000000f2e000000000000000b0000000600000099000000000000000420fc" +
+	"17c1058ff1fef7bde7f9fef7bddec23404a52ffdb6ff80"
+
+// floatToHalf converts a float exactly representable as a normal half-precision float to one
+func floatToHalf(f float32) uint16 {
+	bits := math.Float32bits(f)
+	return uint16(bits>>16&0x8000 | ((bits>>23&0xff)-127+15)<<10 | bits>>13&0x3ff)
+}
+
+// exrTestLines returns the uncompressed pixels of lines of the test image, starting at row y
+func exrTestLines(y, lines int) []byte {
+	var pixels bytes.Buffer
+	for row := y; row < y+lines; row++ {
+		for channel := 0; channel < 3; channel++ {
+			for x := 0; x < exrTestWidth; x++ {
+				r, g, b := exrTestPixel(x, row)
+				switch channel {
+				case 0:
+					binary.Write(&pixels, binary.LittleEndian, b)
+				case 1:
+					binary.Write(&pixels, binary.LittleEndian, floatToHalf(g))
+				default:
+					binary.Write(&pixels, binary.LittleEndian, floatToHalf(r))
+				}
+			}
+		}
+	}
+	return pixels.Bytes()
+}
+
+// exrTestImage returns an OpenEXR file of the test image, stored in the given blocks of lines
+func exrTestImage(compression byte, linesPerBlock int, blocks [][]byte) []byte {
+	int32s := func(values ...int32) []byte {
+		var b bytes.Buffer
+		binary.Write(&b, binary.LittleEndian, values)
+		return b.Bytes()
+	}
+	attribute := func(name, attributeType string, value []byte) []byte {
+		b := append([]byte(name+"\x00"+attributeType+"\x00"), int32s(int32(len(value)))...)
+		return append(b, value...)
+	}
+	var channels []byte
+	for _, channel := range []struct {
+		name      string
+		pixelType int32
+	}{{"B", exrPixelFloat}, {"G", exrPixelHalf}, {"R", exrPixelHalf}} {
+		channels = append(channels, channel.name+"\x00"...)
+		channels = append(channels, int32s(channel.pixelType, 0, 1, 1)...)
+	}
+	channels = append(channels, 0)
+
+	header := append([]byte{}, exrMagic...)
+	header = append(header, int32s(2)...)
+	header = append(header, attribute("channels", "chlist", channels)...)
+	header = append(header, attribute("compression", "compression", []byte{compression})...)
+	header = append(header, attribute("dataWindow", "box2i", int32s(0, 0, exrTestWidth-1, exrTestHeight-1))...)
+	header = append(header, attribute("displayWindow", "box2i", int32s(0, 0, exrTestWidth-1, exrTestHeight-1))...)
+	header = append(header, attribute("lineOrder", "lineOrder", []byte{0})...)
+	header = append(header, 0)
+
+	var offsets, chunks bytes.Buffer
+	for i, block := range blocks {
+		binary.Write(&offsets, binary.LittleEndian, uint64(len(header)+8*len(blocks)+chunks.Len()))
+		chunks.Write(int32s(int32(i*linesPerBlock), int32(len(block))))
+		chunks.Write(block)
+	}
+	return append(append(header, offsets.Bytes()...), chunks.Bytes()...)
+}
+
+// checkEXRTestImage checks the texels decoded from an OpenEXR file of the test image
+func checkEXRTestImage(t *testing.T, data []byte) {
+	level, err := decodeEXR(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level.width != exrTestWidth || level.height != exrTestHeight {
+		t.Fatalf("Expected a %dx%d image but got %dx%d\n", exrTestWidth, exrTestHeight, level.width, level.height)
+	}
+	for y := 0; y < exrTestHeight; y++ {
+		for x := 0; x < exrTestWidth; x++ {
+			r, g, b := exrTestPixel(x, y)
+			i := 3 * (y*exrTestWidth + x)
+			if level.texels[i] != r || level.texels[i+1] != g || level.texels[i+2] != b {
+				t.Errorf("Expected (%f, %f, %f) at (%d, %d) but got %v\n", r, g, b, x, y, level.texels[i:i+3])
+			}
+		}
+	}
+}
+
+func TestDecodeEXRUncompressed(t *testing.T) {
+	var blocks [][]byte
+	for y := 0; y < exrTestHeight; y++ {
+		blocks = append(blocks, exrTestLines(y, 1))
+	}
+	checkEXRTestImage(t, exrTestImage(exrCompressionNone, 1, blocks))
+}
+
+func TestDecodeEXRZip(t *testing.T) {
+	// interleave the even and odd bytes, then store each as its difference from the last
+	pixels := exrTestLines(0, exrTestHeight)
+	var predicted []byte
+	for i := 0; i < len(pixels); i += 2 {
+		predicted = append(predicted, pixels[i])
+	}
+	for i := 1; i < len(pixels); i += 2 {
+		predicted = append(predicted, pixels[i])
+	}
+	for i := len(predicted) - 1; i > 0; i-- {
+		predicted[i] = byte(int(predicted[i]) - int(predicted[i-1]) + 128)
+	}
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write(predicted)
+	zw.Close()
+	if compressed.Len() >= len(pixels) {
+		t.Fatal("Expected the test image to compress")
+	}
+	checkEXRTestImage(t, exrTestImage(exrCompressionZIP, 16, [][]byte{compressed.Bytes()}))
+}
+
+func TestDecodeEXRPIZ(t *testing.T) {
+	block, err := hex.DecodeString(exrTestPIZBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkEXRTestImage(t, exrTestImage(exrCompressionPIZ, 32, [][]byte{block}))
+}
+
+func TestDecodeEXRRejectsSizesBeyondFile(t *testing.T) {
+	var blocks [][]byte
+	for y := 0; y < exrTestHeight; y++ {
+		blocks = append(blocks, exrTestLines(y, 1))
+	}
+	image := func() []byte {
+		return exrTestImage(exrCompressionNone, 1, blocks)
+	}
+	putInt32 := func(data []byte, at int, value int32) []byte {
+		binary.LittleEndian.PutUint32(data[at:], uint32(value))
+		return data
+	}
+	attribute := func(data []byte, name string) int {
+		return bytes.Index(data, []byte(name+"\x00"))
+	}
+	firstChunk := len(image()) - len(blocks)*(8+len(blocks[0]))
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"negative attribute size", putInt32(image(), attribute(image(), "compression")+len("compression\x00compression\x00"), -1)},
+		{"huge attribute size", putInt32(image(), attribute(image(), "compression")+len("compression\x00compression\x00"), math.MaxInt32)},
+		{"huge data window", putInt32(putInt32(image(), attribute(image(), "dataWindow")+len("dataWindow\x00box2i\x00")+12, 1<<30), attribute(image(), "dataWindow")+len("dataWindow\x00box2i\x00")+16, 1<<30)},
+		{"negative chunk size", putInt32(image(), firstChunk+4, -1)},
+		{"huge chunk size", putInt32(image(), firstChunk+4, math.MaxInt32)},
+	}
+	for _, test := range tests {
+		if _, err := decodeEXR(test.data); err == nil {
+			t.Errorf("%s: expected an error\n", test.name)
+		}
+	}
+}
+
+func TestHalfToFloat(t *testing.T) {
+	tests := []struct {
+		half     uint16
+		expected float32
+	}{
+		{0x0000, 0.0},
+		{0x3c00, 1.0},
+		{0xc000, -2.0},
+		{0x3555, 0.333251953125},
+		{0x7bff, 65504.0},
+		{0x0001, float32(math.Ldexp(1.0, -24))},
+	}
+	for _, test := range tests {
+		if value := halfToFloat(test.half); value != test.expected {
+			t.Errorf("Expected %g for %#04x but got %g\n", test.expected, test.half, value)
+		}
+	}
+}
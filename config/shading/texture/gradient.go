@@ -0,0 +1,34 @@
+package texture
+
+import (
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// Gradient holds information about a texture blending from one color at its start to another at its end,
+// either along the line between them, or outwards from the start if it's radial
+// a gradient which isn't solid spans the texture coordinates, taking (u, v) as the point (u, v, 0)
+type Gradient struct {
+	FirstColor  shading.Color  `json:"first_color"`
+	SecondColor shading.Color  `json:"second_color"`
+	Start       geometry.Point `json:"start"`
+	End         geometry.Point `json:"end"`
+	IsRadial    bool           `json:"is_radial"`
+	IsSolid     bool           `json:"is_solid"`
+}
+
+// Value returns the blend of the colors at the given point, which is held at either color beyond the ends
+func (gt *Gradient) Value(u, v float64, p geometry.Point) shading.Color {
+	if !gt.IsSolid {
+		p = geometry.Point{X: u, Y: v, Z: 0.0}
+	}
+	axis := gt.Start.To(gt.End)
+	offset := gt.Start.To(p)
+	var t float64
+	if gt.IsRadial {
+		t = offset.Magnitude() / axis.Magnitude()
+	} else {
+		t = offset.Dot(axis) / axis.Dot(axis)
+	}
+	return blend(gt.FirstColor, gt.SecondColor, t)
+}
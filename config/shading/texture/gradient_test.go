@@ -0,0 +1,45 @@
+package texture
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+func TestGradientBlendsAndClamps(t *testing.T) {
+	black := shading.ColorBlack
+	white := shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0}
+	linear := &Gradient{
+		FirstColor:  black,
+		SecondColor: white,
+		Start:       geometry.Point{X: 0.0, Y: 0.0, Z: 0.0},
+		End:         geometry.Point{X: 2.0, Y: 0.0, Z: 0.0},
+		IsSolid:     true,
+	}
+	radial := &Gradient{
+		FirstColor:  black,
+		SecondColor: white,
+		Start:       geometry.Point{X: 0.5, Y: 0.5, Z: 0.0},
+		End:         geometry.Point{X: 1.0, Y: 0.5, Z: 0.0},
+		IsRadial:    true,
+	}
+	tests := []struct {
+		gradient *Gradient
+		u, v     float64
+		p        geometry.Point
+		expected float64
+	}{
+		{linear, 0.0, 0.0, geometry.Point{X: 0.5, Y: 3.0, Z: -1.0}, 0.25},
+		{linear, 0.0, 0.0, geometry.Point{X: -1.0, Y: 0.0, Z: 0.0}, 0.0},
+		{linear, 0.0, 0.0, geometry.Point{X: 5.0, Y: 0.0, Z: 0.0}, 1.0},
+		{radial, 0.5, 0.75, geometry.Point{}, 0.5},
+		{radial, 0.5, 0.5, geometry.Point{X: 10.0}, 0.0},
+	}
+	for _, test := range tests {
+		if color := test.gradient.Value(test.u, test.v, test.p); math.Abs(color.Red-test.expected) > 1e-9 {
+			t.Errorf("Expected %f at (%f, %f), %v but got %v\n", test.expected, test.u, test.v, test.p, color)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package texture
+
+import (
+	"math"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// HSVAdjust holds information about a texture node which shifts the hue and scales the saturation and value of another texture
+type HSVAdjust struct {
+	Texture         Texture `json:"-"`
+	HueShift        float64 `json:"hue_shift"` // in degrees
+	SaturationScale float64 `json:"saturation_scale"`
+	ValueScale      float64 `json:"value_scale"`
+}
+
+// Value returns the adjusted color of the texture
+// saturation is kept within [0, 1], but value isn't bounded, so bright colors stay bright
+func (ht *HSVAdjust) Value(u, v float64, p geometry.Point) shading.Color {
+	hue, saturation, value := rgbToHSV(ht.Texture.Value(u, v, p))
+	hue = math.Mod(math.Mod(hue+ht.HueShift, 360.0)+360.0, 360.0)
+	saturation = math.Max(0.0, math.Min(1.0, saturation*ht.SaturationScale))
+	return hsvToRGB(hue, saturation, value*ht.ValueScale)
+}
+
+// rgbToHSV returns the hue, in degrees, saturation, and value of a color
+func rgbToHSV(c shading.Color) (float64, float64, float64) {
+	max := math.Max(c.Red, math.Max(c.Green, c.Blue))
+	min := math.Min(c.Red, math.Min(c.Green, c.Blue))
+	delta := max - min
+	if max <= 0.0 {
+		return 0.0, 0.0, max
+	}
+	var hue float64
+	switch {
+	case delta == 0.0:
+		hue = 0.0
+	case max == c.Red:
+		hue = 60.0 * math.Mod((c.Green-c.Blue)/delta+6.0, 6.0)
+	case max == c.Green:
+		hue = 60.0 * ((c.Blue-c.Red)/delta + 2.0)
+	default:
+		hue = 60.0 * ((c.Red-c.Green)/delta + 4.0)
+	}
+	return hue, delta / max, max
+}
+
+// hsvToRGB returns the color of a hue, in degrees in [0, 360), saturation, and value
+func hsvToRGB(hue, saturation, value float64) shading.Color {
+	chroma := value * saturation
+	sector := hue / 60.0
+	x := chroma * (1.0 - math.Abs(math.Mod(sector, 2.0)-1.0))
+	m := value - chroma
+	var r, g, b float64
+	switch int(sector) {
+	case 0:
+		r, g, b = chroma, x, 0.0
+	case 1:
+		r, g, b = x, chroma, 0.0
+	case 2:
+		r, g, b = 0.0, chroma, x
+	case 3:
+		r, g, b = 0.0, x, chroma
+	case 4:
+		r, g, b = x, 0.0, chroma
+	default:
+		r, g, b = chroma, 0.0, x
+	}
+	return shading.Color{Red: r + m, Green: g + m, Blue: b + m}
+}
@@ -0,0 +1,51 @@
+package texture
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+func TestHSVRoundTrip(t *testing.T) {
+	colors := []shading.Color{
+		{Red: 0.8, Green: 0.2, Blue: 0.1},
+		{Red: 0.1, Green: 0.9, Blue: 0.4},
+		{Red: 0.3, Green: 0.2, Blue: 0.7},
+		{Red: 0.5, Green: 0.5, Blue: 0.5},
+		{Red: 2.0, Green: 1.0, Blue: 0.5},
+	}
+	for _, c := range colors {
+		result := hsvToRGB(rgbToHSV(c))
+		if math.Abs(result.Red-c.Red) > 1e-9 || math.Abs(result.Green-c.Green) > 1e-9 || math.Abs(result.Blue-c.Blue) > 1e-9 {
+			t.Errorf("Expected %v to survive conversion to HSV and back but got %v\n", c, result)
+		}
+	}
+}
+
+func TestHSVAdjustShiftsHue(t *testing.T) {
+	ht := &HSVAdjust{
+		Texture:         &Color{Color: shading.Color{Red: 1.0}},
+		HueShift:        -240.0,
+		SaturationScale: 1.0,
+		ValueScale:      0.5,
+	}
+	// a third of the way around from red is green
+	result := ht.Value(0.0, 0.0, geometry.Point{})
+	if math.Abs(result.Red) > 1e-9 || math.Abs(result.Green-0.5) > 1e-9 || math.Abs(result.Blue) > 1e-9 {
+		t.Errorf("Expected half-bright green but got %v\n", result)
+	}
+}
+
+func TestHSVAdjustDesaturates(t *testing.T) {
+	ht := &HSVAdjust{
+		Texture:         &Color{Color: shading.Color{Red: 0.8, Green: 0.4, Blue: 0.2}},
+		SaturationScale: 0.0,
+		ValueScale:      1.0,
+	}
+	result := ht.Value(0.0, 0.0, geometry.Point{})
+	if result.Red != 0.8 || result.Green != 0.8 || result.Blue != 0.8 {
+		t.Errorf("Expected gray at the original value but got %v\n", result)
+	}
+}
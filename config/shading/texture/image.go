@@ -3,36 +3,163 @@ package texture
 import (
 	"bytes"
 	"image"
+	"math"
 
+	"github.com/paulwrubel/photolum/config/geometry"
 	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/enumeration/texturefiltertype"
+	"github.com/paulwrubel/photolum/enumeration/wrapmode"
 )
 
 // Image holds information about a texture based on an image
 type Image struct {
-	ImageData []byte      `json:"-"`
-	Gamma     float64     `json:"gamma"`
-	Magnitude float64     `json:"magnitude"`
-	Image     image.Image `json:"-"`
+	ImageData []byte                              `json:"-"`
+	Gamma     float64                             `json:"gamma"`
+	Magnitude float64                             `json:"magnitude"`
+	Filter    texturefiltertype.TextureFilterType `json:"filter_type"`
+	WrapMode  wrapmode.WrapMode                   `json:"wrap_mode"`
+
+	raster raster
+}
+
+// raster holds an image's texels as linear floats
+type raster struct {
+	width  int
+	height int
+	texels []float32 // red, green, and blue of each texel, row by row from the top
 }
 
-// Load decodes the image from the given filename and performs other setup actions
+// Load decodes the image from its data, converting it to linear texels
+// high dynamic range images are already linear, so only their magnitude is applied
 func (it *Image) Load() error {
-	var err error
-	it.Image, _, err = image.Decode(bytes.NewReader(it.ImageData))
+	decoded, isLinear, err := decodeImage(it.ImageData)
 	if err != nil {
 		return err
 	}
+	// de-gamma and apply magnitude once here, so filtering happens in linear space
+	for i, texel := range decoded.texels {
+		value := float64(texel)
+		if !isLinear {
+			value = math.Pow(value, it.Gamma)
+		}
+		decoded.texels[i] = float32(value * it.Magnitude)
+	}
+	it.raster = decoded
 	return nil
 }
 
+// CheckImageData returns an error if data isn't an image in one of the supported formats
+func CheckImageData(data []byte) error {
+	_, _, err := decodeImage(data)
+	return err
+}
+
+// decodeImage decodes image data into texels, returning whether they're linear,
+// which is the case for Radiance and OpenEXR images, whereas others are left as they're stored
+func decodeImage(data []byte) (raster, bool, error) {
+	switch {
+	case isRadiance(data):
+		decoded, err := decodeRadiance(data)
+		return decoded, true, err
+	case isEXR(data):
+		decoded, err := decodeEXR(data)
+		return decoded, true, err
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return raster{}, false, err
+	}
+	bounds := decoded.Bounds()
+	converted := raster{
+		width:  bounds.Dx(),
+		height: bounds.Dy(),
+		texels: make([]float32, 3*bounds.Dx()*bounds.Dy()),
+	}
+	for y := 0; y < converted.height; y++ {
+		for x := 0; x < converted.width; x++ {
+			c := shading.MakeColor(decoded.At(bounds.Min.X+x, bounds.Min.Y+y))
+			i := 3 * (y*converted.width + x)
+			converted.texels[i] = float32(c.Red)
+			converted.texels[i+1] = float32(c.Green)
+			converted.texels[i+2] = float32(c.Blue)
+		}
+	}
+	return converted, false, nil
+}
+
+// Width returns the width of the image, in texels
+func (it *Image) Width() int {
+	return it.raster.width
+}
+
+// Height returns the height of the image, in texels
+func (it *Image) Height() int {
+	return it.raster.height
+}
+
+// Texel returns the linear color of the texel at column x and row y, counted from the top left
+func (it *Image) Texel(x, y int) shading.Color {
+	return it.raster.texel(x, y)
+}
+
 // Value returns the color of the image at the given texture coordinates
-// parameters u and v have a valid range [0.0, 1.0)
-func (it *Image) Value(u, v float64) shading.Color {
-	// convert to image coordinates
-	x := int(u * float64(it.Image.Bounds().Dx()-1))
-	y := int((1.0 - v) * float64(it.Image.Bounds().Dy()-1))
-	// get the color of the image at that point
-	color := it.Image.At(x, y)
-	// convert to a color, de-gamma, and apply magnitude
-	return shading.MakeColor(color).Pow(it.Gamma).MultScalar(it.Magnitude)
+// coordinates outside [0.0, 1.0) are wrapped according to the image's wrap mode
+func (it *Image) Value(u, v float64, p geometry.Point) shading.Color {
+	switch it.Filter {
+	case texturefiltertype.Bilinear:
+		return it.bilinear(u, v)
+	default:
+		x := it.wrap(int(math.Floor(u*float64(it.raster.width))), it.raster.width)
+		y := it.wrap(int(math.Floor((1.0-v)*float64(it.raster.height))), it.raster.height)
+		return it.raster.texel(x, y)
+	}
+}
+
+// bilinear returns the blend of the four texels nearest the given texture coordinates
+func (it *Image) bilinear(u, v float64) shading.Color {
+	r := it.raster
+	x := u*float64(r.width) - 0.5
+	y := (1.0-v)*float64(r.height) - 0.5
+	floorX, floorY := math.Floor(x), math.Floor(y)
+	tx, ty := x-floorX, y-floorY
+	x0, y0 := int(floorX), int(floorY)
+	left, right := it.wrap(x0, r.width), it.wrap(x0+1, r.width)
+	top, bottom := it.wrap(y0, r.height), it.wrap(y0+1, r.height)
+
+	upper := r.texel(left, top).MultScalar(1.0 - tx).Add(r.texel(right, top).MultScalar(tx))
+	lower := r.texel(left, bottom).MultScalar(1.0 - tx).Add(r.texel(right, bottom).MultScalar(tx))
+	return upper.MultScalar(1.0 - ty).Add(lower.MultScalar(ty))
+}
+
+// wrap maps a texel index which may lie outside [0, n) back into it, according to the image's wrap mode
+func (it *Image) wrap(i, n int) int {
+	switch it.WrapMode {
+	case wrapmode.Clamp:
+		if i < 0 {
+			return 0
+		}
+		if i >= n {
+			return n - 1
+		}
+		return i
+	case wrapmode.Mirror:
+		// every other repetition is flipped, so the image's edges meet themselves
+		i = ((i % (2 * n)) + 2*n) % (2 * n)
+		if i >= n {
+			return 2*n - 1 - i
+		}
+		return i
+	default:
+		return ((i % n) + n) % n
+	}
+}
+
+// texel returns the color of the texel at column x and row y
+func (r raster) texel(x, y int) shading.Color {
+	i := 3 * (y*r.width + x)
+	return shading.Color{
+		Red:   float64(r.texels[i]),
+		Green: float64(r.texels[i+1]),
+		Blue:  float64(r.texels[i+2]),
+	}
 }
@@ -0,0 +1,60 @@
+package texture
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/enumeration/texturefiltertype"
+	"github.com/paulwrubel/photolum/enumeration/wrapmode"
+)
+
+// loadImage returns a loaded image texture of a single row, with the given gray levels from left to right
+func loadImage(t *testing.T, filter texturefiltertype.TextureFilterType, mode wrapmode.WrapMode, grays ...uint8) *Image {
+	img := image.NewGray(image.Rect(0, 0, len(grays), 1))
+	for x, gray := range grays {
+		img.SetGray(x, 0, color.Gray{Y: gray})
+	}
+	var imageData bytes.Buffer
+	if err := png.Encode(&imageData, img); err != nil {
+		t.Fatal(err)
+	}
+	it := &Image{ImageData: imageData.Bytes(), Gamma: 1.0, Magnitude: 1.0, Filter: filter, WrapMode: mode}
+	if err := it.Load(); err != nil {
+		t.Fatal(err)
+	}
+	return it
+}
+
+func TestImageWrapModes(t *testing.T) {
+	tests := []struct {
+		mode     wrapmode.WrapMode
+		u        float64
+		expected float64
+	}{
+		{wrapmode.Repeat, 1.1, 0.0},
+		{wrapmode.Repeat, -0.1, 1.0},
+		{wrapmode.Clamp, 1.1, 1.0},
+		{wrapmode.Clamp, -0.1, 0.0},
+		{wrapmode.Mirror, 1.1, 1.0},
+		{wrapmode.Mirror, 1.9, 0.0},
+	}
+	for _, test := range tests {
+		it := loadImage(t, texturefiltertype.Nearest, test.mode, 0, 255)
+		if value := it.Value(test.u, 0.5, geometry.Point{}).Red; math.Abs(value-test.expected) > 1e-6 {
+			t.Errorf("Expected %f at u = %f wrapping by %s but got %f\n", test.expected, test.u, test.mode, value)
+		}
+	}
+}
+
+func TestImageBilinearBlendsNeighbours(t *testing.T) {
+	it := loadImage(t, texturefiltertype.Bilinear, wrapmode.Clamp, 0, 255)
+	// halfway between the centers of the two texels
+	if value := it.Value(0.5, 0.5, geometry.Point{}).Red; math.Abs(value-0.5) > 1e-6 {
+		t.Errorf("Expected 0.5 but got %f\n", value)
+	}
+}
@@ -0,0 +1,24 @@
+package texture
+
+import (
+	"math"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// Invert holds information about a texture node whose color is the complement of another texture's
+type Invert struct {
+	Texture Texture `json:"-"`
+}
+
+// Value returns one minus each channel of the texture's color,
+// where channels brighter than one become black rather than negative
+func (it *Invert) Value(u, v float64, p geometry.Point) shading.Color {
+	c := it.Texture.Value(u, v, p)
+	return shading.Color{
+		Red:   math.Max(0.0, 1.0-c.Red),
+		Green: math.Max(0.0, 1.0-c.Green),
+		Blue:  math.Max(0.0, 1.0-c.Blue),
+	}
+}
@@ -0,0 +1,26 @@
+package texture
+
+import (
+	"math"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// Marble holds information about a solid texture of veins running across the x axis,
+// blending between two colors and warped by turbulence
+type Marble struct {
+	FirstColor  shading.Color `json:"first_color"`
+	SecondColor shading.Color `json:"second_color"`
+	Scale       float64       `json:"scale"`      // veins per unit distance
+	Octaves     int           `json:"octaves"`    // layers of noise in the turbulence
+	Turbulence  float64       `json:"turbulence"` // how far the veins are warped, in multiples of the distance between them
+	Perlin      *Perlin       `json:"-"`
+}
+
+// Value returns the blend of the colors at the given point in space
+func (mt *Marble) Value(u, v float64, p geometry.Point) shading.Color {
+	q := scale(p, mt.Scale)
+	phase := q.X + mt.Turbulence*mt.Perlin.Turbulence(q, mt.Octaves)
+	return blend(mt.FirstColor, mt.SecondColor, 0.5+0.5*math.Sin(2.0*math.Pi*phase))
+}
@@ -0,0 +1,18 @@
+package texture
+
+import (
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// Mix holds information about a texture node blending between two other textures by a mask
+type Mix struct {
+	First  Texture `json:"-"`
+	Second Texture `json:"-"`
+	Mask   Texture `json:"-"` // fraction of the way to the second texture, given by the mask's luminance
+}
+
+// Value returns the blend of both textures' colors at the mask's luminance
+func (mt *Mix) Value(u, v float64, p geometry.Point) shading.Color {
+	return blend(mt.First.Value(u, v, p), mt.Second.Value(u, v, p), mt.Mask.Value(u, v, p).Luminance())
+}
@@ -0,0 +1,17 @@
+package texture
+
+import (
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// Multiply holds information about a texture node whose color is the product of two other textures
+type Multiply struct {
+	First  Texture `json:"-"`
+	Second Texture `json:"-"`
+}
+
+// Value returns the product of both textures' colors, channel by channel
+func (mt *Multiply) Value(u, v float64, p geometry.Point) shading.Color {
+	return mt.First.Value(u, v, p).MultColor(mt.Second.Value(u, v, p))
+}
@@ -0,0 +1,21 @@
+package texture
+
+import (
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// Noise holds information about a solid texture blending between two colors by fractal Perlin noise
+type Noise struct {
+	FirstColor  shading.Color `json:"first_color"`
+	SecondColor shading.Color `json:"second_color"`
+	Scale       float64       `json:"scale"`   // frequency of the noise
+	Octaves     int           `json:"octaves"` // layers of noise, where a single octave is plain Perlin noise
+	Perlin      *Perlin       `json:"-"`
+}
+
+// Value returns the blend of the colors at the given point in space
+func (nt *Noise) Value(u, v float64, p geometry.Point) shading.Color {
+	noise := nt.Perlin.FBM(scale(p, nt.Scale), nt.Octaves)
+	return blend(nt.FirstColor, nt.SecondColor, 0.5*noise+0.5)
+}
@@ -0,0 +1,115 @@
+package texture
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+)
+
+// Perlin generates smooth, continuous noise throughout space, from which procedural patterns are built
+type Perlin struct {
+	permutation [512]int
+}
+
+// NewPerlin returns a noise generator whose pattern is determined by seed
+func NewPerlin(seed int64) *Perlin {
+	p := &Perlin{}
+	rng := rand.New(rand.NewSource(seed))
+	for i, v := range rng.Perm(256) {
+		p.permutation[i] = v
+		p.permutation[i+256] = v
+	}
+	return p
+}
+
+// Noise returns the noise at point q, in about [-1, 1]
+func (p *Perlin) Noise(q geometry.Point) float64 {
+	floorX, floorY, floorZ := math.Floor(q.X), math.Floor(q.Y), math.Floor(q.Z)
+	x, y, z := q.X-floorX, q.Y-floorY, q.Z-floorZ
+	cellX, cellY, cellZ := int(floorX)&255, int(floorY)&255, int(floorZ)&255
+	u, v, w := fade(x), fade(y), fade(z)
+
+	a := p.permutation[cellX] + cellY
+	aa := p.permutation[a] + cellZ
+	ab := p.permutation[a+1] + cellZ
+	b := p.permutation[cellX+1] + cellY
+	ba := p.permutation[b] + cellZ
+	bb := p.permutation[b+1] + cellZ
+
+	return lerp(w,
+		lerp(v,
+			lerp(u, gradient(p.permutation[aa], x, y, z), gradient(p.permutation[ba], x-1, y, z)),
+			lerp(u, gradient(p.permutation[ab], x, y-1, z), gradient(p.permutation[bb], x-1, y-1, z))),
+		lerp(v,
+			lerp(u, gradient(p.permutation[aa+1], x, y, z-1), gradient(p.permutation[ba+1], x-1, y, z-1)),
+			lerp(u, gradient(p.permutation[ab+1], x, y-1, z-1), gradient(p.permutation[bb+1], x-1, y-1, z-1))))
+}
+
+// FBM returns fractal Brownian motion at point q: octaves of noise, each at twice the frequency
+// and half the amplitude of the last, normalized to stay in about [-1, 1]
+func (p *Perlin) FBM(q geometry.Point, octaves int) float64 {
+	sum := 0.0
+	amplitude := 1.0
+	totalAmplitude := 0.0
+	for i := 0; i < octaves; i++ {
+		sum += amplitude * p.Noise(q)
+		totalAmplitude += amplitude
+		q = geometry.Point{X: q.X * 2.0, Y: q.Y * 2.0, Z: q.Z * 2.0}
+		amplitude *= 0.5
+	}
+	if totalAmplitude == 0.0 {
+		return 0.0
+	}
+	return sum / totalAmplitude
+}
+
+// Turbulence returns octaves of the magnitude of noise at point q, each at twice the frequency
+// and half the amplitude of the last, normalized to stay in about [0, 1]
+// the creases where the noise changes sign give it a sharper, more turbulent look than FBM
+func (p *Perlin) Turbulence(q geometry.Point, octaves int) float64 {
+	sum := 0.0
+	amplitude := 1.0
+	totalAmplitude := 0.0
+	for i := 0; i < octaves; i++ {
+		sum += amplitude * math.Abs(p.Noise(q))
+		totalAmplitude += amplitude
+		q = geometry.Point{X: q.X * 2.0, Y: q.Y * 2.0, Z: q.Z * 2.0}
+		amplitude *= 0.5
+	}
+	if totalAmplitude == 0.0 {
+		return 0.0
+	}
+	return sum / totalAmplitude
+}
+
+// fade eases t so the noise is smooth across the boundaries of its lattice cells
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6.0-15.0) + 10.0)
+}
+
+func lerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
+
+// gradient returns the dot product of (x, y, z) with one of twelve gradient directions chosen by hash
+func gradient(hash int, x, y, z float64) float64 {
+	h := hash & 15
+	u := y
+	if h < 8 {
+		u = x
+	}
+	v := z
+	if h < 4 {
+		v = y
+	} else if h == 12 || h == 14 {
+		v = x
+	}
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
@@ -0,0 +1,401 @@
+package texture
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// constants of OpenEXR's PIZ compression, which maps the values present in a block onto a dense range,
+// transforms each channel with a Haar wavelet, then Huffman codes the result
+const (
+	pizBitmapSize = 1 << 16 / 8
+
+	hufEncodeBits       = 16
+	hufDecodeBits       = 14
+	hufEncodeSize       = 1<<hufEncodeBits + 1
+	hufDecodeSize       = 1 << hufDecodeBits
+	hufDecodeMask       = hufDecodeSize - 1
+	hufMaxCodeLength    = 58
+	hufShortZeroCodeRun = 59
+	hufLongZeroCodeRun  = 63
+	hufShortestLongRun  = 2 + hufLongZeroCodeRun - hufShortZeroCodeRun
+)
+
+// decompressPIZ returns the pixels of a PIZ compressed block, line by line, and channel by channel within each line
+func decompressPIZ(compressed []byte, channels []exrChannel, width, lines int) ([]byte, error) {
+	if len(compressed) < 4 {
+		return nil, fmt.Errorf("exr piz data is truncated")
+	}
+	// a bitmap of which 16-bit values appear in the block, trimmed to the bytes between the first and last set
+	minNonZero := int(binary.LittleEndian.Uint16(compressed))
+	maxNonZero := int(binary.LittleEndian.Uint16(compressed[2:]))
+	compressed = compressed[4:]
+	if maxNonZero >= pizBitmapSize {
+		return nil, fmt.Errorf("invalid exr piz bitmap")
+	}
+	bitmap := make([]byte, pizBitmapSize)
+	if minNonZero <= maxNonZero {
+		n := maxNonZero - minNonZero + 1
+		if len(compressed) < n {
+			return nil, fmt.Errorf("exr piz data is truncated")
+		}
+		copy(bitmap[minNonZero:], compressed[:n])
+		compressed = compressed[n:]
+	}
+	lut, maxValue := pizReverseLUT(bitmap)
+
+	if len(compressed) < 4 {
+		return nil, fmt.Errorf("exr piz data is truncated")
+	}
+	length := int(int32(binary.LittleEndian.Uint32(compressed)))
+	compressed = compressed[4:]
+	if length < 0 || length > len(compressed) {
+		return nil, fmt.Errorf("exr piz data is truncated")
+	}
+	// the block is coded as 16-bit words, a channel at a time, where a 32-bit sample is two words
+	total := 0
+	for _, channel := range channels {
+		total += width * lines * channel.size() / 2
+	}
+	words := make([]uint16, total)
+	if err := hufDecompress(compressed[:length], words); err != nil {
+		return nil, err
+	}
+
+	starts := make([]int, len(channels))
+	start := 0
+	for i, channel := range channels {
+		starts[i] = start
+		n := channel.size() / 2
+		for j := 0; j < n; j++ {
+			wav2Decode(words[start+j:], width, n, lines, width*n, maxValue)
+		}
+		start += width * lines * n
+	}
+	for i, word := range words {
+		words[i] = lut[word]
+	}
+
+	pixels := make([]byte, 0, 2*total)
+	for line := 0; line < lines; line++ {
+		for i, channel := range channels {
+			n := width * channel.size() / 2
+			for _, word := range words[starts[i]+line*n : starts[i]+(line+1)*n] {
+				pixels = append(pixels, byte(word), byte(word>>8))
+			}
+		}
+	}
+	return pixels, nil
+}
+
+// pizReverseLUT returns the table mapping the dense range back onto the values set in the bitmap, and the range's maximum
+// zero is always mapped, whether or not it's set
+func pizReverseLUT(bitmap []byte) ([]uint16, uint16) {
+	lut := make([]uint16, 1<<16)
+	k := 0
+	for i := 0; i < 1<<16; i++ {
+		if i == 0 || bitmap[i>>3]&(1<<uint(i&7)) != 0 {
+			lut[k] = uint16(i)
+			k++
+		}
+	}
+	return lut, uint16(k - 1)
+}
+
+// hufDecoder is an entry of the Huffman decoding table, indexed by the next hufDecodeBits bits,
+// holding either a short code's length and symbol, or the symbols of the longer codes beginning with those bits
+type hufDecoder struct {
+	length     int
+	symbol     int
+	candidates []int
+}
+
+// hufDecompress decodes Huffman coded data into words, which it must fill exactly
+func hufDecompress(compressed []byte, words []uint16) error {
+	if len(compressed) == 0 {
+		if len(words) != 0 {
+			return fmt.Errorf("exr piz data is truncated")
+		}
+		return nil
+	}
+	if len(compressed) < 20 {
+		return fmt.Errorf("exr piz data is truncated")
+	}
+	minSymbol := int(binary.LittleEndian.Uint32(compressed))
+	maxSymbol := int(binary.LittleEndian.Uint32(compressed[4:]))
+	bitCount := int(binary.LittleEndian.Uint32(compressed[12:]))
+	if minSymbol < 0 || maxSymbol >= hufEncodeSize || minSymbol > maxSymbol {
+		return fmt.Errorf("invalid exr huffman table")
+	}
+	codes := make([]uint64, hufEncodeSize)
+	data, err := hufUnpackCodes(compressed[20:], minSymbol, maxSymbol, codes)
+	if err != nil {
+		return err
+	}
+	if bitCount < 0 || (bitCount+7)/8 > len(data) {
+		return fmt.Errorf("exr piz data is truncated")
+	}
+	table, err := hufBuildDecodingTable(codes, minSymbol, maxSymbol)
+	if err != nil {
+		return err
+	}
+	// the largest symbol is a run code, repeating the last symbol by the count in the following byte
+	return hufDecode(codes, table, data[:(bitCount+7)/8], bitCount, maxSymbol, words)
+}
+
+// hufUnpackCodes reads the code length of each symbol from minSymbol to maxSymbol into codes, and assigns them canonical codes,
+// returning the data after the lengths
+// each length is six bits, with the values above the longest code length instead counting runs of symbols without codes
+func hufUnpackCodes(data []byte, minSymbol, maxSymbol int, codes []uint64) ([]byte, error) {
+	var c uint64
+	bits, pos := 0, 0
+	read := func(n int) (int, error) {
+		for bits < n {
+			if pos >= len(data) {
+				return 0, fmt.Errorf("invalid exr huffman table")
+			}
+			c = c<<8 | uint64(data[pos])
+			pos++
+			bits += 8
+		}
+		bits -= n
+		return int(c>>uint(bits)) & (1<<uint(n) - 1), nil
+	}
+	for symbol := minSymbol; symbol <= maxSymbol; symbol++ {
+		length, err := read(6)
+		if err != nil {
+			return nil, err
+		}
+		zeroRun := 0
+		if length == hufLongZeroCodeRun {
+			run, err := read(8)
+			if err != nil {
+				return nil, err
+			}
+			zeroRun = run + hufShortestLongRun
+		} else if length >= hufShortZeroCodeRun {
+			zeroRun = length - hufShortZeroCodeRun + 2
+		} else {
+			codes[symbol] = uint64(length)
+			continue
+		}
+		if symbol+zeroRun > maxSymbol+1 {
+			return nil, fmt.Errorf("invalid exr huffman table")
+		}
+		symbol += zeroRun - 1
+	}
+	hufCanonicalCodes(codes)
+	return data[pos:], nil
+}
+
+// hufCanonicalCodes replaces each code length with the canonical code of that length, above the length in the low six bits
+// longer codes are numbered first, so a code's value never needs more bits than its length
+func hufCanonicalCodes(codes []uint64) {
+	var next [hufMaxCodeLength + 1]uint64
+	for _, length := range codes {
+		next[length]++
+	}
+	c := uint64(0)
+	for length := hufMaxCodeLength; length > 0; length-- {
+		nc := (c + next[length]) >> 1
+		next[length] = c
+		c = nc
+	}
+	for i, length := range codes {
+		if length > 0 {
+			codes[i] = length | next[length]<<6
+			next[length]++
+		}
+	}
+}
+
+// hufBuildDecodingTable builds the table decoding the codes of the symbols from minSymbol to maxSymbol
+func hufBuildDecodingTable(codes []uint64, minSymbol, maxSymbol int) ([]hufDecoder, error) {
+	table := make([]hufDecoder, hufDecodeSize)
+	for symbol := minSymbol; symbol <= maxSymbol; symbol++ {
+		code, length := codes[symbol]>>6, int(codes[symbol]&63)
+		if code>>uint(length) != 0 {
+			return nil, fmt.Errorf("invalid exr huffman table")
+		}
+		if length > hufDecodeBits {
+			// long codes share the entry of their first bits, and are told apart when decoding
+			entry := &table[code>>uint(length-hufDecodeBits)]
+			if entry.length != 0 {
+				return nil, fmt.Errorf("invalid exr huffman table")
+			}
+			entry.candidates = append(entry.candidates, symbol)
+		} else if length > 0 {
+			// short codes fill every entry they're a prefix of
+			start := code << uint(hufDecodeBits-length)
+			for i := uint64(0); i < 1<<uint(hufDecodeBits-length); i++ {
+				entry := &table[start+i]
+				if entry.length != 0 || entry.candidates != nil {
+					return nil, fmt.Errorf("invalid exr huffman table")
+				}
+				entry.length = length
+				entry.symbol = symbol
+			}
+		}
+	}
+	return table, nil
+}
+
+// hufDecode decodes bitCount bits of data into words
+func hufDecode(codes []uint64, table []hufDecoder, data []byte, bitCount, runSymbol int, words []uint16) error {
+	var c uint64
+	bits, pos, n := 0, 0, 0
+	emit := func(symbol int) error {
+		if symbol != runSymbol {
+			if n >= len(words) {
+				return fmt.Errorf("exr piz data decoded to the wrong size")
+			}
+			words[n] = uint16(symbol)
+			n++
+			return nil
+		}
+		if bits < 8 {
+			if pos >= len(data) {
+				return fmt.Errorf("exr piz data is truncated")
+			}
+			c = c<<8 | uint64(data[pos])
+			pos++
+			bits += 8
+		}
+		bits -= 8
+		count := int(byte(c >> uint(bits)))
+		if n == 0 || n+count > len(words) {
+			return fmt.Errorf("exr piz data decoded to the wrong size")
+		}
+		for ; count > 0; count-- {
+			words[n] = words[n-1]
+			n++
+		}
+		return nil
+	}
+
+	for pos < len(data) {
+		c = c<<8 | uint64(data[pos])
+		pos++
+		bits += 8
+		for bits >= hufDecodeBits {
+			entry := table[(c>>uint(bits-hufDecodeBits))&hufDecodeMask]
+			if entry.length != 0 {
+				bits -= entry.length
+				if err := emit(entry.symbol); err != nil {
+					return err
+				}
+				continue
+			}
+			if entry.candidates == nil {
+				return fmt.Errorf("invalid exr huffman code")
+			}
+			found := false
+			for _, symbol := range entry.candidates {
+				length := int(codes[symbol] & 63)
+				for bits < length && pos < len(data) {
+					c = c<<8 | uint64(data[pos])
+					pos++
+					bits += 8
+				}
+				if bits >= length && codes[symbol]>>6 == (c>>uint(bits-length))&(1<<uint(length)-1) {
+					bits -= length
+					if err := emit(symbol); err != nil {
+						return err
+					}
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("invalid exr huffman code")
+			}
+		}
+	}
+
+	// the remaining bits are too few to index the table directly, and the last byte is padded
+	padding := (8 - bitCount) & 7
+	c >>= uint(padding)
+	bits -= padding
+	for bits > 0 {
+		entry := table[(c<<uint(hufDecodeBits-bits))&hufDecodeMask]
+		if entry.length == 0 || entry.length > bits {
+			return fmt.Errorf("invalid exr huffman code")
+		}
+		bits -= entry.length
+		if err := emit(entry.symbol); err != nil {
+			return err
+		}
+	}
+	if n != len(words) {
+		return fmt.Errorf("exr piz data decoded to the wrong size")
+	}
+	return nil
+}
+
+// wav2Decode reverses the two-dimensional Haar wavelet transform of nx by ny words, ox apart in x and oy apart in y
+// the 14-bit transform is used when every value fits in it, as it's slightly better, and otherwise the modular 16-bit one
+func wav2Decode(words []uint16, nx, ox, ny, oy int, maxValue uint16) {
+	decode := wdec16
+	if maxValue < 1<<14 {
+		decode = wdec14
+	}
+	n := nx
+	if ny < n {
+		n = ny
+	}
+	p := 1
+	for p <= n {
+		p <<= 1
+	}
+	p >>= 1
+	p2 := p
+	p >>= 1
+
+	// undo the levels from coarsest to finest
+	for p >= 1 {
+		ox1, oy1 := ox*p, oy*p
+		ox2, oy2 := ox*p2, oy*p2
+		py := 0
+		for ey := oy * (ny - p2); py <= ey; py += oy2 {
+			px := py
+			for ex := py + ox*(nx-p2); px <= ex; px += ox2 {
+				p01, p10 := px+ox1, px+oy1
+				p11 := p10 + ox1
+				i00, i10 := decode(words[px], words[p10])
+				i01, i11 := decode(words[p01], words[p11])
+				words[px], words[p01] = decode(i00, i01)
+				words[p10], words[p11] = decode(i10, i11)
+			}
+			// an odd column at the end
+			if nx&p != 0 {
+				p10 := px + oy1
+				words[px], words[p10] = decode(words[px], words[p10])
+			}
+		}
+		// an odd row at the end
+		if ny&p != 0 {
+			px := py
+			for ex := py + ox*(nx-p2); px <= ex; px += ox2 {
+				p01 := px + ox1
+				words[px], words[p01] = decode(words[px], words[p01])
+			}
+		}
+		p2 = p
+		p >>= 1
+	}
+}
+
+// wdec14 returns the pair whose average and difference, as signed 14-bit values, are l and h
+func wdec14(l, h uint16) (uint16, uint16) {
+	hi := int(int16(h))
+	ai := int(int16(l)) + (hi & 1) + (hi >> 1)
+	return uint16(int16(ai)), uint16(int16(ai - hi))
+}
+
+// wdec16 returns the pair whose average and difference, modulo 2^16, are l and h
+func wdec16(l, h uint16) (uint16, uint16) {
+	m, d := int(l), int(h)
+	b := (m - (d >> 1)) & 0xffff
+	a := (d + b - 0x8000) & 0xffff
+	return uint16(a), uint16(b)
+}
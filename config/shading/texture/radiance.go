@@ -0,0 +1,183 @@
+package texture
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// radianceMagics are the signatures a Radiance RGBE (.hdr) file may begin with
+var radianceMagics = [][]byte{[]byte("#?RADIANCE"), []byte("#?RGBE")}
+
+// radianceMaximumPixelsPerByte bounds how many pixels each byte of a Radiance RGBE file may hold,
+// a little beyond the 127 pixels in every 8 bytes of its densest run-length encoding, so a small file can't claim an enormous image
+const radianceMaximumPixelsPerByte = 16
+
+// isRadiance returns whether data looks like a Radiance RGBE image
+func isRadiance(data []byte) bool {
+	for _, magic := range radianceMagics {
+		if bytes.HasPrefix(data, magic) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeRadiance decodes a Radiance RGBE image, with either flat or run-length encoded scanlines, into linear texels
+func decodeRadiance(data []byte) (raster, error) {
+	// the header is a list of lines ended by a blank one, followed by a line giving the resolution
+	pos := 0
+	nextLine := func() (string, error) {
+		end := bytes.IndexByte(data[pos:], '\n')
+		if end < 0 {
+			return "", fmt.Errorf("radiance header is truncated")
+		}
+		line := string(data[pos : pos+end])
+		pos += end + 1
+		return strings.TrimSpace(line), nil
+	}
+	for {
+		line, err := nextLine()
+		if err != nil {
+			return raster{}, err
+		}
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, "FORMAT=") && line != "FORMAT=32-bit_rle_rgbe" {
+			return raster{}, fmt.Errorf("unsupported radiance format %s", strings.TrimPrefix(line, "FORMAT="))
+		}
+	}
+	line, err := nextLine()
+	if err != nil {
+		return raster{}, err
+	}
+	// only rows running down or up the image are supported, as almost every file has them running down
+	fields := strings.Fields(line)
+	if len(fields) != 4 || (fields[0] != "-Y" && fields[0] != "+Y") || fields[2] != "+X" {
+		return raster{}, fmt.Errorf("unsupported radiance resolution %q", line)
+	}
+	height, heightErr := strconv.Atoi(fields[1])
+	width, widthErr := strconv.Atoi(fields[3])
+	if heightErr != nil || widthErr != nil || width <= 0 || height <= 0 {
+		return raster{}, fmt.Errorf("invalid radiance resolution %q", line)
+	}
+	isBottomUp := fields[0] == "+Y"
+	maximumPixels := (len(data) - pos) * radianceMaximumPixelsPerByte
+	if width > maximumPixels || height > maximumPixels/width {
+		return raster{}, fmt.Errorf("radiance resolution is too large for the file")
+	}
+
+	decoded := raster{
+		width:  width,
+		height: height,
+		texels: make([]float32, 3*width*height),
+	}
+	scanline := make([]byte, 4*width)
+	for row := 0; row < height; row++ {
+		pos, err = readRadianceScanline(data, pos, scanline)
+		if err != nil {
+			return raster{}, err
+		}
+		y := row
+		if isBottomUp {
+			y = height - 1 - row
+		}
+		for x := 0; x < width; x++ {
+			r, g, b, e := scanline[4*x], scanline[4*x+1], scanline[4*x+2], scanline[4*x+3]
+			if e == 0 {
+				continue
+			}
+			scale := math.Ldexp(1.0, int(e)-(128+8))
+			i := 3 * (y*width + x)
+			decoded.texels[i] = float32((float64(r) + 0.5) * scale)
+			decoded.texels[i+1] = float32((float64(g) + 0.5) * scale)
+			decoded.texels[i+2] = float32((float64(b) + 0.5) * scale)
+		}
+	}
+	return decoded, nil
+}
+
+// readRadianceScanline reads the scanline at pos into the RGBE quadruplets of scanline, returning the position after it
+// scanlines of a reasonable width are usually run-length encoded a component at a time,
+// but others are stored flat, possibly with the older encoding of runs of whole pixels
+func readRadianceScanline(data []byte, pos int, scanline []byte) (int, error) {
+	width := len(scanline) / 4
+	if pos+4 > len(data) {
+		return 0, fmt.Errorf("radiance pixel data is truncated")
+	}
+	if width < 8 || width > 0x7fff || data[pos] != 2 || data[pos+1] != 2 || data[pos+2]&0x80 != 0 {
+		return readFlatRadianceScanline(data, pos, scanline)
+	}
+	if int(data[pos+2])<<8|int(data[pos+3]) != width {
+		return 0, fmt.Errorf("radiance scanline width does not match the image")
+	}
+	pos += 4
+	for component := 0; component < 4; component++ {
+		for x := 0; x < width; {
+			if pos >= len(data) {
+				return 0, fmt.Errorf("radiance pixel data is truncated")
+			}
+			count := int(data[pos])
+			pos++
+			if count > 128 {
+				// a run of a single value
+				count -= 128
+				if x+count > width || pos >= len(data) {
+					return 0, fmt.Errorf("invalid radiance scanline run")
+				}
+				for ; count > 0; count-- {
+					scanline[4*x+component] = data[pos]
+					x++
+				}
+				pos++
+			} else {
+				// a run of literal values
+				if count == 0 || x+count > width || pos+count > len(data) {
+					return 0, fmt.Errorf("invalid radiance scanline run")
+				}
+				for ; count > 0; count-- {
+					scanline[4*x+component] = data[pos]
+					x++
+					pos++
+				}
+			}
+		}
+	}
+	return pos, nil
+}
+
+// readFlatRadianceScanline reads a scanline stored as whole pixels,
+// where a pixel of (1, 1, 1, n) repeats the one before it n times, with consecutive repeats counting in higher bytes
+func readFlatRadianceScanline(data []byte, pos int, scanline []byte) (int, error) {
+	width := len(scanline) / 4
+	shift := uint(0)
+	for x := 0; x < width; {
+		if pos+4 > len(data) {
+			return 0, fmt.Errorf("radiance pixel data is truncated")
+		}
+		pixel := data[pos : pos+4]
+		pos += 4
+		if pixel[0] == 1 && pixel[1] == 1 && pixel[2] == 1 {
+			if x == 0 {
+				return 0, fmt.Errorf("invalid radiance scanline run")
+			}
+			count := int(pixel[3]) << shift
+			if x+count > width {
+				return 0, fmt.Errorf("invalid radiance scanline run")
+			}
+			for ; count > 0; count-- {
+				copy(scanline[4*x:4*x+4], scanline[4*x-4:4*x])
+				x++
+			}
+			shift += 8
+			continue
+		}
+		copy(scanline[4*x:4*x+4], pixel)
+		x++
+		shift = 0
+	}
+	return pos, nil
+}
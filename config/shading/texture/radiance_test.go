@@ -0,0 +1,63 @@
+package texture
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDecodeRadianceRunLength(t *testing.T) {
+	// one scanline of eight pixels, each component a run, except red, which is literal
+	data := []byte("#?RADIANCE\nFORMAT=32-bit_rle_rgbe\n\n-Y 1 +X 8\n")
+	data = append(data, 2, 2, 0, 8)
+	data = append(data, 8, 0, 32, 64, 96, 128, 160, 192, 224)
+	data = append(data, 128+8, 128)
+	data = append(data, 128+8, 0)
+	data = append(data, 128+8, 129)
+	level, err := decodeRadiance(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level.width != 8 || level.height != 1 {
+		t.Fatalf("Expected an 8x1 image but got %dx%d\n", level.width, level.height)
+	}
+	for x := 0; x < 8; x++ {
+		// an exponent of 129 scales each component by 2^-7
+		red := (float64(32*x) + 0.5) / 128.0
+		green := 128.5 / 128.0
+		if math.Abs(float64(level.texels[3*x])-red) > 1e-6 || math.Abs(float64(level.texels[3*x+1])-green) > 1e-6 {
+			t.Errorf("Expected (%f, %f) at %d but got %v\n", red, green, x, level.texels[3*x:3*x+3])
+		}
+	}
+}
+
+func TestDecodeRadianceFlatBottomUp(t *testing.T) {
+	// a zero exponent is black, and the pixel of ones repeats the one before it
+	data := []byte("#?RGBE\n\n+Y 2 +X 2\n")
+	data = append(data, 128, 64, 0, 128, 0, 0, 0, 0)
+	data = append(data, 128, 128, 128, 130, 1, 1, 1, 1)
+	level, err := decodeRadiance(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []float32{2.0078125, 2.0078125, 2.0078125, 2.0078125, 2.0078125, 2.0078125, 0.501953125, 0.251953125, 0.001953125, 0.0, 0.0, 0.0}
+	for i, value := range expected {
+		if level.texels[i] != value {
+			t.Errorf("Expected texels %v but got %v\n", expected, level.texels)
+			break
+		}
+	}
+}
+
+func TestDecodeRadianceRejectsXYZE(t *testing.T) {
+	data := []byte("#?RADIANCE\nFORMAT=32-bit_rle_xyze\n\n-Y 1 +X 1\n\x80\x80\x80\x80")
+	if _, err := decodeRadiance(data); err == nil {
+		t.Error("Expected an error decoding an XYZE image\n")
+	}
+}
+
+func TestDecodeRadianceRejectsResolutionBeyondFile(t *testing.T) {
+	data := []byte("#?RADIANCE\nFORMAT=32-bit_rle_rgbe\n\n-Y 2000000000 +X 2000000000\n\x80\x80\x80\x80")
+	if _, err := decodeRadiance(data); err == nil {
+		t.Error("Expected an error decoding an image larger than its file could hold\n")
+	}
+}
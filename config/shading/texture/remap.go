@@ -0,0 +1,38 @@
+package texture
+
+import (
+	"math"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// Remap holds information about a texture node which linearly maps each channel of another texture
+// from an input range to an output range, optionally clamping the result to the output range
+type Remap struct {
+	Texture   Texture `json:"-"`
+	InputMin  float64 `json:"input_min"`
+	InputMax  float64 `json:"input_max"`
+	OutputMin float64 `json:"output_min"`
+	OutputMax float64 `json:"output_max"`
+	IsClamped bool    `json:"is_clamped"`
+}
+
+// Value returns the remapped color of the texture
+func (rt *Remap) Value(u, v float64, p geometry.Point) shading.Color {
+	c := rt.Texture.Value(u, v, p)
+	return shading.Color{
+		Red:   rt.remap(c.Red),
+		Green: rt.remap(c.Green),
+		Blue:  rt.remap(c.Blue),
+	}
+}
+
+// remap maps a single channel
+func (rt *Remap) remap(x float64) float64 {
+	t := (x - rt.InputMin) / (rt.InputMax - rt.InputMin)
+	if rt.IsClamped {
+		t = math.Max(0.0, math.Min(1.0, t))
+	}
+	return rt.OutputMin + t*(rt.OutputMax-rt.OutputMin)
+}
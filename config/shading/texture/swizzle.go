@@ -0,0 +1,44 @@
+package texture
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// Swizzle holds information about a texture node which rearranges the channels of another texture
+// the channels are given as three of R, G, and B, such as "BGR" to swap red and blue, or "RRR" to spread red to gray
+type Swizzle struct {
+	Texture  Texture `json:"-"`
+	Channels string  `json:"channels"`
+
+	sources [3]int
+}
+
+// Setup sets up the swizzle's internal fields
+func (st *Swizzle) Setup() (*Swizzle, error) {
+	if len(st.Channels) != 3 {
+		return nil, fmt.Errorf("swizzle must have three channels")
+	}
+	for i, channel := range strings.ToUpper(st.Channels) {
+		source := strings.IndexRune("RGB", channel)
+		if source < 0 {
+			return nil, fmt.Errorf("invalid swizzle channel %c", channel)
+		}
+		st.sources[i] = source
+	}
+	return st, nil
+}
+
+// Value returns the texture's color with its channels rearranged
+func (st *Swizzle) Value(u, v float64, p geometry.Point) shading.Color {
+	c := st.Texture.Value(u, v, p)
+	channels := [3]float64{c.Red, c.Green, c.Blue}
+	return shading.Color{
+		Red:   channels[st.sources[0]],
+		Green: channels[st.sources[1]],
+		Blue:  channels[st.sources[2]],
+	}
+}
@@ -0,0 +1,30 @@
+package texture
+
+import (
+	"testing"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+func TestSwizzleRearrangesChannels(t *testing.T) {
+	st, err := (&Swizzle{
+		Texture:  &Color{Color: shading.Color{Red: 0.1, Green: 0.2, Blue: 0.3}},
+		Channels: "bgr",
+	}).Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := shading.Color{Red: 0.3, Green: 0.2, Blue: 0.1}
+	if result := st.Value(0.0, 0.0, geometry.Point{}); result != expected {
+		t.Errorf("Expected %v but got %v\n", expected, result)
+	}
+}
+
+func TestSwizzleRejectsInvalidChannels(t *testing.T) {
+	for _, channels := range []string{"RG", "RGBA", "RGX"} {
+		if _, err := (&Swizzle{Channels: channels}).Setup(); err == nil {
+			t.Errorf("Expected an error setting up a swizzle of %q\n", channels)
+		}
+	}
+}
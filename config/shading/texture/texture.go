@@ -1,8 +1,25 @@
 package texture
 
-import "github.com/paulwrubel/photolum/config/shading"
+import (
+	"math"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
 
 // Texture defines behaviors of a Texture implementation
+// textures are looked up by texture coordinates (u, v), or by the point p in space for solid textures
 type Texture interface {
-	Value(u, v float64) shading.Color
+	Value(u, v float64, p geometry.Point) shading.Color
+}
+
+// blend returns the color a fraction t of the way from first to second, with t clamped to [0, 1]
+func blend(first, second shading.Color, t float64) shading.Color {
+	t = math.Max(0.0, math.Min(1.0, t))
+	return first.MultScalar(1.0 - t).Add(second.MultScalar(t))
+}
+
+// scale returns p with each of its coordinates multiplied by s
+func scale(p geometry.Point, s float64) geometry.Point {
+	return geometry.Point{X: p.X * s, Y: p.Y * s, Z: p.Z * s}
 }
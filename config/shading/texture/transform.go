@@ -0,0 +1,35 @@
+package texture
+
+import (
+	"math"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// Transform holds information about a texture looked up by transformed texture coordinates,
+// which are scaled, then rotated about the origin, then offset
+type Transform struct {
+	Texture  Texture    `json:"-"`
+	Scale    [2]float64 `json:"scale"`
+	Offset   [2]float64 `json:"offset"`
+	Rotation float64    `json:"rotation"` // counterclockwise, in degrees
+
+	sinRotation float64
+	cosRotation float64
+}
+
+// Setup sets up the transform's internal fields
+func (tt *Transform) Setup() (*Transform, error) {
+	radians := tt.Rotation * math.Pi / 180.0
+	tt.sinRotation = math.Sin(radians)
+	tt.cosRotation = math.Cos(radians)
+	return tt, nil
+}
+
+// Value returns the color of the underlying texture at the transformed texture coordinates
+func (tt *Transform) Value(u, v float64, p geometry.Point) shading.Color {
+	u, v = u*tt.Scale[0], v*tt.Scale[1]
+	u, v = u*tt.cosRotation-v*tt.sinRotation, u*tt.sinRotation+v*tt.cosRotation
+	return tt.Texture.Value(u+tt.Offset[0], v+tt.Offset[1], p)
+}
@@ -0,0 +1,26 @@
+package texture
+
+import (
+	"math"
+
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// Wood holds information about a solid texture of growth rings around the y axis,
+// each blending from one color to the other and warped by turbulence
+type Wood struct {
+	FirstColor  shading.Color `json:"first_color"`
+	SecondColor shading.Color `json:"second_color"`
+	Scale       float64       `json:"scale"`      // rings per unit distance
+	Octaves     int           `json:"octaves"`    // layers of noise in the turbulence
+	Turbulence  float64       `json:"turbulence"` // how far the rings are warped, in multiples of the distance between them
+	Perlin      *Perlin       `json:"-"`
+}
+
+// Value returns the blend of the colors at the given point in space
+func (wt *Wood) Value(u, v float64, p geometry.Point) shading.Color {
+	q := scale(p, wt.Scale)
+	radius := math.Sqrt(q.X*q.X+q.Z*q.Z) + wt.Turbulence*wt.Perlin.Turbulence(q, wt.Octaves)
+	return blend(wt.FirstColor, wt.SecondColor, radius-math.Floor(radius))
+}
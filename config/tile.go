@@ -6,4 +6,8 @@ type Tile struct {
 	ID     string
 	Origin geometry.Point  // Top left corner of Tile
 	Span   geometry.Vector // Width and Height of Tile
+
+	// the view of the camera the tile lies within, which its samples are kept to
+	ViewOrigin geometry.Point
+	ViewSpan   geometry.Vector
 }
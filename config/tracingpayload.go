@@ -9,4 +9,6 @@ import (
 type TracingPayload struct {
 	FileType filetype.FileType
 	Image    image.Image
+	Heatmap  image.Image     // per-pixel sample counts, only present with adaptive sampling
+	Crop     image.Rectangle // part of the images to encode, or empty to encode all of them
 }
@@ -12,10 +12,85 @@ var ParametersMinimumTotalPixels uint32 = 100
 var ParametersMaximumTotalPixels uint32 = 25000000
 var ParametersMaximumMaxBounces uint32 = 100
 var ParametersMaximumTMax float64 = math.MaxFloat64
+var ParametersDefaultReconstructionFilter string = "BOX"
+var ParametersDefaultFilterRadius float64 = 0.5
+var ParametersMaximumFilterRadius float64 = 4.0
+var ParametersGaussianFilterAlpha float64 = 2.0
+var ParametersMitchellFilterB float64 = 1.0 / 3.0
+var ParametersMitchellFilterC float64 = 1.0 / 3.0
+var ParametersDefaultAdaptiveThreshold float64 = 0.01
+var ParametersDefaultAdaptiveMinimumSamples uint32 = 32
+var ParametersDefaultBackgroundType string = "COLOR"
+var ParametersDefaultEnvironmentRotation float64 = 0.0
+var ParametersDefaultEnvironmentIntensity float64 = 1.0
+var ParametersDefaultTurbidity float64 = 3.0
+var ParametersMinimumTurbidity float64 = 1.7
+var ParametersMaximumTurbidity float64 = 10.0
+var ParametersDefaultGroundAlbedo float64 = 0.2
+var ParametersDefaultSkyIntensity float64 = 0.1
+var ParametersDefaultSunIntensity float64 = 10.0
+var ParametersDefaultSunAngularDiameter float64 = 0.53
+var ParametersDefaultFogAlbedo float64 = 1.0
+var ParametersDefaultFogAnisotropy float64 = 0.0
 
+var MaterialDefaultPrincipledBaseColor float64 = 0.8
+var MaterialDefaultPrincipledMetallic float64 = 0.0
+var MaterialDefaultPrincipledRoughness float64 = 0.5
+var MaterialDefaultPrincipledSpecular float64 = 0.5
+var MaterialDefaultPrincipledClearcoat float64 = 0.0
+var MaterialDefaultPrincipledSheen float64 = 0.0
+var MaterialDefaultPrincipledTransmission float64 = 0.0
+var MaterialDefaultPrincipledEmission float64 = 0.0
+var MaterialDefaultPrincipledRefractiveIndex float64 = 1.5
+var MaterialDefaultLightIsTwoSided bool = false
+var MaterialDefaultLightSpotAngle float64 = 90.0
+var MaterialDefaultLightSpotBlend float64 = 0.0
+var MaterialDefaultLightIsVisibleToCamera bool = true
+var MaterialDefaultBumpStrength float64 = 1.0
+var MaterialDefaultMediumAnisotropy float64 = 0.0
+
+var PrimitiveDefaultDensityType string = "CONSTANT"
+var PrimitiveDefaultNoiseFrequency float64 = 1.0
+var PrimitiveDefaultNoiseOctaves int32 = 4
+var PrimitiveMaximumNoiseOctaves int32 = 16
+var PrimitiveDefaultNoiseCoverage float64 = 1.0
+var PrimitiveDefaultNoiseSeed int64 = 0
+
+var TextureDefaultScale float64 = 1.0
+var TextureDefaultIsSolid bool = false
+var TextureDefaultOctaves int32 = 4
+var TextureMaximumOctaves int32 = 16
+var TextureDefaultSeed int64 = 0
+var TextureDefaultTurbulence float64 = 1.0
+var TextureDefaultFilterType string = "BILINEAR"
+var TextureDefaultWrapMode string = "REPEAT"
+var TextureDefaultUVScale float64 = 1.0
+var TextureDefaultUVOffset float64 = 0.0
+var TextureDefaultUVRotation float64 = 0.0
+var TextureDefaultHueShift float64 = 0.0
+var TextureDefaultSaturationScale float64 = 1.0
+var TextureDefaultValueScale float64 = 1.0
+var TextureDefaultIsClamped bool = false
+
+var CameraDefaultCameraType string = "PERSPECTIVE"
 var CameraMinimumVerticalFOV float64 = 10.0
 var CameraMaximumVerticalFOV float64 = 120.0
+var CameraMaximumFisheyeFOV float64 = 360.0
+var CameraDefaultIsPhysical bool = false
+var CameraDefaultSensorWidth float64 = 36.0
+var CameraDefaultISO float64 = 100.0
+var CameraDefaultApertureBlades int32 = 0
+var CameraDefaultApertureRotation float64 = 0.0
+var CameraDefaultCatsEye float64 = 0.0
+var CameraDefaultTilt float64 = 0.0
+var CameraMaximumTilt float64 = 80.0
+var CameraDefaultShift float64 = 0.0
+var CameraDefaultShutterOpen float64 = 0.0
+var CameraDefaultShutterClose float64 = 0.0
+var CameraDefaultInterocularDistance float64 = 0.064
 var CameraMinimumAperture float64 = 0.0
 var CameraMaximumAperture float64 = math.MaxFloat64
 var CameraMinimumFocusDistance float64 = 0.0
 var CameraMaximumFocusDistance float64 = math.MaxFloat64
+
+var RenderDefaultIsCropped bool = false
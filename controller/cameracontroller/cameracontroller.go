@@ -3,14 +3,19 @@ package cameracontroller
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/paulwrubel/photolum/config"
 	"github.com/paulwrubel/photolum/config/geometry"
 	"github.com/paulwrubel/photolum/constants"
 	"github.com/paulwrubel/photolum/controller"
+	"github.com/paulwrubel/photolum/enumeration/cameratype"
+	"github.com/paulwrubel/photolum/enumeration/stereolayout"
 	"github.com/paulwrubel/photolum/persistence/camerapersistence"
+	"github.com/paulwrubel/photolum/persistence/texturepersistence"
 	"github.com/sirupsen/logrus"
 )
 
@@ -22,13 +27,37 @@ type GetRequest struct {
 }
 
 type GetResponse struct {
-	CameraName     string          `json:"camera_name"`
-	EyeLocation    geometry.Point  `json:"eye_location"`
-	TargetLocation geometry.Point  `json:"target_location"`
-	UpVector       geometry.Vector `json:"up_vector"`
-	VerticalFOV    float64         `json:"vertical_fov"`
-	Aperture       float64         `json:"aperture"`
-	FocusDistance  float64         `json:"focus_distance"`
+	CameraName              string          `json:"camera_name"`
+	CameraType              string          `json:"camera_type"`
+	EyeLocation             geometry.Point  `json:"eye_location"`
+	TargetLocation          geometry.Point  `json:"target_location"`
+	UpVector                geometry.Vector `json:"up_vector"`
+	VerticalFOV             *float64        `json:"vertical_fov,omitempty"`
+	OrthographicHeight      *float64        `json:"orthographic_height,omitempty"`
+	Aperture                *float64        `json:"aperture,omitempty"`
+	FocusDistance           float64         `json:"focus_distance"`
+	IsPhysical              bool            `json:"is_physical"`
+	FocalLength             *float64        `json:"focal_length,omitempty"`
+	SensorWidth             *float64        `json:"sensor_width,omitempty"`
+	FNumber                 *float64        `json:"f_number,omitempty"`
+	ShutterSpeed            *float64        `json:"shutter_speed,omitempty"`
+	ISO                     *float64        `json:"iso,omitempty"`
+	ApertureBlades          int32           `json:"aperture_blades"`
+	ApertureRotation        float64         `json:"aperture_rotation"`
+	ApertureMaskTextureName *string         `json:"aperture_mask_texture_name,omitempty"`
+	CatsEye                 float64         `json:"cats_eye"`
+	Tilt                    Pair            `json:"tilt"`
+	Shift                   Pair            `json:"shift"`
+	ShutterOpen             float64         `json:"shutter_open"`
+	ShutterClose            float64         `json:"shutter_close"`
+	StereoLayout            *string         `json:"stereo_layout,omitempty"`
+	InterocularDistance     *float64        `json:"interocular_distance,omitempty"`
+	ConvergenceDistance     *float64        `json:"convergence_distance,omitempty"`
+}
+
+type Pair struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
 }
 
 type VectorRequest struct {
@@ -37,14 +66,38 @@ type VectorRequest struct {
 	Z *float64 `json:"z"`
 }
 
+type PairRequest struct {
+	X *float64 `json:"x"`
+	Y *float64 `json:"y"`
+}
+
 type PostRequest struct {
-	CameraName     *string        `json:"camera_name"`
-	EyeLocation    *VectorRequest `json:"eye_location"`
-	TargetLocation *VectorRequest `json:"target_location"`
-	UpVector       *VectorRequest `json:"up_vector"`
-	VerticalFOV    *float64       `json:"vertical_fov"`
-	Aperture       *float64       `json:"aperture"`
-	FocusDistance  *float64       `json:"focus_distance"`
+	CameraName              *string        `json:"camera_name"`
+	CameraType              *string        `json:"camera_type"`
+	EyeLocation             *VectorRequest `json:"eye_location"`
+	TargetLocation          *VectorRequest `json:"target_location"`
+	UpVector                *VectorRequest `json:"up_vector"`
+	VerticalFOV             *float64       `json:"vertical_fov"`
+	OrthographicHeight      *float64       `json:"orthographic_height"`
+	Aperture                *float64       `json:"aperture"`
+	FocusDistance           *float64       `json:"focus_distance"`
+	IsPhysical              *bool          `json:"is_physical"`
+	FocalLength             *float64       `json:"focal_length"`
+	SensorWidth             *float64       `json:"sensor_width"`
+	FNumber                 *float64       `json:"f_number"`
+	ShutterSpeed            *float64       `json:"shutter_speed"`
+	ISO                     *float64       `json:"iso"`
+	ApertureBlades          *int32         `json:"aperture_blades"`
+	ApertureRotation        *float64       `json:"aperture_rotation"`
+	ApertureMaskTextureName *string        `json:"aperture_mask_texture_name"`
+	CatsEye                 *float64       `json:"cats_eye"`
+	Tilt                    *PairRequest   `json:"tilt"`
+	Shift                   *PairRequest   `json:"shift"`
+	ShutterOpen             *float64       `json:"shutter_open"`
+	ShutterClose            *float64       `json:"shutter_close"`
+	StereoLayout            *string        `json:"stereo_layout"`
+	InterocularDistance     *float64       `json:"interocular_distance"`
+	ConvergenceDistance     *float64       `json:"convergence_distance"`
 }
 
 func GetHandler(response http.ResponseWriter, request *http.Request, plData *config.PhotolumData, baseLog *logrus.Logger) {
@@ -110,6 +163,7 @@ func GetHandler(response http.ResponseWriter, request *http.Request, plData *con
 
 	getResponse := GetResponse{
 		CameraName: camera.CameraName,
+		CameraType: camera.CameraType,
 		EyeLocation: geometry.Point{
 			X: camera.EyeLocation[0],
 			Y: camera.EyeLocation[1],
@@ -125,9 +179,33 @@ func GetHandler(response http.ResponseWriter, request *http.Request, plData *con
 			Y: camera.UpVector[1],
 			Z: camera.UpVector[2],
 		},
-		VerticalFOV:   camera.VerticalFOV,
-		Aperture:      camera.Aperture,
-		FocusDistance: camera.FocusDistance,
+		VerticalFOV:             camera.VerticalFOV,
+		OrthographicHeight:      camera.OrthographicHeight,
+		Aperture:                camera.Aperture,
+		FocusDistance:           camera.FocusDistance,
+		IsPhysical:              camera.IsPhysical,
+		FocalLength:             camera.FocalLength,
+		SensorWidth:             camera.SensorWidth,
+		FNumber:                 camera.FNumber,
+		ShutterSpeed:            camera.ShutterSpeed,
+		ISO:                     camera.ISO,
+		ApertureBlades:          camera.ApertureBlades,
+		ApertureRotation:        camera.ApertureRotation,
+		ApertureMaskTextureName: camera.ApertureMaskTextureName,
+		CatsEye:                 camera.CatsEye,
+		Tilt: Pair{
+			X: camera.Tilt[0],
+			Y: camera.Tilt[1],
+		},
+		Shift: Pair{
+			X: camera.Shift[0],
+			Y: camera.Shift[1],
+		},
+		ShutterOpen:         camera.ShutterOpen,
+		ShutterClose:        camera.ShutterClose,
+		StereoLayout:        camera.StereoLayout,
+		InterocularDistance: camera.InterocularDistance,
+		ConvergenceDistance: camera.ConvergenceDistance,
 	}
 	response.Header().Add("Content-Type", "application/json")
 	response.WriteHeader(http.StatusOK)
@@ -172,8 +250,6 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 		postRequest.UpVector.X == nil ||
 		postRequest.UpVector.Y == nil ||
 		postRequest.UpVector.Z == nil ||
-		postRequest.VerticalFOV == nil ||
-		postRequest.Aperture == nil ||
 		postRequest.FocusDistance == nil {
 		errorMessage := "missing field from request"
 		errorStatusCode := http.StatusBadRequest
@@ -185,17 +261,61 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 
 	// validate input
 	var errorMessage = ""
-	if *postRequest.VerticalFOV < constants.CameraMinimumVerticalFOV {
-		errorMessage = fmt.Sprintf("vertical_fov cannot be below %f", constants.CameraMinimumVerticalFOV)
+	// the camera type is optional, and defaults to a perspective camera
+	if postRequest.CameraType == nil {
+		defaultCameraType := constants.CameraDefaultCameraType
+		postRequest.CameraType = &defaultCameraType
 	}
-	if *postRequest.VerticalFOV > constants.CameraMaximumVerticalFOV {
-		errorMessage = fmt.Sprintf("vertical_fov cannot exceed %f", constants.CameraMaximumVerticalFOV)
+	if postRequest.IsPhysical == nil {
+		defaultIsPhysical := constants.CameraDefaultIsPhysical
+		postRequest.IsPhysical = &defaultIsPhysical
 	}
-	if *postRequest.Aperture < constants.CameraMinimumAperture {
-		errorMessage = fmt.Sprintf("aperture cannot be below %f", constants.CameraMinimumAperture)
+	cameraType := cameratype.CameraType(strings.ToUpper(*postRequest.CameraType))
+	switch cameraType {
+	case cameratype.Perspective, cameratype.Cylindrical,
+		cameratype.FisheyeEquidistant, cameratype.FisheyeEquisolid:
+		// fisheyes may see all the way around, where a perspective view would stretch to infinity
+		maximumVerticalFOV := constants.CameraMaximumVerticalFOV
+		if cameraType == cameratype.FisheyeEquidistant || cameraType == cameratype.FisheyeEquisolid {
+			maximumVerticalFOV = constants.CameraMaximumFisheyeFOV
+		}
+		// a physical camera's field of view comes from its lens and sensor instead
+		if postRequest.VerticalFOV == nil {
+			if !*postRequest.IsPhysical {
+				errorMessage = fmt.Sprintf("vertical_fov is required for camera_type %s", cameraType)
+			}
+		} else if *postRequest.VerticalFOV < constants.CameraMinimumVerticalFOV {
+			errorMessage = fmt.Sprintf("vertical_fov cannot be below %f", constants.CameraMinimumVerticalFOV)
+		} else if *postRequest.VerticalFOV > maximumVerticalFOV {
+			errorMessage = fmt.Sprintf("vertical_fov cannot exceed %f", maximumVerticalFOV)
+		}
+	case cameratype.Orthographic, cameratype.Equirectangular:
+		if postRequest.VerticalFOV != nil {
+			errorMessage = fmt.Sprintf("vertical_fov is not allowed for camera_type %s", cameraType)
+		}
+	default:
+		errorMessage = "invalid camera_type"
 	}
-	if *postRequest.Aperture > constants.CameraMaximumAperture {
-		errorMessage = fmt.Sprintf("aperture cannot exceed %f", constants.CameraMaximumAperture)
+	if cameraType == cameratype.Orthographic {
+		if postRequest.OrthographicHeight == nil {
+			errorMessage = "orthographic_height is required for camera_type ORTHOGRAPHIC"
+		} else if *postRequest.OrthographicHeight <= 0.0 {
+			errorMessage = "orthographic_height must be greater than zero"
+		}
+	} else if postRequest.OrthographicHeight != nil {
+		errorMessage = fmt.Sprintf("orthographic_height is not allowed for camera_type %s", cameraType)
+	}
+	if postRequest.Aperture != nil {
+		// panoramic cameras cast every ray from the eye itself, so they have no lens to blur with
+		if cameraType != cameratype.Perspective && cameraType != cameratype.Orthographic && *postRequest.Aperture != 0.0 {
+			errorMessage = fmt.Sprintf("aperture must be zero for camera_type %s", cameraType)
+		}
+		if *postRequest.Aperture < constants.CameraMinimumAperture {
+			errorMessage = fmt.Sprintf("aperture cannot be below %f", constants.CameraMinimumAperture)
+		}
+		if *postRequest.Aperture > constants.CameraMaximumAperture {
+			errorMessage = fmt.Sprintf("aperture cannot exceed %f", constants.CameraMaximumAperture)
+		}
 	}
 	if *postRequest.FocusDistance < constants.CameraMinimumFocusDistance {
 		errorMessage = fmt.Sprintf("focus_distance cannot be below %f", constants.CameraMinimumFocusDistance)
@@ -203,6 +323,159 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 	if *postRequest.FocusDistance > constants.CameraMaximumFocusDistance {
 		errorMessage = fmt.Sprintf("focus_distance cannot exceed %f", constants.CameraMaximumFocusDistance)
 	}
+	// a physical camera is described by its lens, sensor, and exposure settings rather than its view and aperture
+	if *postRequest.IsPhysical {
+		if postRequest.SensorWidth == nil {
+			defaultSensorWidth := constants.CameraDefaultSensorWidth
+			postRequest.SensorWidth = &defaultSensorWidth
+		}
+		if postRequest.ISO == nil {
+			defaultISO := constants.CameraDefaultISO
+			postRequest.ISO = &defaultISO
+		}
+		if postRequest.FocalLength == nil || postRequest.FNumber == nil || postRequest.ShutterSpeed == nil {
+			errorMessage = "focal_length, f_number, and shutter_speed are required for physical cameras"
+		} else if *postRequest.FocalLength <= 0.0 {
+			errorMessage = "focal_length must be greater than zero"
+		} else if *postRequest.FNumber <= 0.0 {
+			errorMessage = "f_number must be greater than zero"
+		} else if *postRequest.ShutterSpeed <= 0.0 {
+			errorMessage = "shutter_speed must be greater than zero"
+		}
+		if *postRequest.SensorWidth <= 0.0 {
+			errorMessage = "sensor_width must be greater than zero"
+		}
+		if *postRequest.ISO <= 0.0 {
+			errorMessage = "iso must be greater than zero"
+		}
+		if postRequest.VerticalFOV != nil {
+			errorMessage = "vertical_fov is not allowed for physical cameras"
+		}
+		if postRequest.Aperture != nil {
+			errorMessage = "aperture is not allowed for physical cameras, which use f_number"
+		}
+		if cameraType != cameratype.Perspective {
+			errorMessage = "is_physical is only allowed for camera_type PERSPECTIVE"
+		}
+	} else {
+		if postRequest.Aperture == nil {
+			errorMessage = "aperture is required for cameras which aren't physical"
+		}
+		if postRequest.FocalLength != nil ||
+			postRequest.SensorWidth != nil ||
+			postRequest.FNumber != nil ||
+			postRequest.ShutterSpeed != nil ||
+			postRequest.ISO != nil {
+			errorMessage = "focal_length, sensor_width, f_number, shutter_speed, and iso are only allowed for physical cameras"
+		}
+	}
+	// the lens settings are optional, and default to a round aperture with no cutoff, tilt or shift
+	if postRequest.ApertureBlades == nil {
+		defaultApertureBlades := constants.CameraDefaultApertureBlades
+		postRequest.ApertureBlades = &defaultApertureBlades
+	}
+	if postRequest.ApertureRotation == nil {
+		defaultApertureRotation := constants.CameraDefaultApertureRotation
+		postRequest.ApertureRotation = &defaultApertureRotation
+	}
+	if postRequest.CatsEye == nil {
+		defaultCatsEye := constants.CameraDefaultCatsEye
+		postRequest.CatsEye = &defaultCatsEye
+	}
+	if postRequest.Tilt == nil {
+		postRequest.Tilt = &PairRequest{}
+	}
+	if postRequest.Tilt.X == nil {
+		defaultTilt := constants.CameraDefaultTilt
+		postRequest.Tilt.X = &defaultTilt
+	}
+	if postRequest.Tilt.Y == nil {
+		defaultTilt := constants.CameraDefaultTilt
+		postRequest.Tilt.Y = &defaultTilt
+	}
+	if postRequest.Shift == nil {
+		postRequest.Shift = &PairRequest{}
+	}
+	if postRequest.Shift.X == nil {
+		defaultShift := constants.CameraDefaultShift
+		postRequest.Shift.X = &defaultShift
+	}
+	if postRequest.Shift.Y == nil {
+		defaultShift := constants.CameraDefaultShift
+		postRequest.Shift.Y = &defaultShift
+	}
+	if *postRequest.ApertureBlades != 0 && *postRequest.ApertureBlades < 3 {
+		errorMessage = "aperture_blades must be zero, for a round aperture, or at least 3"
+	}
+	if *postRequest.CatsEye < 0.0 || *postRequest.CatsEye > 1.0 {
+		errorMessage = "cats_eye must be between 0 and 1"
+	}
+	if math.Abs(*postRequest.Tilt.X) > constants.CameraMaximumTilt || math.Abs(*postRequest.Tilt.Y) > constants.CameraMaximumTilt {
+		errorMessage = fmt.Sprintf("tilt cannot exceed %f degrees", constants.CameraMaximumTilt)
+	}
+	// panoramic cameras have no lens to shape, tilt, or shift
+	if cameraType != cameratype.Perspective && cameraType != cameratype.Orthographic &&
+		(*postRequest.ApertureBlades != 0 ||
+			postRequest.ApertureMaskTextureName != nil ||
+			*postRequest.CatsEye != 0.0 ||
+			*postRequest.Tilt.X != 0.0 ||
+			*postRequest.Tilt.Y != 0.0 ||
+			*postRequest.Shift.X != 0.0 ||
+			*postRequest.Shift.Y != 0.0) {
+		errorMessage = fmt.Sprintf("lens settings are not allowed for camera_type %s", cameraType)
+	}
+	// the shutter is optional, and defaults to an instant, so nothing blurs
+	if postRequest.ShutterOpen == nil {
+		defaultShutterOpen := constants.CameraDefaultShutterOpen
+		postRequest.ShutterOpen = &defaultShutterOpen
+	}
+	if postRequest.ShutterClose == nil {
+		defaultShutterClose := constants.CameraDefaultShutterClose
+		postRequest.ShutterClose = &defaultShutterClose
+	}
+	if *postRequest.ShutterClose < *postRequest.ShutterOpen {
+		errorMessage = "shutter_close cannot be before shutter_open"
+	}
+	// stereo is optional, and without it the camera renders a single view
+	// the eyes converge at the given distance, or look out in parallel without one
+	if postRequest.StereoLayout != nil {
+		stereoLayout := string(stereolayout.StereoLayout(strings.ToUpper(*postRequest.StereoLayout)))
+		postRequest.StereoLayout = &stereoLayout
+		if postRequest.InterocularDistance == nil {
+			defaultInterocularDistance := constants.CameraDefaultInterocularDistance
+			postRequest.InterocularDistance = &defaultInterocularDistance
+		}
+		switch stereolayout.StereoLayout(stereoLayout) {
+		case stereolayout.SideBySide, stereolayout.TopBottom:
+		default:
+			errorMessage = "invalid stereo_layout"
+		}
+		if *postRequest.InterocularDistance <= 0.0 {
+			errorMessage = "interocular_distance must be greater than zero"
+		}
+		if postRequest.ConvergenceDistance != nil && *postRequest.ConvergenceDistance <= 0.0 {
+			errorMessage = "convergence_distance must be greater than zero"
+		}
+		if cameraType != cameratype.Perspective && cameraType != cameratype.Equirectangular {
+			errorMessage = "stereo_layout is only allowed for camera_type PERSPECTIVE or EQUIRECTANGULAR"
+		}
+	} else if postRequest.InterocularDistance != nil || postRequest.ConvergenceDistance != nil {
+		errorMessage = "interocular_distance and convergence_distance are only allowed for stereo cameras"
+	}
+	if postRequest.ApertureMaskTextureName != nil {
+		exists, err := texturepersistence.DoesExist(plData, log, *postRequest.ApertureMaskTextureName)
+		if err != nil {
+			errorMessage := "error checking texture existence in database"
+			errorStatusCode := http.StatusInternalServerError
+
+			log.WithError(err).Error(errorMessage)
+			controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, err)
+			return
+		}
+		if !exists {
+			errorMessage = "named aperture mask texture does not exist"
+		}
+	}
 
 	// send error
 	if errorMessage != "" {
@@ -235,6 +508,7 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 	// assemble camera
 	camera := &camerapersistence.Camera{
 		CameraName: *postRequest.CameraName,
+		CameraType: string(cameraType),
 		EyeLocation: []float64{
 			*postRequest.EyeLocation.X,
 			*postRequest.EyeLocation.Y,
@@ -250,9 +524,33 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 			*postRequest.UpVector.Y,
 			*postRequest.UpVector.Z,
 		},
-		VerticalFOV:   *postRequest.VerticalFOV,
-		Aperture:      *postRequest.Aperture,
-		FocusDistance: *postRequest.FocusDistance,
+		VerticalFOV:             postRequest.VerticalFOV,
+		OrthographicHeight:      postRequest.OrthographicHeight,
+		Aperture:                postRequest.Aperture,
+		FocusDistance:           *postRequest.FocusDistance,
+		IsPhysical:              *postRequest.IsPhysical,
+		FocalLength:             postRequest.FocalLength,
+		SensorWidth:             postRequest.SensorWidth,
+		FNumber:                 postRequest.FNumber,
+		ShutterSpeed:            postRequest.ShutterSpeed,
+		ISO:                     postRequest.ISO,
+		ApertureBlades:          *postRequest.ApertureBlades,
+		ApertureRotation:        *postRequest.ApertureRotation,
+		ApertureMaskTextureName: postRequest.ApertureMaskTextureName,
+		CatsEye:                 *postRequest.CatsEye,
+		Tilt: []float64{
+			*postRequest.Tilt.X,
+			*postRequest.Tilt.Y,
+		},
+		Shift: []float64{
+			*postRequest.Shift.X,
+			*postRequest.Shift.Y,
+		},
+		ShutterOpen:         *postRequest.ShutterOpen,
+		ShutterClose:        *postRequest.ShutterClose,
+		StereoLayout:        postRequest.StereoLayout,
+		InterocularDistance: postRequest.InterocularDistance,
+		ConvergenceDistance: postRequest.ConvergenceDistance,
 	}
 
 	// save to db
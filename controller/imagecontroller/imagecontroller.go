@@ -116,6 +116,19 @@ func GetHandler(response http.ResponseWriter, request *http.Request, plData *con
 		}
 		response.WriteHeader(http.StatusOK)
 		response.Write(render.ImageData)
+	case "heatmap":
+		// heatmaps only exist for renders using adaptive sampling
+		if render.HeatmapData == nil {
+			errorMessage := "render has no sample heatmap"
+			errorStatusCode := http.StatusNotFound
+
+			log.Error(errorMessage)
+			controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, nil)
+			return
+		}
+		response.Header().Add("Content-Type", "image/png")
+		response.WriteHeader(http.StatusOK)
+		response.Write(render.HeatmapData)
 	default:
 		errorMessage := "invalid format"
 		errorStatusCode := http.StatusInternalServerError
@@ -2,11 +2,15 @@ package materialcontroller
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/google/uuid"
 	"github.com/paulwrubel/photolum/config"
+	"github.com/paulwrubel/photolum/config/shading"
+	shadingmaterial "github.com/paulwrubel/photolum/config/shading/material"
+	"github.com/paulwrubel/photolum/constants"
 	"github.com/paulwrubel/photolum/controller"
 	"github.com/paulwrubel/photolum/enumeration/materialtype"
 	"github.com/paulwrubel/photolum/persistence/materialpersistence"
@@ -22,42 +26,197 @@ type GetRequest struct {
 }
 
 type LambertianGetResponse struct {
+	MaterialName           string   `json:"material_name"`
+	MaterialType           string   `json:"material_type"`
+	ReflectanceTextureName string   `json:"reflectance_texture_name,omitempty"`
+	EmittanceTextureName   string   `json:"emittance_texture_name,omitempty"`
+	NormalTextureName      *string  `json:"normal_texture_name,omitempty"`
+	BumpTextureName        *string  `json:"bump_texture_name,omitempty"`
+	BumpStrength           *float64 `json:"bump_strength,omitempty"`
+}
+
+type MetalGetResponse struct {
+	MaterialName           string   `json:"material_name"`
+	MaterialType           string   `json:"material_type"`
+	ReflectanceTextureName string   `json:"reflectance_texture_name,omitempty"`
+	EmittanceTextureName   string   `json:"emittance_texture_name,omitempty"`
+	Fuzziness              float64  `json:"fuzziness"`
+	NormalTextureName      *string  `json:"normal_texture_name,omitempty"`
+	BumpTextureName        *string  `json:"bump_texture_name,omitempty"`
+	BumpStrength           *float64 `json:"bump_strength,omitempty"`
+}
+
+type DielectricGetResponse struct {
+	MaterialName           string         `json:"material_name"`
+	MaterialType           string         `json:"material_type"`
+	ReflectanceTextureName string         `json:"reflectance_texture_name,omitempty"`
+	EmittanceTextureName   string         `json:"emittance_texture_name,omitempty"`
+	RefractiveIndex        float64        `json:"refractive_index"`
+	TransmittanceColor     *shading.Color `json:"transmittance_color,omitempty"`
+	TransmittanceDistance  *float64       `json:"transmittance_distance,omitempty"`
+	AbsorptionCoefficient  *shading.Color `json:"absorption_coefficient,omitempty"`
+	ScatteringCoefficient  *shading.Color `json:"scattering_coefficient,omitempty"`
+	Anisotropy             *float64       `json:"anisotropy,omitempty"`
+	NormalTextureName      *string        `json:"normal_texture_name,omitempty"`
+	BumpTextureName        *string        `json:"bump_texture_name,omitempty"`
+	BumpStrength           *float64       `json:"bump_strength,omitempty"`
+}
+
+type IsotropicGetResponse struct {
 	MaterialName           string `json:"material_name"`
 	MaterialType           string `json:"material_type"`
 	ReflectanceTextureName string `json:"reflectance_texture_name,omitempty"`
 	EmittanceTextureName   string `json:"emittance_texture_name,omitempty"`
 }
 
-type MetalGetResponse struct {
+type HenyeyGreensteinGetResponse struct {
 	MaterialName           string  `json:"material_name"`
 	MaterialType           string  `json:"material_type"`
 	ReflectanceTextureName string  `json:"reflectance_texture_name,omitempty"`
 	EmittanceTextureName   string  `json:"emittance_texture_name,omitempty"`
-	Fuzziness              float64 `json:"fuzziness"`
+	Anisotropy             float64 `json:"anisotropy"`
 }
 
-type DielectricGetResponse struct {
-	MaterialName           string  `json:"material_name"`
-	MaterialType           string  `json:"material_type"`
-	ReflectanceTextureName string  `json:"reflectance_texture_name,omitempty"`
-	EmittanceTextureName   string  `json:"emittance_texture_name,omitempty"`
-	RefractiveIndex        float64 `json:"refractive_index"`
+type ConductorGetResponse struct {
+	MaterialName           string        `json:"material_name"`
+	MaterialType           string        `json:"material_type"`
+	ReflectanceTextureName string        `json:"reflectance_texture_name,omitempty"`
+	EmittanceTextureName   string        `json:"emittance_texture_name,omitempty"`
+	RoughnessU             float64       `json:"roughness_u"`
+	RoughnessV             float64       `json:"roughness_v"`
+	Eta                    shading.Color `json:"eta"`
+	K                      shading.Color `json:"k"`
+	NormalTextureName      *string       `json:"normal_texture_name,omitempty"`
+	BumpTextureName        *string       `json:"bump_texture_name,omitempty"`
+	BumpStrength           *float64      `json:"bump_strength,omitempty"`
 }
 
-type IsotropicGetResponse struct {
-	MaterialName           string `json:"material_name"`
-	MaterialType           string `json:"material_type"`
-	ReflectanceTextureName string `json:"reflectance_texture_name,omitempty"`
-	EmittanceTextureName   string `json:"emittance_texture_name,omitempty"`
+type RoughDielectricGetResponse struct {
+	MaterialName           string         `json:"material_name"`
+	MaterialType           string         `json:"material_type"`
+	ReflectanceTextureName string         `json:"reflectance_texture_name,omitempty"`
+	EmittanceTextureName   string         `json:"emittance_texture_name,omitempty"`
+	RoughnessTextureName   string         `json:"roughness_texture_name"`
+	RefractiveIndex        float64        `json:"refractive_index"`
+	TransmittanceColor     *shading.Color `json:"transmittance_color,omitempty"`
+	TransmittanceDistance  *float64       `json:"transmittance_distance,omitempty"`
+	AbsorptionCoefficient  *shading.Color `json:"absorption_coefficient,omitempty"`
+	ScatteringCoefficient  *shading.Color `json:"scattering_coefficient,omitempty"`
+	Anisotropy             *float64       `json:"anisotropy,omitempty"`
+	NormalTextureName      *string        `json:"normal_texture_name,omitempty"`
+	BumpTextureName        *string        `json:"bump_texture_name,omitempty"`
+	BumpStrength           *float64       `json:"bump_strength,omitempty"`
+}
+
+type SubsurfaceGetResponse struct {
+	MaterialName           string        `json:"material_name"`
+	MaterialType           string        `json:"material_type"`
+	ReflectanceTextureName string        `json:"reflectance_texture_name,omitempty"`
+	EmittanceTextureName   string        `json:"emittance_texture_name,omitempty"`
+	RefractiveIndex        float64       `json:"refractive_index"`
+	Albedo                 shading.Color `json:"albedo"`
+	MeanFreePath           shading.Color `json:"mean_free_path"`
+	Anisotropy             float64       `json:"anisotropy"`
+	NormalTextureName      *string       `json:"normal_texture_name,omitempty"`
+	BumpTextureName        *string       `json:"bump_texture_name,omitempty"`
+	BumpStrength           *float64      `json:"bump_strength,omitempty"`
+}
+
+type PrincipledGetResponse struct {
+	MaterialName            string         `json:"material_name"`
+	MaterialType            string         `json:"material_type"`
+	BaseColor               *shading.Color `json:"base_color,omitempty"`
+	BaseColorTextureName    *string        `json:"base_color_texture_name,omitempty"`
+	Metallic                *float64       `json:"metallic,omitempty"`
+	MetallicTextureName     *string        `json:"metallic_texture_name,omitempty"`
+	Roughness               *float64       `json:"roughness,omitempty"`
+	RoughnessTextureName    *string        `json:"roughness_texture_name,omitempty"`
+	Specular                *float64       `json:"specular,omitempty"`
+	SpecularTextureName     *string        `json:"specular_texture_name,omitempty"`
+	Clearcoat               *float64       `json:"clearcoat,omitempty"`
+	ClearcoatTextureName    *string        `json:"clearcoat_texture_name,omitempty"`
+	Sheen                   *float64       `json:"sheen,omitempty"`
+	SheenTextureName        *string        `json:"sheen_texture_name,omitempty"`
+	Transmission            *float64       `json:"transmission,omitempty"`
+	TransmissionTextureName *string        `json:"transmission_texture_name,omitempty"`
+	RefractiveIndex         float64        `json:"refractive_index"`
+	Emission                *shading.Color `json:"emission,omitempty"`
+	EmittanceTextureName    *string        `json:"emittance_texture_name,omitempty"`
+	NormalTextureName       *string        `json:"normal_texture_name,omitempty"`
+	BumpTextureName         *string        `json:"bump_texture_name,omitempty"`
+	BumpStrength            *float64       `json:"bump_strength,omitempty"`
+}
+
+type MixGetResponse struct {
+	MaterialName       string `json:"material_name"`
+	MaterialType       string `json:"material_type"`
+	FirstMaterialName  string `json:"first_material_name"`
+	SecondMaterialName string `json:"second_material_name"`
+	WeightTextureName  string `json:"weight_texture_name"`
+}
+
+type LightGetResponse struct {
+	MaterialName         string   `json:"material_name"`
+	MaterialType         string   `json:"material_type"`
+	EmittanceTextureName string   `json:"emittance_texture_name"`
+	Power                *float64 `json:"power,omitempty"`
+	IsTwoSided           bool     `json:"is_two_sided"`
+	SpotAngle            float64  `json:"spot_angle"`
+	SpotBlend            float64  `json:"spot_blend"`
+	IsVisibleToCamera    bool     `json:"is_visible_to_camera"`
+}
+
+type ColorRequest struct {
+	Red   *float64 `json:"red"`
+	Green *float64 `json:"green"`
+	Blue  *float64 `json:"blue"`
 }
 
 type PostRequest struct {
-	MaterialName           *string  `json:"material_name"`
-	MaterialType           *string  `json:"material_type"`
-	ReflectanceTextureName *string  `json:"reflectance_texture_name"`
-	EmittanceTextureName   *string  `json:"emittance_texture_name"`
-	Fuzziness              *float64 `json:"fuzziness"`
-	RefractiveIndex        *float64 `json:"refractive_index"`
+	MaterialName            *string       `json:"material_name"`
+	MaterialType            *string       `json:"material_type"`
+	ReflectanceTextureName  *string       `json:"reflectance_texture_name"`
+	EmittanceTextureName    *string       `json:"emittance_texture_name"`
+	Fuzziness               *float64      `json:"fuzziness"`
+	RefractiveIndex         *float64      `json:"refractive_index"`
+	RoughnessU              *float64      `json:"roughness_u"`
+	RoughnessV              *float64      `json:"roughness_v"`
+	ConductorPreset         *string       `json:"conductor_preset"`
+	Eta                     *ColorRequest `json:"eta"`
+	K                       *ColorRequest `json:"k"`
+	RoughnessTextureName    *string       `json:"roughness_texture_name"`
+	TransmittanceColor      *ColorRequest `json:"transmittance_color"`
+	TransmittanceDistance   *float64      `json:"transmittance_distance"`
+	BaseColor               *ColorRequest `json:"base_color"`
+	BaseColorTextureName    *string       `json:"base_color_texture_name"`
+	Metallic                *float64      `json:"metallic"`
+	MetallicTextureName     *string       `json:"metallic_texture_name"`
+	Roughness               *float64      `json:"roughness"`
+	Specular                *float64      `json:"specular"`
+	SpecularTextureName     *string       `json:"specular_texture_name"`
+	Clearcoat               *float64      `json:"clearcoat"`
+	ClearcoatTextureName    *string       `json:"clearcoat_texture_name"`
+	Sheen                   *float64      `json:"sheen"`
+	SheenTextureName        *string       `json:"sheen_texture_name"`
+	Transmission            *float64      `json:"transmission"`
+	TransmissionTextureName *string       `json:"transmission_texture_name"`
+	Emission                *ColorRequest `json:"emission"`
+	FirstMaterialName       *string       `json:"first_material_name"`
+	SecondMaterialName      *string       `json:"second_material_name"`
+	WeightTextureName       *string       `json:"weight_texture_name"`
+	Power                   *float64      `json:"power"`
+	IsTwoSided              *bool         `json:"is_two_sided"`
+	SpotAngle               *float64      `json:"spot_angle"`
+	SpotBlend               *float64      `json:"spot_blend"`
+	IsVisibleToCamera       *bool         `json:"is_visible_to_camera"`
+	NormalTextureName       *string       `json:"normal_texture_name"`
+	BumpTextureName         *string       `json:"bump_texture_name"`
+	BumpStrength            *float64      `json:"bump_strength"`
+	Anisotropy              *float64      `json:"anisotropy"`
+	AbsorptionCoefficient   *ColorRequest `json:"absorption_coefficient"`
+	ScatteringCoefficient   *ColorRequest `json:"scattering_coefficient"`
+	Albedo                  *ColorRequest `json:"albedo"`
+	MeanFreePath            *ColorRequest `json:"mean_free_path"`
 }
 
 func GetHandler(response http.ResponseWriter, request *http.Request, plData *config.PhotolumData, baseLog *logrus.Logger) {
@@ -129,6 +288,7 @@ func GetHandler(response http.ResponseWriter, request *http.Request, plData *con
 	if material.EmittanceTextureName != nil {
 		emittanceTextureName = *material.EmittanceTextureName
 	}
+	transmittanceColor := colorFromArray(material.TransmittanceColor)
 	var getResponse interface{}
 	switch materialtype.MaterialType(material.MaterialType) {
 	case materialtype.Lambertian:
@@ -137,6 +297,9 @@ func GetHandler(response http.ResponseWriter, request *http.Request, plData *con
 			MaterialType:           material.MaterialType,
 			ReflectanceTextureName: reflectanceTextureName,
 			EmittanceTextureName:   emittanceTextureName,
+			NormalTextureName:      material.NormalTextureName,
+			BumpTextureName:        material.BumpTextureName,
+			BumpStrength:           material.BumpStrength,
 		}
 	case materialtype.Metal:
 		getResponse = MetalGetResponse{
@@ -145,6 +308,9 @@ func GetHandler(response http.ResponseWriter, request *http.Request, plData *con
 			ReflectanceTextureName: reflectanceTextureName,
 			EmittanceTextureName:   emittanceTextureName,
 			Fuzziness:              *material.Fuzziness,
+			NormalTextureName:      material.NormalTextureName,
+			BumpTextureName:        material.BumpTextureName,
+			BumpStrength:           material.BumpStrength,
 		}
 	case materialtype.Dielectric:
 		getResponse = DielectricGetResponse{
@@ -153,6 +319,14 @@ func GetHandler(response http.ResponseWriter, request *http.Request, plData *con
 			ReflectanceTextureName: reflectanceTextureName,
 			EmittanceTextureName:   emittanceTextureName,
 			RefractiveIndex:        *material.RefractiveIndex,
+			TransmittanceColor:     transmittanceColor,
+			TransmittanceDistance:  material.TransmittanceDistance,
+			AbsorptionCoefficient:  colorFromArray(material.AbsorptionCoefficient),
+			ScatteringCoefficient:  colorFromArray(material.ScatteringCoefficient),
+			Anisotropy:             material.Anisotropy,
+			NormalTextureName:      material.NormalTextureName,
+			BumpTextureName:        material.BumpTextureName,
+			BumpStrength:           material.BumpStrength,
 		}
 	case materialtype.Isotropic:
 		getResponse = IsotropicGetResponse{
@@ -161,6 +335,111 @@ func GetHandler(response http.ResponseWriter, request *http.Request, plData *con
 			ReflectanceTextureName: reflectanceTextureName,
 			EmittanceTextureName:   emittanceTextureName,
 		}
+	case materialtype.HenyeyGreenstein:
+		getResponse = HenyeyGreensteinGetResponse{
+			MaterialName:           material.MaterialName,
+			MaterialType:           material.MaterialType,
+			ReflectanceTextureName: reflectanceTextureName,
+			EmittanceTextureName:   emittanceTextureName,
+			Anisotropy:             *material.Anisotropy,
+		}
+	case materialtype.Conductor:
+		getResponse = ConductorGetResponse{
+			MaterialName:           material.MaterialName,
+			MaterialType:           material.MaterialType,
+			ReflectanceTextureName: reflectanceTextureName,
+			EmittanceTextureName:   emittanceTextureName,
+			RoughnessU:             *material.RoughnessU,
+			RoughnessV:             *material.RoughnessV,
+			Eta: shading.Color{
+				Red:   material.Eta[0],
+				Green: material.Eta[1],
+				Blue:  material.Eta[2],
+			},
+			K: shading.Color{
+				Red:   material.K[0],
+				Green: material.K[1],
+				Blue:  material.K[2],
+			},
+			NormalTextureName: material.NormalTextureName,
+			BumpTextureName:   material.BumpTextureName,
+			BumpStrength:      material.BumpStrength,
+		}
+	case materialtype.RoughDielectric:
+		getResponse = RoughDielectricGetResponse{
+			MaterialName:           material.MaterialName,
+			MaterialType:           material.MaterialType,
+			ReflectanceTextureName: reflectanceTextureName,
+			EmittanceTextureName:   emittanceTextureName,
+			RoughnessTextureName:   *material.RoughnessTextureName,
+			RefractiveIndex:        *material.RefractiveIndex,
+			TransmittanceColor:     transmittanceColor,
+			TransmittanceDistance:  material.TransmittanceDistance,
+			AbsorptionCoefficient:  colorFromArray(material.AbsorptionCoefficient),
+			ScatteringCoefficient:  colorFromArray(material.ScatteringCoefficient),
+			Anisotropy:             material.Anisotropy,
+			NormalTextureName:      material.NormalTextureName,
+			BumpTextureName:        material.BumpTextureName,
+			BumpStrength:           material.BumpStrength,
+		}
+	case materialtype.Subsurface:
+		getResponse = SubsurfaceGetResponse{
+			MaterialName:           material.MaterialName,
+			MaterialType:           material.MaterialType,
+			ReflectanceTextureName: reflectanceTextureName,
+			EmittanceTextureName:   emittanceTextureName,
+			RefractiveIndex:        *material.RefractiveIndex,
+			Albedo:                 *colorFromArray(material.Albedo),
+			MeanFreePath:           *colorFromArray(material.MeanFreePath),
+			Anisotropy:             *material.Anisotropy,
+			NormalTextureName:      material.NormalTextureName,
+			BumpTextureName:        material.BumpTextureName,
+			BumpStrength:           material.BumpStrength,
+		}
+	case materialtype.Principled:
+		getResponse = PrincipledGetResponse{
+			MaterialName:            material.MaterialName,
+			MaterialType:            material.MaterialType,
+			BaseColor:               colorFromArray(material.BaseColor),
+			BaseColorTextureName:    material.BaseColorTextureName,
+			Metallic:                material.Metallic,
+			MetallicTextureName:     material.MetallicTextureName,
+			Roughness:               material.Roughness,
+			RoughnessTextureName:    material.RoughnessTextureName,
+			Specular:                material.Specular,
+			SpecularTextureName:     material.SpecularTextureName,
+			Clearcoat:               material.Clearcoat,
+			ClearcoatTextureName:    material.ClearcoatTextureName,
+			Sheen:                   material.Sheen,
+			SheenTextureName:        material.SheenTextureName,
+			Transmission:            material.Transmission,
+			TransmissionTextureName: material.TransmissionTextureName,
+			RefractiveIndex:         *material.RefractiveIndex,
+			Emission:                colorFromArray(material.Emission),
+			EmittanceTextureName:    material.EmittanceTextureName,
+			NormalTextureName:       material.NormalTextureName,
+			BumpTextureName:         material.BumpTextureName,
+			BumpStrength:            material.BumpStrength,
+		}
+	case materialtype.Mix:
+		getResponse = MixGetResponse{
+			MaterialName:       material.MaterialName,
+			MaterialType:       material.MaterialType,
+			FirstMaterialName:  *material.FirstMaterialName,
+			SecondMaterialName: *material.SecondMaterialName,
+			WeightTextureName:  *material.WeightTextureName,
+		}
+	case materialtype.Light:
+		getResponse = LightGetResponse{
+			MaterialName:         material.MaterialName,
+			MaterialType:         material.MaterialType,
+			EmittanceTextureName: emittanceTextureName,
+			Power:                material.Power,
+			IsTwoSided:           *material.IsTwoSided,
+			SpotAngle:            *material.SpotAngle,
+			SpotBlend:            *material.SpotBlend,
+			IsVisibleToCamera:    *material.IsVisibleToCamera,
+		}
 	}
 
 	response.Header().Add("Content-Type", "application/json")
@@ -193,9 +472,16 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 		return
 	}
 	// check for missing fields
+	// conductors get their color from their refractive index, principled materials from their own parameters,
+	// subsurface materials from their albedo, and mixes from the materials they mix,
+	// so they're the only materials which need no texture
 	if postRequest.MaterialName == nil ||
 		postRequest.MaterialType == nil ||
-		(postRequest.ReflectanceTextureName == nil && postRequest.EmittanceTextureName == nil) {
+		(postRequest.ReflectanceTextureName == nil && postRequest.EmittanceTextureName == nil &&
+			materialtype.MaterialType(strings.ToUpper(*postRequest.MaterialType)) != materialtype.Conductor &&
+			materialtype.MaterialType(strings.ToUpper(*postRequest.MaterialType)) != materialtype.Principled &&
+			materialtype.MaterialType(strings.ToUpper(*postRequest.MaterialType)) != materialtype.Subsurface &&
+			materialtype.MaterialType(strings.ToUpper(*postRequest.MaterialType)) != materialtype.Mix) {
 		errorMessage := "missing field from request"
 		errorStatusCode := http.StatusBadRequest
 
@@ -236,6 +522,9 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 			errorMessage = "named emittance_texture does not exist"
 		}
 	}
+	var eta, k []float64
+	var baseColor, emission []float64
+	var albedo, meanFreePath []float64
 	switch materialtype.MaterialType(strings.ToUpper(*postRequest.MaterialType)) {
 	case materialtype.Lambertian:
 		// no unique validation necessary
@@ -267,10 +556,367 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 		}
 	case materialtype.Isotropic:
 		// no unique validation necessary
+	case materialtype.HenyeyGreenstein:
+		if postRequest.Anisotropy == nil {
+			errorMessage := "missing field from request"
+			errorStatusCode := http.StatusBadRequest
+
+			log.Error(errorMessage)
+			controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, nil)
+			return
+		}
+		if *postRequest.Anisotropy <= -1.0 || *postRequest.Anisotropy >= 1.0 {
+			errorMessage = "anisotropy must be greater than -1 and less than 1"
+		}
+	case materialtype.Conductor:
+		if postRequest.RoughnessU == nil ||
+			(postRequest.ConductorPreset == nil && (postRequest.Eta == nil || postRequest.K == nil)) {
+			errorMessage := "missing field from request"
+			errorStatusCode := http.StatusBadRequest
+
+			log.Error(errorMessage)
+			controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, nil)
+			return
+		}
+		// roughness is isotropic unless both directions are given
+		if postRequest.RoughnessV == nil {
+			postRequest.RoughnessV = postRequest.RoughnessU
+		}
+		if *postRequest.RoughnessU < 0.0 || *postRequest.RoughnessU > 1.0 ||
+			*postRequest.RoughnessV < 0.0 || *postRequest.RoughnessV > 1.0 {
+			errorMessage = "roughness_u and roughness_v must be between 0 and 1"
+		}
+		// a preset fills in the refractive index of a known metal
+		if postRequest.ConductorPreset != nil {
+			preset, ok := shadingmaterial.ConductorPresets[strings.ToUpper(*postRequest.ConductorPreset)]
+			if !ok {
+				errorMessage = "invalid conductor_preset"
+				break
+			}
+			eta = []float64{preset.Eta.Red, preset.Eta.Green, preset.Eta.Blue}
+			k = []float64{preset.K.Red, preset.K.Green, preset.K.Blue}
+		} else if postRequest.Eta.Red == nil || postRequest.Eta.Green == nil || postRequest.Eta.Blue == nil ||
+			postRequest.K.Red == nil || postRequest.K.Green == nil || postRequest.K.Blue == nil {
+			errorMessage = "eta and k must have red, green, and blue fields"
+		} else if *postRequest.Eta.Red <= 0.0 || *postRequest.Eta.Green <= 0.0 || *postRequest.Eta.Blue <= 0.0 {
+			errorMessage = "eta fields must be greater than zero"
+		} else if *postRequest.K.Red < 0.0 || *postRequest.K.Green < 0.0 || *postRequest.K.Blue < 0.0 {
+			errorMessage = "k fields must be greater than or equal to zero"
+		} else {
+			eta = []float64{*postRequest.Eta.Red, *postRequest.Eta.Green, *postRequest.Eta.Blue}
+			k = []float64{*postRequest.K.Red, *postRequest.K.Green, *postRequest.K.Blue}
+		}
+	case materialtype.RoughDielectric:
+		if postRequest.RefractiveIndex == nil ||
+			postRequest.RoughnessTextureName == nil {
+			errorMessage := "missing field from request"
+			errorStatusCode := http.StatusBadRequest
+
+			log.Error(errorMessage)
+			controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, nil)
+			return
+		}
+		if *postRequest.RefractiveIndex <= 1.0 {
+			errorMessage = "refractive_index must be greater than 1.0"
+		}
+		exists, err := texturepersistence.DoesExist(plData, log, *postRequest.RoughnessTextureName)
+		if err != nil {
+			errorMessage := "error checking texture existence in database"
+			errorStatusCode := http.StatusInternalServerError
+
+			log.WithError(err).Error(errorMessage)
+			controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, err)
+			return
+		}
+		if !exists {
+			errorMessage = "named roughness_texture does not exist"
+		}
+	case materialtype.Subsurface:
+		if postRequest.RefractiveIndex == nil ||
+			postRequest.Albedo == nil ||
+			postRequest.MeanFreePath == nil {
+			errorMessage := "missing field from request"
+			errorStatusCode := http.StatusBadRequest
+
+			log.Error(errorMessage)
+			controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, nil)
+			return
+		}
+		if postRequest.Anisotropy == nil {
+			defaultAnisotropy := constants.MaterialDefaultMediumAnisotropy
+			postRequest.Anisotropy = &defaultAnisotropy
+		}
+		albedo = arrayFromColorRequest(postRequest.Albedo)
+		meanFreePath = arrayFromColorRequest(postRequest.MeanFreePath)
+		if *postRequest.RefractiveIndex <= 1.0 {
+			errorMessage = "refractive_index must be greater than 1.0"
+		} else if albedo == nil || meanFreePath == nil {
+			errorMessage = "albedo and mean_free_path must have red, green, and blue fields"
+		} else if albedo[0] < 0.0 || albedo[0] > 1.0 || albedo[1] < 0.0 || albedo[1] > 1.0 ||
+			albedo[2] < 0.0 || albedo[2] > 1.0 {
+			errorMessage = "albedo fields must be between 0 and 1"
+		} else if meanFreePath[0] <= 0.0 || meanFreePath[1] <= 0.0 || meanFreePath[2] <= 0.0 {
+			errorMessage = "mean_free_path fields must be greater than zero"
+		} else if *postRequest.Anisotropy <= -1.0 || *postRequest.Anisotropy >= 1.0 {
+			errorMessage = "anisotropy must be greater than -1 and less than 1"
+		}
+	case materialtype.Principled:
+		if errorMessage != "" {
+			break
+		}
+		if postRequest.ReflectanceTextureName != nil {
+			errorMessage = "principled materials take base_color or base_color_texture_name instead of reflectance_texture_name"
+			break
+		}
+		if postRequest.RefractiveIndex == nil {
+			refractiveIndex := constants.MaterialDefaultPrincipledRefractiveIndex
+			postRequest.RefractiveIndex = &refractiveIndex
+		}
+		if *postRequest.RefractiveIndex <= 1.0 {
+			errorMessage = "refractive_index must be greater than 1.0"
+			break
+		}
+		// each parameter is either a constant, a texture, or left to its default
+		var err error
+		baseColor, errorMessage, err = principledColor(plData, log, "base_color",
+			postRequest.BaseColor, postRequest.BaseColorTextureName, constants.MaterialDefaultPrincipledBaseColor)
+		if err != nil {
+			errorMessage := "error checking texture existence in database"
+			errorStatusCode := http.StatusInternalServerError
+
+			log.WithError(err).Error(errorMessage)
+			controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, err)
+			return
+		}
+		parameters := []struct {
+			name         string
+			constant     **float64
+			textureName  *string
+			defaultValue float64
+		}{
+			{"metallic", &postRequest.Metallic, postRequest.MetallicTextureName, constants.MaterialDefaultPrincipledMetallic},
+			{"roughness", &postRequest.Roughness, postRequest.RoughnessTextureName, constants.MaterialDefaultPrincipledRoughness},
+			{"specular", &postRequest.Specular, postRequest.SpecularTextureName, constants.MaterialDefaultPrincipledSpecular},
+			{"clearcoat", &postRequest.Clearcoat, postRequest.ClearcoatTextureName, constants.MaterialDefaultPrincipledClearcoat},
+			{"sheen", &postRequest.Sheen, postRequest.SheenTextureName, constants.MaterialDefaultPrincipledSheen},
+			{"transmission", &postRequest.Transmission, postRequest.TransmissionTextureName, constants.MaterialDefaultPrincipledTransmission},
+		}
+		for _, parameter := range parameters {
+			if errorMessage != "" {
+				break
+			}
+			*parameter.constant, errorMessage, err = principledParameter(plData, log, parameter.name,
+				*parameter.constant, parameter.textureName, parameter.defaultValue)
+			if err != nil {
+				errorMessage := "error checking texture existence in database"
+				errorStatusCode := http.StatusInternalServerError
+
+				log.WithError(err).Error(errorMessage)
+				controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, err)
+				return
+			}
+		}
+		if errorMessage != "" {
+			break
+		}
+		// emission may come from the emittance texture, whose existence was checked above
+		if postRequest.EmittanceTextureName != nil {
+			if postRequest.Emission != nil {
+				errorMessage = "only one of emission and emittance_texture_name may be given"
+			}
+		} else if postRequest.Emission == nil {
+			emission = []float64{
+				constants.MaterialDefaultPrincipledEmission,
+				constants.MaterialDefaultPrincipledEmission,
+				constants.MaterialDefaultPrincipledEmission,
+			}
+		} else if postRequest.Emission.Red == nil || postRequest.Emission.Green == nil || postRequest.Emission.Blue == nil {
+			errorMessage = "emission must have red, green, and blue fields"
+		} else if *postRequest.Emission.Red < 0.0 || *postRequest.Emission.Green < 0.0 || *postRequest.Emission.Blue < 0.0 {
+			errorMessage = "emission fields must be greater than or equal to zero"
+		} else {
+			emission = []float64{*postRequest.Emission.Red, *postRequest.Emission.Green, *postRequest.Emission.Blue}
+		}
+	case materialtype.Mix:
+		if postRequest.FirstMaterialName == nil ||
+			postRequest.SecondMaterialName == nil ||
+			postRequest.WeightTextureName == nil {
+			errorMessage := "missing field from request"
+			errorStatusCode := http.StatusBadRequest
+
+			log.Error(errorMessage)
+			controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, nil)
+			return
+		}
+		if postRequest.ReflectanceTextureName != nil || postRequest.EmittanceTextureName != nil {
+			errorMessage = "mix materials take their textures from the materials they mix"
+			break
+		}
+		// materials can only reference ones which already exist, so they can't form a cycle
+		for _, materialName := range []*string{postRequest.FirstMaterialName, postRequest.SecondMaterialName} {
+			exists, err := materialpersistence.DoesExist(plData, log, *materialName)
+			if err != nil {
+				errorMessage := "error checking material existence in database"
+				errorStatusCode := http.StatusInternalServerError
+
+				log.WithError(err).Error(errorMessage)
+				controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, err)
+				return
+			}
+			if !exists {
+				errorMessage = fmt.Sprintf("named material %s does not exist", *materialName)
+			}
+		}
+		exists, err := texturepersistence.DoesExist(plData, log, *postRequest.WeightTextureName)
+		if err != nil {
+			errorMessage := "error checking texture existence in database"
+			errorStatusCode := http.StatusInternalServerError
+
+			log.WithError(err).Error(errorMessage)
+			controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, err)
+			return
+		}
+		if !exists {
+			errorMessage = "named weight_texture does not exist"
+		}
+	case materialtype.Light:
+		if postRequest.EmittanceTextureName == nil {
+			errorMessage := "missing field from request"
+			errorStatusCode := http.StatusBadRequest
+
+			log.Error(errorMessage)
+			controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, nil)
+			return
+		}
+		// set defaults for missing optional fields
+		if postRequest.IsTwoSided == nil {
+			isTwoSided := constants.MaterialDefaultLightIsTwoSided
+			postRequest.IsTwoSided = &isTwoSided
+		}
+		if postRequest.SpotAngle == nil {
+			spotAngle := constants.MaterialDefaultLightSpotAngle
+			postRequest.SpotAngle = &spotAngle
+		}
+		if postRequest.SpotBlend == nil {
+			spotBlend := constants.MaterialDefaultLightSpotBlend
+			postRequest.SpotBlend = &spotBlend
+		}
+		if postRequest.IsVisibleToCamera == nil {
+			isVisibleToCamera := constants.MaterialDefaultLightIsVisibleToCamera
+			postRequest.IsVisibleToCamera = &isVisibleToCamera
+		}
+		if postRequest.ReflectanceTextureName != nil {
+			errorMessage = "lights reflect no light, so take no reflectance_texture_name"
+		} else if postRequest.Power != nil && *postRequest.Power <= 0.0 {
+			errorMessage = "power must be greater than zero"
+		} else if *postRequest.SpotAngle <= 0.0 || *postRequest.SpotAngle > 90.0 {
+			errorMessage = "spot_angle must be greater than zero and at most 90"
+		} else if *postRequest.SpotBlend < 0.0 || *postRequest.SpotBlend > 1.0 {
+			errorMessage = "spot_blend must be between 0 and 1"
+		}
 	default:
 		errorMessage = "invalid material_type"
 	}
 
+	// absorption is optional, and only makes sense for materials which let light through
+	var transmittanceColor []float64
+	if postRequest.TransmittanceColor != nil || postRequest.TransmittanceDistance != nil {
+		materialType := materialtype.MaterialType(strings.ToUpper(*postRequest.MaterialType))
+		if materialType != materialtype.Dielectric && materialType != materialtype.RoughDielectric {
+			errorMessage = "transmittance_color and transmittance_distance are only valid for dielectric materials"
+		} else if postRequest.TransmittanceColor == nil || postRequest.TransmittanceDistance == nil {
+			errorMessage = "transmittance_color and transmittance_distance must be given together"
+		} else if postRequest.TransmittanceColor.Red == nil || postRequest.TransmittanceColor.Green == nil ||
+			postRequest.TransmittanceColor.Blue == nil {
+			errorMessage = "transmittance_color must have red, green, and blue fields"
+		} else if *postRequest.TransmittanceColor.Red <= 0.0 || *postRequest.TransmittanceColor.Red > 1.0 ||
+			*postRequest.TransmittanceColor.Green <= 0.0 || *postRequest.TransmittanceColor.Green > 1.0 ||
+			*postRequest.TransmittanceColor.Blue <= 0.0 || *postRequest.TransmittanceColor.Blue > 1.0 {
+			errorMessage = "transmittance_color fields must be greater than zero and at most 1.0"
+		} else if *postRequest.TransmittanceDistance <= 0.0 {
+			errorMessage = "transmittance_distance must be greater than zero"
+		} else {
+			transmittanceColor = []float64{
+				*postRequest.TransmittanceColor.Red,
+				*postRequest.TransmittanceColor.Green,
+				*postRequest.TransmittanceColor.Blue,
+			}
+		}
+	}
+
+	// an interior medium is optional too, for transmissive materials which are cloudy inside rather than clear
+	var absorptionCoefficient, scatteringCoefficient []float64
+	if postRequest.AbsorptionCoefficient != nil || postRequest.ScatteringCoefficient != nil {
+		materialType := materialtype.MaterialType(strings.ToUpper(*postRequest.MaterialType))
+		zero := 0.0
+		noCoefficient := &ColorRequest{Red: &zero, Green: &zero, Blue: &zero}
+		if postRequest.AbsorptionCoefficient == nil {
+			postRequest.AbsorptionCoefficient = noCoefficient
+		}
+		if postRequest.ScatteringCoefficient == nil {
+			postRequest.ScatteringCoefficient = noCoefficient
+		}
+		if postRequest.Anisotropy == nil {
+			defaultAnisotropy := constants.MaterialDefaultMediumAnisotropy
+			postRequest.Anisotropy = &defaultAnisotropy
+		}
+		absorptionCoefficient = arrayFromColorRequest(postRequest.AbsorptionCoefficient)
+		scatteringCoefficient = arrayFromColorRequest(postRequest.ScatteringCoefficient)
+		if materialType != materialtype.Dielectric && materialType != materialtype.RoughDielectric {
+			errorMessage = "absorption_coefficient and scattering_coefficient are only valid for dielectric materials"
+		} else if postRequest.TransmittanceColor != nil {
+			errorMessage = "transmittance_color cannot be given along with an interior medium"
+		} else if absorptionCoefficient == nil || scatteringCoefficient == nil {
+			errorMessage = "absorption_coefficient and scattering_coefficient must have red, green, and blue fields"
+		} else if absorptionCoefficient[0] < 0.0 || absorptionCoefficient[1] < 0.0 || absorptionCoefficient[2] < 0.0 ||
+			scatteringCoefficient[0] < 0.0 || scatteringCoefficient[1] < 0.0 || scatteringCoefficient[2] < 0.0 {
+			errorMessage = "absorption_coefficient and scattering_coefficient fields must be greater than or equal to zero"
+		} else if *postRequest.Anisotropy <= -1.0 || *postRequest.Anisotropy >= 1.0 {
+			errorMessage = "anisotropy must be greater than -1 and less than 1"
+		}
+	} else if postRequest.Anisotropy != nil &&
+		materialtype.MaterialType(strings.ToUpper(*postRequest.MaterialType)) != materialtype.HenyeyGreenstein &&
+		materialtype.MaterialType(strings.ToUpper(*postRequest.MaterialType)) != materialtype.Subsurface {
+		errorMessage = "anisotropy is only valid for henyey_greenstein and subsurface materials and interior media"
+	}
+
+	// normal and bump maps are optional, and only make sense for materials which shade at a surface
+	var bumpStrength *float64
+	if postRequest.NormalTextureName != nil || postRequest.BumpTextureName != nil || postRequest.BumpStrength != nil {
+		switch materialtype.MaterialType(strings.ToUpper(*postRequest.MaterialType)) {
+		case materialtype.Lambertian, materialtype.Metal, materialtype.Dielectric,
+			materialtype.Conductor, materialtype.RoughDielectric, materialtype.Subsurface, materialtype.Principled:
+			bumpStrength = postRequest.BumpStrength
+			if postRequest.BumpTextureName == nil && postRequest.BumpStrength != nil {
+				errorMessage = "bump_strength is only valid with a bump_texture_name"
+			} else if postRequest.BumpTextureName != nil && postRequest.BumpStrength == nil {
+				defaultBumpStrength := constants.MaterialDefaultBumpStrength
+				bumpStrength = &defaultBumpStrength
+			} else if postRequest.BumpStrength != nil && *postRequest.BumpStrength < 0.0 {
+				errorMessage = "bump_strength must be greater than or equal to zero"
+			}
+		default:
+			errorMessage = "normal_texture_name and bump_texture_name are only valid for surface materials"
+		}
+		for _, textureName := range []*string{postRequest.NormalTextureName, postRequest.BumpTextureName} {
+			if textureName == nil {
+				continue
+			}
+			exists, err := texturepersistence.DoesExist(plData, log, *textureName)
+			if err != nil {
+				errorMessage := "error checking texture existence in database"
+				errorStatusCode := http.StatusInternalServerError
+
+				log.WithError(err).Error(errorMessage)
+				controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, err)
+				return
+			}
+			if !exists {
+				errorMessage = fmt.Sprintf("named texture %s does not exist", *textureName)
+			}
+		}
+	}
+
 	// send error
 	if errorMessage != "" {
 		errorStatusCode := http.StatusBadRequest
@@ -301,12 +947,49 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 
 	// assemble material
 	material := &materialpersistence.Material{
-		MaterialName:           *postRequest.MaterialName,
-		MaterialType:           strings.ToUpper(*postRequest.MaterialType),
-		ReflectanceTextureName: postRequest.ReflectanceTextureName,
-		EmittanceTextureName:   postRequest.EmittanceTextureName,
-		Fuzziness:              postRequest.Fuzziness,
-		RefractiveIndex:        postRequest.RefractiveIndex,
+		MaterialName:            *postRequest.MaterialName,
+		MaterialType:            strings.ToUpper(*postRequest.MaterialType),
+		ReflectanceTextureName:  postRequest.ReflectanceTextureName,
+		EmittanceTextureName:    postRequest.EmittanceTextureName,
+		Fuzziness:               postRequest.Fuzziness,
+		RefractiveIndex:         postRequest.RefractiveIndex,
+		RoughnessU:              postRequest.RoughnessU,
+		RoughnessV:              postRequest.RoughnessV,
+		Eta:                     eta,
+		K:                       k,
+		RoughnessTextureName:    postRequest.RoughnessTextureName,
+		TransmittanceColor:      transmittanceColor,
+		TransmittanceDistance:   postRequest.TransmittanceDistance,
+		BaseColor:               baseColor,
+		BaseColorTextureName:    postRequest.BaseColorTextureName,
+		Metallic:                postRequest.Metallic,
+		MetallicTextureName:     postRequest.MetallicTextureName,
+		Roughness:               postRequest.Roughness,
+		Specular:                postRequest.Specular,
+		SpecularTextureName:     postRequest.SpecularTextureName,
+		Clearcoat:               postRequest.Clearcoat,
+		ClearcoatTextureName:    postRequest.ClearcoatTextureName,
+		Sheen:                   postRequest.Sheen,
+		SheenTextureName:        postRequest.SheenTextureName,
+		Transmission:            postRequest.Transmission,
+		TransmissionTextureName: postRequest.TransmissionTextureName,
+		Emission:                emission,
+		FirstMaterialName:       postRequest.FirstMaterialName,
+		SecondMaterialName:      postRequest.SecondMaterialName,
+		WeightTextureName:       postRequest.WeightTextureName,
+		Power:                   postRequest.Power,
+		IsTwoSided:              postRequest.IsTwoSided,
+		SpotAngle:               postRequest.SpotAngle,
+		SpotBlend:               postRequest.SpotBlend,
+		IsVisibleToCamera:       postRequest.IsVisibleToCamera,
+		NormalTextureName:       postRequest.NormalTextureName,
+		BumpTextureName:         postRequest.BumpTextureName,
+		BumpStrength:            bumpStrength,
+		Anisotropy:              postRequest.Anisotropy,
+		AbsorptionCoefficient:   absorptionCoefficient,
+		ScatteringCoefficient:   scatteringCoefficient,
+		Albedo:                  albedo,
+		MeanFreePath:            meanFreePath,
 	}
 
 	// save to db
@@ -323,3 +1006,80 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 	response.WriteHeader(http.StatusCreated)
 	log.Debug("request completed")
 }
+
+// principledParameter validates a scalar parameter of a principled material, given as either a constant or a texture
+// it returns the constant to save, which is defaulted when neither is given, or a message describing invalid input
+func principledParameter(plData *config.PhotolumData, log *logrus.Entry, name string,
+	constant *float64, textureName *string, defaultValue float64) (*float64, string, error) {
+	if textureName != nil {
+		if constant != nil {
+			return nil, fmt.Sprintf("only one of %s and %s_texture_name may be given", name, name), nil
+		}
+		exists, err := texturepersistence.DoesExist(plData, log, *textureName)
+		if err != nil {
+			return nil, "", err
+		}
+		if !exists {
+			return nil, fmt.Sprintf("named %s_texture does not exist", name), nil
+		}
+		return nil, "", nil
+	}
+	if constant == nil {
+		constant = &defaultValue
+	}
+	if *constant < 0.0 || *constant > 1.0 {
+		return nil, fmt.Sprintf("%s must be between 0 and 1", name), nil
+	}
+	return constant, "", nil
+}
+
+// principledColor validates a color parameter of a principled material, given as either a constant or a texture
+// it returns the constant to save, which is defaulted to a gray when neither is given, or a message describing invalid input
+func principledColor(plData *config.PhotolumData, log *logrus.Entry, name string,
+	constant *ColorRequest, textureName *string, defaultValue float64) ([]float64, string, error) {
+	if textureName != nil {
+		if constant != nil {
+			return nil, fmt.Sprintf("only one of %s and %s_texture_name may be given", name, name), nil
+		}
+		exists, err := texturepersistence.DoesExist(plData, log, *textureName)
+		if err != nil {
+			return nil, "", err
+		}
+		if !exists {
+			return nil, fmt.Sprintf("named %s_texture does not exist", name), nil
+		}
+		return nil, "", nil
+	}
+	if constant == nil {
+		return []float64{defaultValue, defaultValue, defaultValue}, "", nil
+	}
+	if constant.Red == nil || constant.Green == nil || constant.Blue == nil {
+		return nil, fmt.Sprintf("%s must have red, green, and blue fields", name), nil
+	}
+	if *constant.Red < 0.0 || *constant.Red > 1.0 ||
+		*constant.Green < 0.0 || *constant.Green > 1.0 ||
+		*constant.Blue < 0.0 || *constant.Blue > 1.0 {
+		return nil, fmt.Sprintf("%s fields must be between 0 and 1", name), nil
+	}
+	return []float64{*constant.Red, *constant.Green, *constant.Blue}, "", nil
+}
+
+// colorFromArray converts a color stored as an array of its channels, returning nil if it isn't set
+func colorFromArray(values []float64) *shading.Color {
+	if values == nil {
+		return nil
+	}
+	return &shading.Color{
+		Red:   values[0],
+		Green: values[1],
+		Blue:  values[2],
+	}
+}
+
+// arrayFromColorRequest converts a color from a request to an array, returning nil if any channel is missing
+func arrayFromColorRequest(color *ColorRequest) []float64 {
+	if color.Red == nil || color.Green == nil || color.Blue == nil {
+		return nil
+	}
+	return []float64{*color.Red, *color.Green, *color.Blue}
+}
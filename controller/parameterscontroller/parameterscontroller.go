@@ -8,11 +8,16 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/paulwrubel/photolum/config"
+	"github.com/paulwrubel/photolum/config/geometry"
 	"github.com/paulwrubel/photolum/config/shading"
 	"github.com/paulwrubel/photolum/constants"
 	"github.com/paulwrubel/photolum/controller"
+	"github.com/paulwrubel/photolum/enumeration/backgroundtype"
 	"github.com/paulwrubel/photolum/enumeration/filetype"
+	"github.com/paulwrubel/photolum/enumeration/filtertype"
+	"github.com/paulwrubel/photolum/enumeration/texturetype"
 	"github.com/paulwrubel/photolum/persistence/parameterspersistence"
+	"github.com/paulwrubel/photolum/persistence/texturepersistence"
 	"github.com/sirupsen/logrus"
 )
 
@@ -24,22 +29,48 @@ type GetRequest struct {
 }
 
 type GetResponse struct {
-	ParametersName           string        `json:"parameters_name"`
-	ImageWidth               uint32        `json:"image_width"`
-	ImageHeight              uint32        `json:"image_height"`
-	FileType                 string        `json:"file_type"`
-	GammaCorrection          float64       `json:"gamma_correction"`
-	UseScalingTruncation     bool          `json:"use_scaling_truncation"`
-	SamplesPerRound          uint32        `json:"samples_per_round"`
-	RoundCount               uint32        `json:"round_count"`
-	TileWidth                uint32        `json:"tile_width"`
-	TileHeight               uint32        `json:"tile_height"`
-	MaxBounces               uint32        `json:"max_bounces"`
-	UseBVH                   bool          `json:"use_bvh"`
-	BackgroundColorMagnitude float64       `json:"background_color_magnitude"`
-	BackgroundColor          shading.Color `json:"background_color"`
-	TMin                     float64       `json:"t_min"`
-	TMax                     float64       `json:"t_max"`
+	ParametersName           string           `json:"parameters_name"`
+	ImageWidth               uint32           `json:"image_width"`
+	ImageHeight              uint32           `json:"image_height"`
+	FileType                 string           `json:"file_type"`
+	GammaCorrection          float64          `json:"gamma_correction"`
+	UseScalingTruncation     bool             `json:"use_scaling_truncation"`
+	SamplesPerRound          uint32           `json:"samples_per_round"`
+	RoundCount               uint32           `json:"round_count"`
+	TileWidth                uint32           `json:"tile_width"`
+	TileHeight               uint32           `json:"tile_height"`
+	MaxBounces               uint32           `json:"max_bounces"`
+	UseBVH                   bool             `json:"use_bvh"`
+	BackgroundColorMagnitude float64          `json:"background_color_magnitude"`
+	BackgroundColor          shading.Color    `json:"background_color"`
+	TMin                     float64          `json:"t_min"`
+	TMax                     float64          `json:"t_max"`
+	ReconstructionFilter     string           `json:"reconstruction_filter"`
+	FilterRadius             float64          `json:"filter_radius"`
+	UseAdaptiveSampling      bool             `json:"use_adaptive_sampling"`
+	AdaptiveThreshold        float64          `json:"adaptive_threshold"`
+	AdaptiveMinimumSamples   uint32           `json:"adaptive_minimum_samples"`
+	NoiseTarget              *float64         `json:"noise_target,omitempty"`
+	Seed                     *int64           `json:"seed,omitempty"`
+	BackgroundType           string           `json:"background_type"`
+	EnvironmentTextureName   *string          `json:"environment_texture_name,omitempty"`
+	EnvironmentRotation      float64          `json:"environment_rotation"`
+	EnvironmentIntensity     float64          `json:"environment_intensity"`
+	SunDirection             *geometry.Vector `json:"sun_direction,omitempty"`
+	Turbidity                float64          `json:"turbidity"`
+	GroundAlbedo             shading.Color    `json:"ground_albedo"`
+	SkyIntensity             float64          `json:"sky_intensity"`
+	SunIntensity             float64          `json:"sun_intensity"`
+	SunAngularDiameter       float64          `json:"sun_angular_diameter"`
+	FogDensity               *float64         `json:"fog_density,omitempty"`
+	FogAlbedo                *shading.Color   `json:"fog_albedo,omitempty"`
+	FogAnisotropy            *float64         `json:"fog_anisotropy,omitempty"`
+}
+
+type VectorRequest struct {
+	X *float64 `json:"x"`
+	Y *float64 `json:"y"`
+	Z *float64 `json:"z"`
 }
 
 type ColorRequest struct {
@@ -49,22 +80,42 @@ type ColorRequest struct {
 }
 
 type PostRequest struct {
-	ParametersName           *string       `json:"parameters_name"`
-	ImageWidth               *uint32       `json:"image_width"`
-	ImageHeight              *uint32       `json:"image_height"`
-	FileType                 *string       `json:"file_type"`
-	GammaCorrection          *float64      `json:"gamma_correction"`
-	UseScalingTruncation     *bool         `json:"use_scaling_truncation"`
-	SamplesPerRound          *uint32       `json:"samples_per_round"`
-	RoundCount               *uint32       `json:"round_count"`
-	TileWidth                *uint32       `json:"tile_width"`
-	TileHeight               *uint32       `json:"tile_height"`
-	MaxBounces               *uint32       `json:"max_bounces"`
-	UseBVH                   *bool         `json:"use_bvh"`
-	BackgroundColorMagnitude *float64      `json:"background_color_magnitude"`
-	BackgroundColor          *ColorRequest `json:"background_color"`
-	TMin                     *float64      `json:"t_min"`
-	TMax                     *float64      `json:"t_max"`
+	ParametersName           *string        `json:"parameters_name"`
+	ImageWidth               *uint32        `json:"image_width"`
+	ImageHeight              *uint32        `json:"image_height"`
+	FileType                 *string        `json:"file_type"`
+	GammaCorrection          *float64       `json:"gamma_correction"`
+	UseScalingTruncation     *bool          `json:"use_scaling_truncation"`
+	SamplesPerRound          *uint32        `json:"samples_per_round"`
+	RoundCount               *uint32        `json:"round_count"`
+	TileWidth                *uint32        `json:"tile_width"`
+	TileHeight               *uint32        `json:"tile_height"`
+	MaxBounces               *uint32        `json:"max_bounces"`
+	UseBVH                   *bool          `json:"use_bvh"`
+	BackgroundColorMagnitude *float64       `json:"background_color_magnitude"`
+	BackgroundColor          *ColorRequest  `json:"background_color"`
+	TMin                     *float64       `json:"t_min"`
+	TMax                     *float64       `json:"t_max"`
+	ReconstructionFilter     *string        `json:"reconstruction_filter"`
+	FilterRadius             *float64       `json:"filter_radius"`
+	UseAdaptiveSampling      *bool          `json:"use_adaptive_sampling"`
+	AdaptiveThreshold        *float64       `json:"adaptive_threshold"`
+	AdaptiveMinimumSamples   *uint32        `json:"adaptive_minimum_samples"`
+	NoiseTarget              *float64       `json:"noise_target"`
+	Seed                     *int64         `json:"seed"`
+	BackgroundType           *string        `json:"background_type"`
+	EnvironmentTextureName   *string        `json:"environment_texture_name"`
+	EnvironmentRotation      *float64       `json:"environment_rotation"`
+	EnvironmentIntensity     *float64       `json:"environment_intensity"`
+	SunDirection             *VectorRequest `json:"sun_direction"`
+	Turbidity                *float64       `json:"turbidity"`
+	GroundAlbedo             *ColorRequest  `json:"ground_albedo"`
+	SkyIntensity             *float64       `json:"sky_intensity"`
+	SunIntensity             *float64       `json:"sun_intensity"`
+	SunAngularDiameter       *float64       `json:"sun_angular_diameter"`
+	FogDensity               *float64       `json:"fog_density"`
+	FogAlbedo                *ColorRequest  `json:"fog_albedo"`
+	FogAnisotropy            *float64       `json:"fog_anisotropy"`
 }
 
 func GetHandler(response http.ResponseWriter, request *http.Request, plData *config.PhotolumData, baseLog *logrus.Logger) {
@@ -147,8 +198,44 @@ func GetHandler(response http.ResponseWriter, request *http.Request, plData *con
 			Green: parameters.BackgroundColor[1],
 			Blue:  parameters.BackgroundColor[2],
 		},
-		TMin: parameters.TMin,
-		TMax: parameters.TMax,
+		TMin:                   parameters.TMin,
+		TMax:                   parameters.TMax,
+		ReconstructionFilter:   parameters.ReconstructionFilter,
+		FilterRadius:           parameters.FilterRadius,
+		UseAdaptiveSampling:    parameters.UseAdaptiveSampling,
+		AdaptiveThreshold:      parameters.AdaptiveThreshold,
+		NoiseTarget:            parameters.NoiseTarget,
+		AdaptiveMinimumSamples: parameters.AdaptiveMinimumSamples,
+		Seed:                   parameters.Seed,
+		BackgroundType:         parameters.BackgroundType,
+		EnvironmentTextureName: parameters.EnvironmentTextureName,
+		EnvironmentRotation:    parameters.EnvironmentRotation,
+		EnvironmentIntensity:   parameters.EnvironmentIntensity,
+		Turbidity:              parameters.Turbidity,
+		GroundAlbedo: shading.Color{
+			Red:   parameters.GroundAlbedo[0],
+			Green: parameters.GroundAlbedo[1],
+			Blue:  parameters.GroundAlbedo[2],
+		},
+		SkyIntensity:       parameters.SkyIntensity,
+		SunIntensity:       parameters.SunIntensity,
+		SunAngularDiameter: parameters.SunAngularDiameter,
+		FogDensity:         parameters.FogDensity,
+		FogAnisotropy:      parameters.FogAnisotropy,
+	}
+	if parameters.FogAlbedo != nil {
+		getResponse.FogAlbedo = &shading.Color{
+			Red:   parameters.FogAlbedo[0],
+			Green: parameters.FogAlbedo[1],
+			Blue:  parameters.FogAlbedo[2],
+		}
+	}
+	if parameters.SunDirection != nil {
+		getResponse.SunDirection = &geometry.Vector{
+			X: parameters.SunDirection[0],
+			Y: parameters.SunDirection[1],
+			Z: parameters.SunDirection[2],
+		}
 	}
 	response.Header().Add("Content-Type", "application/json")
 	response.WriteHeader(http.StatusOK)
@@ -268,6 +355,199 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 	if *postRequest.TMax > constants.ParametersMaximumTMax {
 		errorMessage = fmt.Sprintf("t_max field must not exceed %f", constants.ParametersMaximumTMax)
 	}
+	// the reconstruction filter is optional, and defaults to a single-pixel box
+	if postRequest.ReconstructionFilter == nil {
+		defaultFilter := constants.ParametersDefaultReconstructionFilter
+		postRequest.ReconstructionFilter = &defaultFilter
+	}
+	if postRequest.FilterRadius == nil {
+		defaultRadius := constants.ParametersDefaultFilterRadius
+		postRequest.FilterRadius = &defaultRadius
+	}
+	switch filtertype.FilterType(strings.ToUpper(*postRequest.ReconstructionFilter)) {
+	case filtertype.Box:
+	case filtertype.Tent:
+	case filtertype.Gaussian:
+	case filtertype.Mitchell:
+	case filtertype.Lanczos:
+	default:
+		errorMessage = "invalid reconstruction_filter"
+	}
+	reconstructionFilter := strings.ToUpper(*postRequest.ReconstructionFilter)
+	if *postRequest.FilterRadius <= 0.0 {
+		errorMessage = "filter_radius must be greater than zero"
+	}
+	if *postRequest.FilterRadius > constants.ParametersMaximumFilterRadius {
+		errorMessage = fmt.Sprintf("filter_radius must not exceed %f", constants.ParametersMaximumFilterRadius)
+	}
+	// adaptive sampling is optional, and disabled by default
+	if postRequest.UseAdaptiveSampling == nil {
+		useAdaptiveSampling := false
+		postRequest.UseAdaptiveSampling = &useAdaptiveSampling
+	}
+	if postRequest.AdaptiveThreshold == nil {
+		defaultThreshold := constants.ParametersDefaultAdaptiveThreshold
+		postRequest.AdaptiveThreshold = &defaultThreshold
+	}
+	if postRequest.AdaptiveMinimumSamples == nil {
+		defaultMinimumSamples := constants.ParametersDefaultAdaptiveMinimumSamples
+		postRequest.AdaptiveMinimumSamples = &defaultMinimumSamples
+	}
+	if *postRequest.AdaptiveThreshold <= 0.0 {
+		errorMessage = "adaptive_threshold must be greater than zero"
+	}
+	if *postRequest.AdaptiveMinimumSamples < 2 {
+		errorMessage = "adaptive_minimum_samples must be at least 2"
+	}
+	if postRequest.NoiseTarget != nil && *postRequest.NoiseTarget <= 0.0 {
+		errorMessage = "noise_target must be greater than zero"
+	}
+	// the background is optional, and defaults to the flat background_color
+	if postRequest.BackgroundType == nil {
+		defaultBackgroundType := constants.ParametersDefaultBackgroundType
+		postRequest.BackgroundType = &defaultBackgroundType
+	}
+	if postRequest.EnvironmentRotation == nil {
+		defaultRotation := constants.ParametersDefaultEnvironmentRotation
+		postRequest.EnvironmentRotation = &defaultRotation
+	}
+	if postRequest.EnvironmentIntensity == nil {
+		defaultIntensity := constants.ParametersDefaultEnvironmentIntensity
+		postRequest.EnvironmentIntensity = &defaultIntensity
+	}
+	backgroundType := strings.ToUpper(*postRequest.BackgroundType)
+	switch backgroundtype.BackgroundType(backgroundType) {
+	case backgroundtype.Color:
+	case backgroundtype.EnvironmentMap:
+		if postRequest.EnvironmentTextureName == nil {
+			errorMessage = "environment_texture_name is required for background_type ENVIRONMENT_MAP"
+			break
+		}
+		// the environment must be an existing image texture
+		exists, err := texturepersistence.DoesExist(plData, log, *postRequest.EnvironmentTextureName)
+		if err != nil {
+			errorMessage := "error checking texture existence in database"
+			errorStatusCode := http.StatusInternalServerError
+
+			log.WithError(err).Error(errorMessage)
+			controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, err)
+			return
+		}
+		if !exists {
+			errorMessage = "named environment texture does not exist"
+			break
+		}
+		textureDB, err := texturepersistence.Get(plData, log, *postRequest.EnvironmentTextureName)
+		if err != nil {
+			errorMessage := "error getting texture from database"
+			errorStatusCode := http.StatusInternalServerError
+
+			log.WithError(err).Error(errorMessage)
+			controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, err)
+			return
+		}
+		if texturetype.TextureType(textureDB.TextureType) != texturetype.Image {
+			errorMessage = "named environment texture must be of texture_type IMAGE"
+		}
+	case backgroundtype.Sky:
+		if postRequest.SunDirection == nil {
+			errorMessage = "sun_direction is required for background_type SKY"
+		}
+	default:
+		errorMessage = "invalid background_type"
+	}
+	if *postRequest.EnvironmentIntensity < 0.0 {
+		errorMessage = "environment_intensity must be greater than or equal to zero"
+	}
+	// the sky's parameters are likewise optional, except for the position of the sun
+	if postRequest.Turbidity == nil {
+		defaultTurbidity := constants.ParametersDefaultTurbidity
+		postRequest.Turbidity = &defaultTurbidity
+	}
+	if postRequest.GroundAlbedo == nil {
+		defaultRed := constants.ParametersDefaultGroundAlbedo
+		defaultGreen := constants.ParametersDefaultGroundAlbedo
+		defaultBlue := constants.ParametersDefaultGroundAlbedo
+		postRequest.GroundAlbedo = &ColorRequest{
+			Red:   &defaultRed,
+			Green: &defaultGreen,
+			Blue:  &defaultBlue,
+		}
+	}
+	if postRequest.SkyIntensity == nil {
+		defaultSkyIntensity := constants.ParametersDefaultSkyIntensity
+		postRequest.SkyIntensity = &defaultSkyIntensity
+	}
+	if postRequest.SunIntensity == nil {
+		defaultSunIntensity := constants.ParametersDefaultSunIntensity
+		postRequest.SunIntensity = &defaultSunIntensity
+	}
+	if postRequest.SunAngularDiameter == nil {
+		defaultSunAngularDiameter := constants.ParametersDefaultSunAngularDiameter
+		postRequest.SunAngularDiameter = &defaultSunAngularDiameter
+	}
+	var sunDirection []float64
+	if postRequest.SunDirection != nil {
+		if postRequest.SunDirection.X == nil || postRequest.SunDirection.Y == nil || postRequest.SunDirection.Z == nil {
+			errorMessage = "sun_direction must have x, y, and z fields"
+		} else if *postRequest.SunDirection.Y <= 0.0 {
+			errorMessage = "sun_direction must point above the horizon (y greater than zero)"
+		} else {
+			sunDirection = []float64{*postRequest.SunDirection.X, *postRequest.SunDirection.Y, *postRequest.SunDirection.Z}
+		}
+	}
+	if *postRequest.Turbidity < constants.ParametersMinimumTurbidity || *postRequest.Turbidity > constants.ParametersMaximumTurbidity {
+		errorMessage = fmt.Sprintf("turbidity must be between %f and %f", constants.ParametersMinimumTurbidity, constants.ParametersMaximumTurbidity)
+	}
+	if postRequest.GroundAlbedo.Red == nil || postRequest.GroundAlbedo.Green == nil || postRequest.GroundAlbedo.Blue == nil {
+		errorMessage = "ground_albedo must have red, green, and blue fields"
+	} else if *postRequest.GroundAlbedo.Red < 0.0 || *postRequest.GroundAlbedo.Green < 0.0 || *postRequest.GroundAlbedo.Blue < 0.0 ||
+		*postRequest.GroundAlbedo.Red > 1.0 || *postRequest.GroundAlbedo.Green > 1.0 || *postRequest.GroundAlbedo.Blue > 1.0 {
+		errorMessage = "ground_albedo fields must be between 0 and 1"
+	}
+	if *postRequest.SkyIntensity < 0.0 {
+		errorMessage = "sky_intensity must be greater than or equal to zero"
+	}
+	if *postRequest.SunIntensity < 0.0 {
+		errorMessage = "sun_intensity must be greater than or equal to zero"
+	}
+	if *postRequest.SunAngularDiameter <= 0.0 || *postRequest.SunAngularDiameter >= 180.0 {
+		errorMessage = "sun_angular_diameter must be greater than zero and less than 180"
+	}
+	// fog is optional, and its color and anisotropy are only meaningful when it has a density
+	var fogAlbedo []float64
+	if postRequest.FogDensity == nil {
+		if postRequest.FogAlbedo != nil || postRequest.FogAnisotropy != nil {
+			errorMessage = "fog_albedo and fog_anisotropy are only valid with a fog_density"
+		}
+	} else {
+		if postRequest.FogAlbedo == nil {
+			defaultRed := constants.ParametersDefaultFogAlbedo
+			defaultGreen := constants.ParametersDefaultFogAlbedo
+			defaultBlue := constants.ParametersDefaultFogAlbedo
+			postRequest.FogAlbedo = &ColorRequest{
+				Red:   &defaultRed,
+				Green: &defaultGreen,
+				Blue:  &defaultBlue,
+			}
+		}
+		if postRequest.FogAnisotropy == nil {
+			defaultFogAnisotropy := constants.ParametersDefaultFogAnisotropy
+			postRequest.FogAnisotropy = &defaultFogAnisotropy
+		}
+		if *postRequest.FogDensity <= 0.0 {
+			errorMessage = "fog_density must be greater than zero"
+		} else if postRequest.FogAlbedo.Red == nil || postRequest.FogAlbedo.Green == nil || postRequest.FogAlbedo.Blue == nil {
+			errorMessage = "fog_albedo must have red, green, and blue fields"
+		} else if *postRequest.FogAlbedo.Red < 0.0 || *postRequest.FogAlbedo.Green < 0.0 || *postRequest.FogAlbedo.Blue < 0.0 ||
+			*postRequest.FogAlbedo.Red > 1.0 || *postRequest.FogAlbedo.Green > 1.0 || *postRequest.FogAlbedo.Blue > 1.0 {
+			errorMessage = "fog_albedo fields must be between 0 and 1"
+		} else if *postRequest.FogAnisotropy <= -1.0 || *postRequest.FogAnisotropy >= 1.0 {
+			errorMessage = "fog_anisotropy must be greater than -1 and less than 1"
+		} else {
+			fogAlbedo = []float64{*postRequest.FogAlbedo.Red, *postRequest.FogAlbedo.Green, *postRequest.FogAlbedo.Blue}
+		}
+	}
 
 	// send error
 	if errorMessage != "" {
@@ -317,8 +597,32 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 			*(postRequest.BackgroundColor.Green),
 			*(postRequest.BackgroundColor.Blue),
 		},
-		TMin: *(postRequest.TMin),
-		TMax: *(postRequest.TMax),
+		TMin:                   *(postRequest.TMin),
+		TMax:                   *(postRequest.TMax),
+		ReconstructionFilter:   reconstructionFilter,
+		FilterRadius:           *(postRequest.FilterRadius),
+		UseAdaptiveSampling:    *(postRequest.UseAdaptiveSampling),
+		AdaptiveThreshold:      *(postRequest.AdaptiveThreshold),
+		AdaptiveMinimumSamples: *(postRequest.AdaptiveMinimumSamples),
+		NoiseTarget:            postRequest.NoiseTarget,
+		Seed:                   postRequest.Seed,
+		BackgroundType:         backgroundType,
+		EnvironmentTextureName: postRequest.EnvironmentTextureName,
+		EnvironmentRotation:    *(postRequest.EnvironmentRotation),
+		EnvironmentIntensity:   *(postRequest.EnvironmentIntensity),
+		SunDirection:           sunDirection,
+		Turbidity:              *(postRequest.Turbidity),
+		GroundAlbedo: []float64{
+			*(postRequest.GroundAlbedo.Red),
+			*(postRequest.GroundAlbedo.Green),
+			*(postRequest.GroundAlbedo.Blue),
+		},
+		SkyIntensity:       *(postRequest.SkyIntensity),
+		SunIntensity:       *(postRequest.SunIntensity),
+		SunAngularDiameter: *(postRequest.SunAngularDiameter),
+		FogDensity:         postRequest.FogDensity,
+		FogAlbedo:          fogAlbedo,
+		FogAnisotropy:      postRequest.FogAnisotropy,
 	}
 
 	// save to db
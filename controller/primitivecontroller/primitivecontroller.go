@@ -1,15 +1,20 @@
 package primitivecontroller
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/google/uuid"
 	"github.com/paulwrubel/photolum/config"
 	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/geometry/primitive/participatingvolume"
+	"github.com/paulwrubel/photolum/constants"
 	"github.com/paulwrubel/photolum/controller"
 	"github.com/paulwrubel/photolum/enumeration/axis"
+	"github.com/paulwrubel/photolum/enumeration/densitytype"
 	"github.com/paulwrubel/photolum/enumeration/primitivetype"
 	"github.com/paulwrubel/photolum/enumeration/rotationorder"
 	"github.com/paulwrubel/photolum/persistence/primitivepersistence"
@@ -24,10 +29,17 @@ type GetRequest struct {
 }
 
 type ParticipatingVolumeGetResponse struct {
-	PrimitiveName             string  `json:"primitive_name"`
-	PrimitiveType             string  `json:"primitive_type"`
-	EncapsulatedPrimitiveName string  `json:"encapsulated_primitive_name"`
-	Density                   float64 `json:"density"`
+	PrimitiveName             string   `json:"primitive_name"`
+	PrimitiveType             string   `json:"primitive_type"`
+	EncapsulatedPrimitiveName string   `json:"encapsulated_primitive_name"`
+	Density                   float64  `json:"density"`
+	DensityType               string   `json:"density_type"`
+	NoiseFrequency            *float64 `json:"noise_frequency,omitempty"`
+	NoiseOctaves              *int32   `json:"noise_octaves,omitempty"`
+	NoiseCoverage             *float64 `json:"noise_coverage,omitempty"`
+	NoiseSeed                 *int64   `json:"noise_seed,omitempty"`
+	VoxelResolution           []int32  `json:"voxel_resolution,omitempty"`
+	VoxelData                 string   `json:"voxel_data,omitempty"`
 }
 
 type SphereGetResponse struct {
@@ -64,23 +76,34 @@ type RectangleGetResponse struct {
 	HasNegativeNormal bool           `json:"has_negative_normal"`
 }
 
+type TextureCoordinate struct {
+	U float64 `json:"u"`
+	V float64 `json:"v"`
+}
+
 type TriangleGetResponse struct {
-	PrimitiveName string         `json:"primitive_name"`
-	PrimitiveType string         `json:"primitive_type"`
-	A             geometry.Point `json:"a"`
-	B             geometry.Point `json:"b"`
-	C             geometry.Point `json:"c"`
+	PrimitiveName string             `json:"primitive_name"`
+	PrimitiveType string             `json:"primitive_type"`
+	A             geometry.Point     `json:"a"`
+	B             geometry.Point     `json:"b"`
+	C             geometry.Point     `json:"c"`
+	AUV           *TextureCoordinate `json:"a_uv,omitempty"`
+	BUV           *TextureCoordinate `json:"b_uv,omitempty"`
+	CUV           *TextureCoordinate `json:"c_uv,omitempty"`
 }
 
 type TriangleWithNormalsGetResponse struct {
-	PrimitiveName string          `json:"primitive_name"`
-	PrimitiveType string          `json:"primitive_type"`
-	A             geometry.Point  `json:"a"`
-	B             geometry.Point  `json:"b"`
-	C             geometry.Point  `json:"c"`
-	ANormal       geometry.Vector `json:"a_normal"`
-	BNormal       geometry.Vector `json:"b_normal"`
-	CNormal       geometry.Vector `json:"c_normal"`
+	PrimitiveName string             `json:"primitive_name"`
+	PrimitiveType string             `json:"primitive_type"`
+	A             geometry.Point     `json:"a"`
+	B             geometry.Point     `json:"b"`
+	C             geometry.Point     `json:"c"`
+	ANormal       geometry.Vector    `json:"a_normal"`
+	BNormal       geometry.Vector    `json:"b_normal"`
+	CNormal       geometry.Vector    `json:"c_normal"`
+	AUV           *TextureCoordinate `json:"a_uv,omitempty"`
+	BUV           *TextureCoordinate `json:"b_uv,omitempty"`
+	CUV           *TextureCoordinate `json:"c_uv,omitempty"`
 }
 
 type PlaneGetResponse struct {
@@ -130,38 +153,85 @@ type QuaternionGetResponse struct {
 	RotationOrder             string    `json:"rotation_order"`
 }
 
+type TranslationKeyframe struct {
+	Moment       float64         `json:"moment"`
+	Displacement geometry.Vector `json:"displacement"`
+}
+
+type KeyframedTranslationGetResponse struct {
+	PrimitiveName             string                `json:"primitive_name"`
+	PrimitiveType             string                `json:"primitive_type"`
+	EncapsulatedPrimitiveName string                `json:"encapsulated_primitive_name"`
+	Keyframes                 []TranslationKeyframe `json:"keyframes"`
+}
+
+type RotationKeyframe struct {
+	Moment     float64   `json:"moment"`
+	AxisAngles []float64 `json:"axis_angles"`
+}
+
+type KeyframedRotationGetResponse struct {
+	PrimitiveName             string             `json:"primitive_name"`
+	PrimitiveType             string             `json:"primitive_type"`
+	EncapsulatedPrimitiveName string             `json:"encapsulated_primitive_name"`
+	Keyframes                 []RotationKeyframe `json:"keyframes"`
+	RotationOrder             string             `json:"rotation_order"`
+}
+
 type VectorRequest struct {
 	X *float64 `json:"x"`
 	Y *float64 `json:"y"`
 	Z *float64 `json:"z"`
 }
 
+type TextureCoordinateRequest struct {
+	U *float64 `json:"u"`
+	V *float64 `json:"v"`
+}
+
+type KeyframeRequest struct {
+	Moment       *float64       `json:"moment"`
+	Displacement *VectorRequest `json:"displacement"`
+	AxisAngles   []float64      `json:"axis_angles"`
+}
+
 type PostRequest struct {
-	PrimitiveName             *string        `json:"primitive_name"`
-	PrimitiveType             *string        `json:"primitive_type"`
-	EncapsulatedPrimitiveName *string        `json:"encapsulated_primitive_name"`
-	A                         *VectorRequest `json:"a"`
-	B                         *VectorRequest `json:"b"`
-	C                         *VectorRequest `json:"c"`
-	ANormal                   *VectorRequest `json:"a_normal"`
-	BNormal                   *VectorRequest `json:"b_normal"`
-	CNormal                   *VectorRequest `json:"c_normal"`
-	Point                     *VectorRequest `json:"point"`
-	Normal                    *VectorRequest `json:"normal"`
-	Center                    *VectorRequest `json:"center"`
-	Axis                      *string        `json:"axis"`
-	Displacement              *VectorRequest `json:"displacement"`
-	AxisAngles                []float64      `json:"axis_angles"`
-	RotationOrder             *string        `json:"rotation_order"`
-	Radius                    *float64       `json:"radius"`
-	InnerRadius               *float64       `json:"inner_radius"`
-	OuterRadius               *float64       `json:"outer_radius"`
-	Height                    *float64       `json:"height"`
-	Angle                     *float64       `json:"angle"`
-	Density                   *float64       `json:"density"`
-	IsCulled                  *bool          `json:"is_culled"`
-	HasNegativeNormal         *bool          `json:"has_negative_normal"`
-	HasInvertedNormals        *bool          `json:"has_inverted_normals"`
+	PrimitiveName             *string                   `json:"primitive_name"`
+	PrimitiveType             *string                   `json:"primitive_type"`
+	EncapsulatedPrimitiveName *string                   `json:"encapsulated_primitive_name"`
+	A                         *VectorRequest            `json:"a"`
+	B                         *VectorRequest            `json:"b"`
+	C                         *VectorRequest            `json:"c"`
+	ANormal                   *VectorRequest            `json:"a_normal"`
+	BNormal                   *VectorRequest            `json:"b_normal"`
+	CNormal                   *VectorRequest            `json:"c_normal"`
+	Point                     *VectorRequest            `json:"point"`
+	Normal                    *VectorRequest            `json:"normal"`
+	Center                    *VectorRequest            `json:"center"`
+	Axis                      *string                   `json:"axis"`
+	Displacement              *VectorRequest            `json:"displacement"`
+	AxisAngles                []float64                 `json:"axis_angles"`
+	RotationOrder             *string                   `json:"rotation_order"`
+	Keyframes                 []KeyframeRequest         `json:"keyframes"`
+	Radius                    *float64                  `json:"radius"`
+	InnerRadius               *float64                  `json:"inner_radius"`
+	OuterRadius               *float64                  `json:"outer_radius"`
+	Height                    *float64                  `json:"height"`
+	Angle                     *float64                  `json:"angle"`
+	Density                   *float64                  `json:"density"`
+	IsCulled                  *bool                     `json:"is_culled"`
+	HasNegativeNormal         *bool                     `json:"has_negative_normal"`
+	HasInvertedNormals        *bool                     `json:"has_inverted_normals"`
+	AUV                       *TextureCoordinateRequest `json:"a_uv"`
+	BUV                       *TextureCoordinateRequest `json:"b_uv"`
+	CUV                       *TextureCoordinateRequest `json:"c_uv"`
+	DensityType               *string                   `json:"density_type"`
+	NoiseFrequency            *float64                  `json:"noise_frequency"`
+	NoiseOctaves              *int32                    `json:"noise_octaves"`
+	NoiseCoverage             *float64                  `json:"noise_coverage"`
+	NoiseSeed                 *int64                    `json:"noise_seed"`
+	VoxelResolution           []int32                   `json:"voxel_resolution"`
+	VoxelData                 *string                   `json:"voxel_data"`
 }
 
 func GetHandler(response http.ResponseWriter, request *http.Request, plData *config.PhotolumData, baseLog *logrus.Logger) {
@@ -230,11 +300,23 @@ func GetHandler(response http.ResponseWriter, request *http.Request, plData *con
 	var getResponse interface{}
 	switch primitivetype.PrimitiveType(primitive.PrimitiveType) {
 	case primitivetype.ParticipatingVolume:
+		// volumes saved before density types existed are constant
+		densityType := constants.PrimitiveDefaultDensityType
+		if primitive.DensityType != nil {
+			densityType = *primitive.DensityType
+		}
 		getResponse = ParticipatingVolumeGetResponse{
 			PrimitiveName:             primitive.PrimitiveName,
 			PrimitiveType:             primitive.PrimitiveType,
 			EncapsulatedPrimitiveName: *primitive.EncapsulatedPrimitiveName,
 			Density:                   *primitive.Density,
+			DensityType:               densityType,
+			NoiseFrequency:            primitive.NoiseFrequency,
+			NoiseOctaves:              primitive.NoiseOctaves,
+			NoiseCoverage:             primitive.NoiseCoverage,
+			NoiseSeed:                 primitive.NoiseSeed,
+			VoxelResolution:           primitive.VoxelResolution,
+			VoxelData:                 base64.StdEncoding.EncodeToString(primitive.VoxelData),
 		}
 	case primitivetype.Sphere:
 		getResponse = SphereGetResponse{
@@ -318,6 +400,9 @@ func GetHandler(response http.ResponseWriter, request *http.Request, plData *con
 					Y: primitive.C[1],
 					Z: primitive.C[2],
 				},
+				AUV: textureCoordinateFromArray(primitive.AUV),
+				BUV: textureCoordinateFromArray(primitive.BUV),
+				CUV: textureCoordinateFromArray(primitive.CUV),
 			}
 		} else {
 			getResponse = TriangleWithNormalsGetResponse{
@@ -353,6 +438,9 @@ func GetHandler(response http.ResponseWriter, request *http.Request, plData *con
 					Y: primitive.CNormal[1],
 					Z: primitive.CNormal[2],
 				},
+				AUV: textureCoordinateFromArray(primitive.AUV),
+				BUV: textureCoordinateFromArray(primitive.BUV),
+				CUV: textureCoordinateFromArray(primitive.CUV),
 			}
 		}
 	case primitivetype.Plane:
@@ -430,6 +518,41 @@ func GetHandler(response http.ResponseWriter, request *http.Request, plData *con
 			AxisAngles:                primitive.AxisAngles,
 			RotationOrder:             *primitive.RotationOrder,
 		}
+	case primitivetype.KeyframedTranslation:
+		// each keyframe's displacement is stored as three consecutive values
+		keyframes := make([]TranslationKeyframe, len(primitive.KeyframeMoments))
+		for i, moment := range primitive.KeyframeMoments {
+			keyframes[i] = TranslationKeyframe{
+				Moment: moment,
+				Displacement: geometry.Vector{
+					X: primitive.KeyframeValues[3*i],
+					Y: primitive.KeyframeValues[3*i+1],
+					Z: primitive.KeyframeValues[3*i+2],
+				},
+			}
+		}
+		getResponse = KeyframedTranslationGetResponse{
+			PrimitiveName:             primitive.PrimitiveName,
+			PrimitiveType:             primitive.PrimitiveType,
+			EncapsulatedPrimitiveName: *primitive.EncapsulatedPrimitiveName,
+			Keyframes:                 keyframes,
+		}
+	case primitivetype.KeyframedRotation:
+		// each keyframe's axis angles are stored as three consecutive values
+		keyframes := make([]RotationKeyframe, len(primitive.KeyframeMoments))
+		for i, moment := range primitive.KeyframeMoments {
+			keyframes[i] = RotationKeyframe{
+				Moment:     moment,
+				AxisAngles: primitive.KeyframeValues[3*i : 3*i+3],
+			}
+		}
+		getResponse = KeyframedRotationGetResponse{
+			PrimitiveName:             primitive.PrimitiveName,
+			PrimitiveType:             primitive.PrimitiveType,
+			EncapsulatedPrimitiveName: *primitive.EncapsulatedPrimitiveName,
+			Keyframes:                 keyframes,
+			RotationOrder:             *primitive.RotationOrder,
+		}
 	}
 
 	response.Header().Add("Content-Type", "application/json")
@@ -474,6 +597,12 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 
 	// validate input
 	errorMessage := ""
+	var densityType string
+	var noiseFrequency, noiseCoverage *float64
+	var noiseOctaves *int32
+	var noiseSeed *int64
+	var voxelData []byte
+	var keyframeMoments, keyframeValues []float64
 
 	// do the named encapsulated primitives exist?
 	if postRequest.EncapsulatedPrimitiveName != nil {
@@ -505,6 +634,89 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 		if *postRequest.Density <= 0.0 {
 			errorMessage = "density must be greater than zero"
 		}
+		densityType = constants.PrimitiveDefaultDensityType
+		if postRequest.DensityType != nil {
+			densityType = strings.ToUpper(*postRequest.DensityType)
+		}
+		hasNoiseFields := postRequest.NoiseFrequency != nil || postRequest.NoiseOctaves != nil ||
+			postRequest.NoiseCoverage != nil || postRequest.NoiseSeed != nil
+		hasVoxelFields := postRequest.VoxelResolution != nil || postRequest.VoxelData != nil
+		switch densitytype.DensityType(densityType) {
+		case densitytype.Constant:
+			if hasNoiseFields || hasVoxelFields {
+				errorMessage = "noise and voxel fields are only valid for their density_type"
+			}
+		case densitytype.Noise:
+			if hasVoxelFields {
+				errorMessage = "voxel fields are only valid for density_type VOXEL_GRID"
+				break
+			}
+			defaultNoiseFrequency := constants.PrimitiveDefaultNoiseFrequency
+			noiseFrequency = &defaultNoiseFrequency
+			if postRequest.NoiseFrequency != nil {
+				noiseFrequency = postRequest.NoiseFrequency
+			}
+			defaultNoiseOctaves := constants.PrimitiveDefaultNoiseOctaves
+			noiseOctaves = &defaultNoiseOctaves
+			if postRequest.NoiseOctaves != nil {
+				noiseOctaves = postRequest.NoiseOctaves
+			}
+			defaultNoiseCoverage := constants.PrimitiveDefaultNoiseCoverage
+			noiseCoverage = &defaultNoiseCoverage
+			if postRequest.NoiseCoverage != nil {
+				noiseCoverage = postRequest.NoiseCoverage
+			}
+			defaultNoiseSeed := constants.PrimitiveDefaultNoiseSeed
+			noiseSeed = &defaultNoiseSeed
+			if postRequest.NoiseSeed != nil {
+				noiseSeed = postRequest.NoiseSeed
+			}
+			if *noiseFrequency <= 0.0 {
+				errorMessage = "noise_frequency must be greater than zero"
+			} else if *noiseOctaves < 1 || *noiseOctaves > constants.PrimitiveMaximumNoiseOctaves {
+				errorMessage = fmt.Sprintf("noise_octaves must be between 1 and %d", constants.PrimitiveMaximumNoiseOctaves)
+			} else if *noiseCoverage <= 0.0 || *noiseCoverage > 1.0 {
+				errorMessage = "noise_coverage must be greater than zero and at most 1"
+			}
+		case densitytype.VoxelGrid:
+			if hasNoiseFields {
+				errorMessage = "noise fields are only valid for density_type NOISE"
+				break
+			}
+			if postRequest.VoxelResolution == nil || postRequest.VoxelData == nil {
+				errorMessage := "missing field from request"
+				errorStatusCode := http.StatusBadRequest
+
+				log.Error(errorMessage)
+				controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, nil)
+				return
+			}
+			if len(postRequest.VoxelResolution) != 3 {
+				errorMessage = "voxel_resolution must have x, y, and z entries"
+				break
+			}
+			voxelCount := 1
+			for _, resolution := range postRequest.VoxelResolution {
+				if resolution < 1 {
+					errorMessage = "voxel_resolution entries must be at least 1"
+				}
+				voxelCount *= int(resolution)
+			}
+			var err error
+			voxelData, err = base64.StdEncoding.DecodeString(*postRequest.VoxelData)
+			if err != nil {
+				errorMessage = "could not decode voxel_data"
+				break
+			}
+			voxels, err := participatingvolume.DecodeVoxels(voxelData)
+			if err != nil {
+				errorMessage = err.Error()
+			} else if errorMessage == "" && len(voxels) != voxelCount {
+				errorMessage = "voxel_data must have one density for each voxel in voxel_resolution"
+			}
+		default:
+			errorMessage = "invalid density_type"
+		}
 	case primitivetype.Sphere:
 		if postRequest.Center == nil ||
 			postRequest.Radius == nil ||
@@ -693,6 +905,19 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 				errorMessage = "triangle c_normal must not have zero magnitude"
 			}
 		}
+
+		uvCount := 0
+		for _, uv := range []*TextureCoordinateRequest{postRequest.AUV, postRequest.BUV, postRequest.CUV} {
+			if uv != nil {
+				uvCount++
+				if uv.U == nil || uv.V == nil {
+					errorMessage = "triangle texture coordinates must have u and v fields"
+				}
+			}
+		}
+		if uvCount != 0 && uvCount != 3 {
+			errorMessage = "triangle must have either all texture coordinates specified or no texture coordinates specified"
+		}
 	case primitivetype.Plane:
 		if postRequest.Point == nil ||
 			postRequest.Normal == nil ||
@@ -799,20 +1024,58 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 		if len(postRequest.AxisAngles) != 3 {
 			errorMessage = "invalid number of axis angles, should be 3"
 		}
-		switch rotationorder.RotationOrder(strings.ToUpper(*postRequest.RotationOrder)) {
-		case rotationorder.XYX:
-		case rotationorder.XYZ:
-		case rotationorder.XZX:
-		case rotationorder.XZY:
-		case rotationorder.YXY:
-		case rotationorder.YXZ:
-		case rotationorder.YZX:
-		case rotationorder.YZY:
-		case rotationorder.ZXY:
-		case rotationorder.ZXZ:
-		case rotationorder.ZYX:
-		case rotationorder.ZYZ:
-		default:
+		if !isValidRotationOrder(*postRequest.RotationOrder) {
+			errorMessage = "invalid rotation_order"
+		}
+		*postRequest.RotationOrder = strings.ToUpper(*postRequest.RotationOrder)
+	case primitivetype.KeyframedTranslation:
+		if postRequest.EncapsulatedPrimitiveName == nil ||
+			postRequest.Keyframes == nil {
+			errorMessage := "missing field from request"
+			errorStatusCode := http.StatusBadRequest
+
+			log.Error(errorMessage)
+			controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, nil)
+			return
+		}
+		if len(postRequest.Keyframes) == 0 {
+			errorMessage = "keyframes must not be empty"
+		}
+		for _, keyframe := range postRequest.Keyframes {
+			if keyframe.Moment == nil ||
+				keyframe.Displacement == nil ||
+				keyframe.Displacement.X == nil ||
+				keyframe.Displacement.Y == nil ||
+				keyframe.Displacement.Z == nil {
+				errorMessage = "each keyframe must have a moment and a displacement"
+				break
+			}
+			keyframeMoments = append(keyframeMoments, *keyframe.Moment)
+			keyframeValues = append(keyframeValues, *keyframe.Displacement.X, *keyframe.Displacement.Y, *keyframe.Displacement.Z)
+		}
+	case primitivetype.KeyframedRotation:
+		if postRequest.EncapsulatedPrimitiveName == nil ||
+			postRequest.Keyframes == nil ||
+			postRequest.RotationOrder == nil {
+			errorMessage := "missing field from request"
+			errorStatusCode := http.StatusBadRequest
+
+			log.Error(errorMessage)
+			controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, nil)
+			return
+		}
+		if len(postRequest.Keyframes) == 0 {
+			errorMessage = "keyframes must not be empty"
+		}
+		for _, keyframe := range postRequest.Keyframes {
+			if keyframe.Moment == nil || len(keyframe.AxisAngles) != 3 {
+				errorMessage = "each keyframe must have a moment and 3 axis angles"
+				break
+			}
+			keyframeMoments = append(keyframeMoments, *keyframe.Moment)
+			keyframeValues = append(keyframeValues, keyframe.AxisAngles...)
+		}
+		if !isValidRotationOrder(*postRequest.RotationOrder) {
 			errorMessage = "invalid rotation_order"
 		}
 		*postRequest.RotationOrder = strings.ToUpper(*postRequest.RotationOrder)
@@ -909,6 +1172,16 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 	} else {
 		displacement = []float64{*postRequest.Displacement.X, *postRequest.Displacement.Y, *postRequest.Displacement.Z}
 	}
+	var densityTypeField *string
+	if densityType != "" {
+		densityTypeField = &densityType
+	}
+	var aUV, bUV, cUV []float64
+	if postRequest.AUV != nil && postRequest.BUV != nil && postRequest.CUV != nil {
+		aUV = []float64{*postRequest.AUV.U, *postRequest.AUV.V}
+		bUV = []float64{*postRequest.BUV.U, *postRequest.BUV.V}
+		cUV = []float64{*postRequest.CUV.U, *postRequest.CUV.V}
+	}
 	primitive := &primitivepersistence.Primitive{
 		PrimitiveName:             *postRequest.PrimitiveName,
 		PrimitiveType:             strings.ToUpper(*postRequest.PrimitiveType),
@@ -935,6 +1208,18 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 		IsCulled:                  postRequest.IsCulled,
 		HasNegativeNormal:         postRequest.HasNegativeNormal,
 		HasInvertedNormals:        postRequest.HasInvertedNormals,
+		AUV:                       aUV,
+		BUV:                       bUV,
+		CUV:                       cUV,
+		DensityType:               densityTypeField,
+		NoiseFrequency:            noiseFrequency,
+		NoiseOctaves:              noiseOctaves,
+		NoiseCoverage:             noiseCoverage,
+		NoiseSeed:                 noiseSeed,
+		VoxelResolution:           postRequest.VoxelResolution,
+		VoxelData:                 voxelData,
+		KeyframeMoments:           keyframeMoments,
+		KeyframeValues:            keyframeValues,
 	}
 
 	// save to db
@@ -951,3 +1236,35 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 	response.WriteHeader(http.StatusCreated)
 	log.Debug("request completed")
 }
+
+// textureCoordinateFromArray converts texture coordinates stored as an array, returning nil if they aren't set
+func textureCoordinateFromArray(values []float64) *TextureCoordinate {
+	if values == nil {
+		return nil
+	}
+	return &TextureCoordinate{
+		U: values[0],
+		V: values[1],
+	}
+}
+
+// isValidRotationOrder returns whether order names an order in which to rotate about each axis
+func isValidRotationOrder(order string) bool {
+	switch rotationorder.RotationOrder(strings.ToUpper(order)) {
+	case rotationorder.XYX:
+	case rotationorder.XYZ:
+	case rotationorder.XZX:
+	case rotationorder.XZY:
+	case rotationorder.YXY:
+	case rotationorder.YXZ:
+	case rotationorder.YZX:
+	case rotationorder.YZY:
+	case rotationorder.ZXY:
+	case rotationorder.ZXZ:
+	case rotationorder.ZYX:
+	case rotationorder.ZYZ:
+	default:
+		return false
+	}
+	return true
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/paulwrubel/photolum/config"
+	"github.com/paulwrubel/photolum/constants"
 	"github.com/paulwrubel/photolum/controller"
 	"github.com/paulwrubel/photolum/enumeration/renderstatus"
 	"github.com/paulwrubel/photolum/persistence/parameterspersistence"
@@ -25,36 +26,59 @@ type GetRequest struct {
 }
 
 type GetIncompleteResponse struct {
-	RenderName             string `json:"render_name"`
-	ParametersName         string `json:"parameters_name"`
-	SceneName              string `json:"scene_name"`
-	RenderStatus           string `json:"render_status"`
-	CompletedRounds        string `json:"completed_rounds"`
-	RoundProgress          string `json:"round_progress"`
-	TotalProgress          string `json:"total_progress"`
-	StartTime              string `json:"start_time"`
-	ElapsedRuntime         string `json:"elapsed_runtime"`
-	EstimatedTimeRemaining string `json:"estimated_time_remaining"`
-	EstimatedEndTime       string `json:"estimated_end_time"`
+	RenderName             string  `json:"render_name"`
+	ParametersName         string  `json:"parameters_name"`
+	SceneName              string  `json:"scene_name"`
+	RenderStatus           string  `json:"render_status"`
+	CompletedRounds        string  `json:"completed_rounds"`
+	RoundProgress          string  `json:"round_progress"`
+	TotalProgress          string  `json:"total_progress"`
+	StartTime              string  `json:"start_time"`
+	ElapsedRuntime         string  `json:"elapsed_runtime"`
+	EstimatedTimeRemaining string  `json:"estimated_time_remaining"`
+	EstimatedEndTime       string  `json:"estimated_end_time"`
+	Region                 *Region `json:"region,omitempty"`
+	IsCropped              bool    `json:"is_cropped"`
+	BaseRenderName         *string `json:"base_render_name,omitempty"`
 }
 
 type GetCompleteResponse struct {
-	RenderName      string `json:"render_name"`
-	ParametersName  string `json:"parameters_name"`
-	SceneName       string `json:"scene_name"`
-	RenderStatus    string `json:"render_status"`
-	CompletedRounds string `json:"completed_rounds"`
-	RoundProgress   string `json:"round_progress"`
-	TotalProgress   string `json:"total_progress"`
-	StartTime       string `json:"start_time"`
-	EndTime         string `json:"end_time"`
-	TotalRuntime    string `json:"total_runtime"`
+	RenderName      string  `json:"render_name"`
+	ParametersName  string  `json:"parameters_name"`
+	SceneName       string  `json:"scene_name"`
+	RenderStatus    string  `json:"render_status"`
+	CompletedRounds string  `json:"completed_rounds"`
+	RoundProgress   string  `json:"round_progress"`
+	TotalProgress   string  `json:"total_progress"`
+	StartTime       string  `json:"start_time"`
+	EndTime         string  `json:"end_time"`
+	TotalRuntime    string  `json:"total_runtime"`
+	Region          *Region `json:"region,omitempty"`
+	IsCropped       bool    `json:"is_cropped"`
+	BaseRenderName  *string `json:"base_render_name,omitempty"`
+}
+
+type Region struct {
+	X      int32 `json:"x"`
+	Y      int32 `json:"y"`
+	Width  int32 `json:"width"`
+	Height int32 `json:"height"`
+}
+
+type RegionRequest struct {
+	X      *int32 `json:"x"`
+	Y      *int32 `json:"y"`
+	Width  *int32 `json:"width"`
+	Height *int32 `json:"height"`
 }
 
 type PostRequest struct {
-	RenderName     *string `json:"render_name"`
-	ParametersName *string `json:"parameters_name"`
-	SceneName      *string `json:"scene_name"`
+	RenderName     *string        `json:"render_name"`
+	ParametersName *string        `json:"parameters_name"`
+	SceneName      *string        `json:"scene_name"`
+	Region         *RegionRequest `json:"region"`
+	IsCropped      *bool          `json:"is_cropped"`
+	BaseRenderName *string        `json:"base_render_name"`
 }
 
 func GetHandler(response http.ResponseWriter, request *http.Request, plData *config.PhotolumData, baseLog *logrus.Logger) {
@@ -130,6 +154,16 @@ func GetHandler(response http.ResponseWriter, request *http.Request, plData *con
 		return
 	}
 
+	// regions are only present for renders which traced part of the image
+	var region *Region
+	if render.RegionOrigin != nil {
+		region = &Region{
+			X:      render.RegionOrigin[0],
+			Y:      render.RegionOrigin[1],
+			Width:  render.RegionSpan[0],
+			Height: render.RegionSpan[1],
+		}
+	}
 	roundPercentage := 1.0 / float64(parameters.RoundCount)
 	totalProgress := (float64(render.CompletedRounds) / float64(parameters.RoundCount)) + roundPercentage*(render.RoundProgress)
 	var getResponse interface{}
@@ -146,6 +180,9 @@ func GetHandler(response http.ResponseWriter, request *http.Request, plData *con
 			StartTime:       render.StartTimestamp.Local().Format("2006-01-02 15:04:05 MST"),
 			EndTime:         render.EndTimestamp.Local().Format("2006-01-02 15:04:05 MST"),
 			TotalRuntime:    totalRuntime.Round(time.Second).String(),
+			Region:          region,
+			IsCropped:       render.IsCropped,
+			BaseRenderName:  render.BaseRenderName,
 		}
 	} else {
 		elapsedRuntime := time.Since(render.StartTimestamp)
@@ -163,6 +200,9 @@ func GetHandler(response http.ResponseWriter, request *http.Request, plData *con
 			ElapsedRuntime:         elapsedRuntime.Round(time.Second).String(),
 			EstimatedTimeRemaining: estimatedTimeRemaining.Round(time.Second).String(),
 			EstimatedEndTime:       estimatedEndTime.Local().Format("2006-01-02 15:04:05 MST"),
+			Region:                 region,
+			IsCropped:              render.IsCropped,
+			BaseRenderName:         render.BaseRenderName,
 		}
 	}
 	response.Header().Add("Content-Type", "application/json")
@@ -197,7 +237,12 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 	// check for missing fields
 	if postRequest.RenderName == nil ||
 		postRequest.ParametersName == nil ||
-		postRequest.SceneName == nil {
+		postRequest.SceneName == nil ||
+		(postRequest.Region != nil &&
+			(postRequest.Region.X == nil ||
+				postRequest.Region.Y == nil ||
+				postRequest.Region.Width == nil ||
+				postRequest.Region.Height == nil)) {
 		errorMessage := "missing field from request"
 		errorStatusCode := http.StatusBadRequest
 
@@ -233,6 +278,83 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 	if !exists {
 		errorMessage = "named scene does not exist"
 	}
+	// the region is optional, and without it the whole image is traced
+	if postRequest.IsCropped == nil {
+		defaultIsCropped := constants.RenderDefaultIsCropped
+		postRequest.IsCropped = &defaultIsCropped
+	}
+	if postRequest.Region != nil {
+		if *postRequest.Region.Width <= 0 || *postRequest.Region.Height <= 0 {
+			errorMessage = "region width and height must be greater than zero"
+		}
+		if *postRequest.Region.X < 0 || *postRequest.Region.Y < 0 {
+			errorMessage = "region x and y cannot be negative"
+		}
+	} else if *postRequest.IsCropped {
+		errorMessage = "is_cropped is only allowed for renders with a region"
+	}
+	if postRequest.BaseRenderName != nil && *postRequest.IsCropped {
+		errorMessage = "base_render_name is not allowed for cropped renders"
+	}
+	// the region must lie within the image, and a base render must be the same size as it
+	if errorMessage == "" && (postRequest.Region != nil || postRequest.BaseRenderName != nil) {
+		parameters, err := parameterspersistence.Get(plData, log, *postRequest.ParametersName)
+		if err != nil {
+			errorMessage := "error getting parameters from database"
+			errorStatusCode := http.StatusInternalServerError
+
+			log.WithError(err).Error(errorMessage)
+			controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, err)
+			return
+		}
+		if postRequest.Region != nil &&
+			(int64(*postRequest.Region.X)+int64(*postRequest.Region.Width) > int64(parameters.ImageWidth) ||
+				int64(*postRequest.Region.Y)+int64(*postRequest.Region.Height) > int64(parameters.ImageHeight)) {
+			errorMessage = "region must lie within the image"
+		}
+		if postRequest.BaseRenderName != nil {
+			exists, err := renderpersistence.DoesExist(plData, log, *postRequest.BaseRenderName)
+			if err != nil {
+				errorMessage := "error checking base render existence in database"
+				errorStatusCode := http.StatusInternalServerError
+
+				log.WithError(err).Error(errorMessage)
+				controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, err)
+				return
+			}
+			if !exists {
+				errorMessage = "named base render does not exist"
+			} else {
+				baseRender, err := renderpersistence.Get(plData, log, *postRequest.BaseRenderName)
+				if err != nil {
+					errorMessage := "error getting base render from database"
+					errorStatusCode := http.StatusInternalServerError
+
+					log.WithError(err).Error(errorMessage)
+					controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, err)
+					return
+				}
+				baseParameters, err := parameterspersistence.Get(plData, log, baseRender.ParametersName)
+				if err != nil {
+					errorMessage := "error getting base render parameters from database"
+					errorStatusCode := http.StatusInternalServerError
+
+					log.WithError(err).Error(errorMessage)
+					controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, err)
+					return
+				}
+				if baseRender.ImageData == nil {
+					errorMessage = "base render has no image yet"
+				}
+				if baseRender.IsCropped {
+					errorMessage = "base render cannot be cropped"
+				}
+				if baseParameters.ImageWidth != parameters.ImageWidth || baseParameters.ImageHeight != parameters.ImageHeight {
+					errorMessage = "base render must have the same image size"
+				}
+			}
+		}
+	}
 
 	// send error
 	if errorMessage != "" {
@@ -271,6 +393,12 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 		CompletedRounds: 0,
 		RoundProgress:   0.0,
 		StartTimestamp:  time.Now(),
+		IsCropped:       *postRequest.IsCropped,
+		BaseRenderName:  postRequest.BaseRenderName,
+	}
+	if postRequest.Region != nil {
+		render.RegionOrigin = []int32{*postRequest.Region.X, *postRequest.Region.Y}
+		render.RegionSpan = []int32{*postRequest.Region.Width, *postRequest.Region.Height}
 	}
 
 	// save render to db
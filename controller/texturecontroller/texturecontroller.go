@@ -3,7 +3,7 @@ package texturecontroller
 import (
 	"encoding/base64"
 	"encoding/json"
-	"image"
+	"fmt"
 	_ "image/jpeg"
 	_ "image/png"
 	"io/ioutil"
@@ -12,9 +12,14 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/paulwrubel/photolum/config"
+	"github.com/paulwrubel/photolum/config/geometry"
 	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/config/shading/texture"
+	"github.com/paulwrubel/photolum/constants"
 	"github.com/paulwrubel/photolum/controller"
+	"github.com/paulwrubel/photolum/enumeration/texturefiltertype"
 	"github.com/paulwrubel/photolum/enumeration/texturetype"
+	"github.com/paulwrubel/photolum/enumeration/wrapmode"
 	"github.com/paulwrubel/photolum/persistence/texturepersistence"
 	"github.com/sirupsen/logrus"
 )
@@ -33,11 +38,126 @@ type ColorGetResponse struct {
 }
 
 type ImageGetResponse struct {
-	TextureName string  `json:"texture_name"`
-	TextureType string  `json:"texture_type"`
-	Gamma       float64 `json:"gamma"`
-	Magnitude   float64 `json:"magnitude"`
-	ImageData   string  `json:"image_data"`
+	TextureName string             `json:"texture_name"`
+	TextureType string             `json:"texture_type"`
+	Gamma       float64            `json:"gamma"`
+	Magnitude   float64            `json:"magnitude"`
+	ImageData   string             `json:"image_data"`
+	FilterType  string             `json:"filter_type"`
+	WrapMode    string             `json:"wrap_mode"`
+	UVScale     *TextureCoordinate `json:"uv_scale,omitempty"`
+	UVOffset    *TextureCoordinate `json:"uv_offset,omitempty"`
+	UVRotation  *float64           `json:"uv_rotation,omitempty"`
+}
+
+type CheckerGetResponse struct {
+	TextureName string             `json:"texture_name"`
+	TextureType string             `json:"texture_type"`
+	FirstColor  shading.Color      `json:"first_color"`
+	SecondColor shading.Color      `json:"second_color"`
+	Scale       float64            `json:"scale"`
+	IsSolid     bool               `json:"is_solid"`
+	UVScale     *TextureCoordinate `json:"uv_scale,omitempty"`
+	UVOffset    *TextureCoordinate `json:"uv_offset,omitempty"`
+	UVRotation  *float64           `json:"uv_rotation,omitempty"`
+}
+
+type GradientGetResponse struct {
+	TextureName   string             `json:"texture_name"`
+	TextureType   string             `json:"texture_type"`
+	FirstColor    shading.Color      `json:"first_color"`
+	SecondColor   shading.Color      `json:"second_color"`
+	GradientStart geometry.Vector    `json:"gradient_start"`
+	GradientEnd   geometry.Vector    `json:"gradient_end"`
+	IsSolid       bool               `json:"is_solid"`
+	UVScale       *TextureCoordinate `json:"uv_scale,omitempty"`
+	UVOffset      *TextureCoordinate `json:"uv_offset,omitempty"`
+	UVRotation    *float64           `json:"uv_rotation,omitempty"`
+}
+
+type NoiseGetResponse struct {
+	TextureName string        `json:"texture_name"`
+	TextureType string        `json:"texture_type"`
+	FirstColor  shading.Color `json:"first_color"`
+	SecondColor shading.Color `json:"second_color"`
+	Scale       float64       `json:"scale"`
+	Octaves     int32         `json:"octaves"`
+	Seed        int64         `json:"seed"`
+}
+
+type MarbleGetResponse struct {
+	TextureName string        `json:"texture_name"`
+	TextureType string        `json:"texture_type"`
+	FirstColor  shading.Color `json:"first_color"`
+	SecondColor shading.Color `json:"second_color"`
+	Scale       float64       `json:"scale"`
+	Octaves     int32         `json:"octaves"`
+	Seed        int64         `json:"seed"`
+	Turbulence  float64       `json:"turbulence"`
+}
+
+type WoodGetResponse struct {
+	TextureName string        `json:"texture_name"`
+	TextureType string        `json:"texture_type"`
+	FirstColor  shading.Color `json:"first_color"`
+	SecondColor shading.Color `json:"second_color"`
+	Scale       float64       `json:"scale"`
+	Octaves     int32         `json:"octaves"`
+	Seed        int64         `json:"seed"`
+	Turbulence  float64       `json:"turbulence"`
+}
+
+type MultiplyGetResponse struct {
+	TextureName       string `json:"texture_name"`
+	TextureType       string `json:"texture_type"`
+	FirstTextureName  string `json:"first_texture_name"`
+	SecondTextureName string `json:"second_texture_name"`
+}
+
+type AddGetResponse struct {
+	TextureName       string `json:"texture_name"`
+	TextureType       string `json:"texture_type"`
+	FirstTextureName  string `json:"first_texture_name"`
+	SecondTextureName string `json:"second_texture_name"`
+}
+
+type MixGetResponse struct {
+	TextureName       string `json:"texture_name"`
+	TextureType       string `json:"texture_type"`
+	FirstTextureName  string `json:"first_texture_name"`
+	SecondTextureName string `json:"second_texture_name"`
+	MaskTextureName   string `json:"mask_texture_name"`
+}
+
+type InvertGetResponse struct {
+	TextureName      string `json:"texture_name"`
+	TextureType      string `json:"texture_type"`
+	FirstTextureName string `json:"first_texture_name"`
+}
+
+type HSVAdjustGetResponse struct {
+	TextureName      string  `json:"texture_name"`
+	TextureType      string  `json:"texture_type"`
+	FirstTextureName string  `json:"first_texture_name"`
+	HueShift         float64 `json:"hue_shift"`
+	SaturationScale  float64 `json:"saturation_scale"`
+	ValueScale       float64 `json:"value_scale"`
+}
+
+type RemapGetResponse struct {
+	TextureName      string `json:"texture_name"`
+	TextureType      string `json:"texture_type"`
+	FirstTextureName string `json:"first_texture_name"`
+	InputRange       Range  `json:"input_range"`
+	OutputRange      Range  `json:"output_range"`
+	IsClamped        bool   `json:"is_clamped"`
+}
+
+type SwizzleGetResponse struct {
+	TextureName      string `json:"texture_name"`
+	TextureType      string `json:"texture_type"`
+	FirstTextureName string `json:"first_texture_name"`
+	Swizzle          string `json:"swizzle"`
 }
 
 type ColorRequest struct {
@@ -46,13 +166,63 @@ type ColorRequest struct {
 	Blue  *float64 `json:"blue"`
 }
 
+type TextureCoordinate struct {
+	U float64 `json:"u"`
+	V float64 `json:"v"`
+}
+
+type TextureCoordinateRequest struct {
+	U *float64 `json:"u"`
+	V *float64 `json:"v"`
+}
+
+type Range struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+type RangeRequest struct {
+	Min *float64 `json:"min"`
+	Max *float64 `json:"max"`
+}
+
+type VectorRequest struct {
+	X *float64 `json:"x"`
+	Y *float64 `json:"y"`
+	Z *float64 `json:"z"`
+}
+
 type PostRequest struct {
-	TextureName *string       `json:"texture_name"`
-	TextureType *string       `json:"texture_type"`
-	Color       *ColorRequest `json:"color"`
-	Gamma       *float64      `json:"gamma"`
-	Magnitude   *float64      `json:"magnitude"`
-	ImageData   *string       `json:"image_data"`
+	TextureName       *string                   `json:"texture_name"`
+	TextureType       *string                   `json:"texture_type"`
+	Color             *ColorRequest             `json:"color"`
+	Gamma             *float64                  `json:"gamma"`
+	Magnitude         *float64                  `json:"magnitude"`
+	ImageData         *string                   `json:"image_data"`
+	FirstColor        *ColorRequest             `json:"first_color"`
+	SecondColor       *ColorRequest             `json:"second_color"`
+	Scale             *float64                  `json:"scale"`
+	IsSolid           *bool                     `json:"is_solid"`
+	GradientStart     *VectorRequest            `json:"gradient_start"`
+	GradientEnd       *VectorRequest            `json:"gradient_end"`
+	Octaves           *int32                    `json:"octaves"`
+	Seed              *int64                    `json:"seed"`
+	Turbulence        *float64                  `json:"turbulence"`
+	FilterType        *string                   `json:"filter_type"`
+	WrapMode          *string                   `json:"wrap_mode"`
+	UVScale           *TextureCoordinateRequest `json:"uv_scale"`
+	UVOffset          *TextureCoordinateRequest `json:"uv_offset"`
+	UVRotation        *float64                  `json:"uv_rotation"`
+	FirstTextureName  *string                   `json:"first_texture_name"`
+	SecondTextureName *string                   `json:"second_texture_name"`
+	MaskTextureName   *string                   `json:"mask_texture_name"`
+	HueShift          *float64                  `json:"hue_shift"`
+	SaturationScale   *float64                  `json:"saturation_scale"`
+	ValueScale        *float64                  `json:"value_scale"`
+	InputRange        *RangeRequest             `json:"input_range"`
+	OutputRange       *RangeRequest             `json:"output_range"`
+	IsClamped         *bool                     `json:"is_clamped"`
+	Swizzle           *string                   `json:"swizzle"`
 }
 
 func GetHandler(response http.ResponseWriter, request *http.Request, plData *config.PhotolumData, baseLog *logrus.Logger) {
@@ -136,6 +306,121 @@ func GetHandler(response http.ResponseWriter, request *http.Request, plData *con
 			Gamma:       *texture.Gamma,
 			Magnitude:   *texture.Magnitude,
 			ImageData:   base64.StdEncoding.EncodeToString(texture.ImageData),
+			FilterType:  *texture.FilterType,
+			WrapMode:    *texture.WrapMode,
+			UVScale:     textureCoordinateFromArray(texture.UVScale),
+			UVOffset:    textureCoordinateFromArray(texture.UVOffset),
+			UVRotation:  texture.UVRotation,
+		}
+	case texturetype.Checker:
+		getResponse = CheckerGetResponse{
+			TextureName: texture.TextureName,
+			TextureType: texture.TextureType,
+			FirstColor:  colorFromArray(texture.FirstColor),
+			SecondColor: colorFromArray(texture.SecondColor),
+			Scale:       *texture.Scale,
+			IsSolid:     *texture.IsSolid,
+			UVScale:     textureCoordinateFromArray(texture.UVScale),
+			UVOffset:    textureCoordinateFromArray(texture.UVOffset),
+			UVRotation:  texture.UVRotation,
+		}
+	case texturetype.LinearGradient, texturetype.RadialGradient:
+		getResponse = GradientGetResponse{
+			TextureName:   texture.TextureName,
+			TextureType:   texture.TextureType,
+			FirstColor:    colorFromArray(texture.FirstColor),
+			SecondColor:   colorFromArray(texture.SecondColor),
+			GradientStart: vectorFromArray(texture.GradientStart),
+			GradientEnd:   vectorFromArray(texture.GradientEnd),
+			IsSolid:       *texture.IsSolid,
+			UVScale:       textureCoordinateFromArray(texture.UVScale),
+			UVOffset:      textureCoordinateFromArray(texture.UVOffset),
+			UVRotation:    texture.UVRotation,
+		}
+	case texturetype.Noise:
+		getResponse = NoiseGetResponse{
+			TextureName: texture.TextureName,
+			TextureType: texture.TextureType,
+			FirstColor:  colorFromArray(texture.FirstColor),
+			SecondColor: colorFromArray(texture.SecondColor),
+			Scale:       *texture.Scale,
+			Octaves:     *texture.Octaves,
+			Seed:        *texture.Seed,
+		}
+	case texturetype.Marble:
+		getResponse = MarbleGetResponse{
+			TextureName: texture.TextureName,
+			TextureType: texture.TextureType,
+			FirstColor:  colorFromArray(texture.FirstColor),
+			SecondColor: colorFromArray(texture.SecondColor),
+			Scale:       *texture.Scale,
+			Octaves:     *texture.Octaves,
+			Seed:        *texture.Seed,
+			Turbulence:  *texture.Turbulence,
+		}
+	case texturetype.Wood:
+		getResponse = WoodGetResponse{
+			TextureName: texture.TextureName,
+			TextureType: texture.TextureType,
+			FirstColor:  colorFromArray(texture.FirstColor),
+			SecondColor: colorFromArray(texture.SecondColor),
+			Scale:       *texture.Scale,
+			Octaves:     *texture.Octaves,
+			Seed:        *texture.Seed,
+			Turbulence:  *texture.Turbulence,
+		}
+	case texturetype.Multiply:
+		getResponse = MultiplyGetResponse{
+			TextureName:       texture.TextureName,
+			TextureType:       texture.TextureType,
+			FirstTextureName:  *texture.FirstTextureName,
+			SecondTextureName: *texture.SecondTextureName,
+		}
+	case texturetype.Add:
+		getResponse = AddGetResponse{
+			TextureName:       texture.TextureName,
+			TextureType:       texture.TextureType,
+			FirstTextureName:  *texture.FirstTextureName,
+			SecondTextureName: *texture.SecondTextureName,
+		}
+	case texturetype.Mix:
+		getResponse = MixGetResponse{
+			TextureName:       texture.TextureName,
+			TextureType:       texture.TextureType,
+			FirstTextureName:  *texture.FirstTextureName,
+			SecondTextureName: *texture.SecondTextureName,
+			MaskTextureName:   *texture.MaskTextureName,
+		}
+	case texturetype.Invert:
+		getResponse = InvertGetResponse{
+			TextureName:      texture.TextureName,
+			TextureType:      texture.TextureType,
+			FirstTextureName: *texture.FirstTextureName,
+		}
+	case texturetype.HSVAdjust:
+		getResponse = HSVAdjustGetResponse{
+			TextureName:      texture.TextureName,
+			TextureType:      texture.TextureType,
+			FirstTextureName: *texture.FirstTextureName,
+			HueShift:         *texture.HueShift,
+			SaturationScale:  *texture.SaturationScale,
+			ValueScale:       *texture.ValueScale,
+		}
+	case texturetype.Remap:
+		getResponse = RemapGetResponse{
+			TextureName:      texture.TextureName,
+			TextureType:      texture.TextureType,
+			FirstTextureName: *texture.FirstTextureName,
+			InputRange:       rangeFromArray(texture.InputRange),
+			OutputRange:      rangeFromArray(texture.OutputRange),
+			IsClamped:        *texture.IsClamped,
+		}
+	case texturetype.Swizzle:
+		getResponse = SwizzleGetResponse{
+			TextureName:      texture.TextureName,
+			TextureType:      texture.TextureType,
+			FirstTextureName: *texture.FirstTextureName,
+			Swizzle:          *texture.Swizzle,
 		}
 	}
 
@@ -160,6 +445,9 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 
 	// decode request
 	var postRequest *PostRequest
+	var firstColor, secondColor []float64
+	var gradientStart, gradientEnd []float64
+	var inputRange, outputRange []float64
 	errorMessage := ""
 
 	contentType := request.Header.Get("Content-Type")
@@ -215,7 +503,10 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 			} else {
 				imageDataFile, _, err := request.FormFile("image_data")
 				defer imageDataFile.Close()
-				_, _, err = image.Decode(imageDataFile)
+				imageData, err := ioutil.ReadAll(imageDataFile)
+				if err == nil {
+					err = texture.CheckImageData(imageData)
+				}
 				if err != nil {
 					errMessage := "could not decode image_data file"
 					errorStatusCode := http.StatusInternalServerError
@@ -287,8 +578,10 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 			} else if *postRequest.Magnitude < 0 {
 				errorMessage = "magnitude must be greater than or equal to zero"
 			} else {
-				imageDataReader := base64.NewDecoder(base64.StdEncoding, strings.NewReader(*postRequest.ImageData))
-				_, _, err = image.Decode(imageDataReader)
+				imageData, err := base64.StdEncoding.DecodeString(*postRequest.ImageData)
+				if err == nil {
+					err = texture.CheckImageData(imageData)
+				}
 				if err != nil {
 					errMessage := "could not decode image_data"
 					errorStatusCode := http.StatusInternalServerError
@@ -298,9 +591,274 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 					return
 				}
 			}
+		case texturetype.Checker:
+			if postRequest.FirstColor == nil ||
+				postRequest.SecondColor == nil {
+				errorMessage := "missing field from request"
+				errorStatusCode := http.StatusBadRequest
+
+				log.Error(errorMessage)
+				controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, nil)
+				return
+			}
+			if postRequest.Scale == nil {
+				defaultScale := constants.TextureDefaultScale
+				postRequest.Scale = &defaultScale
+			}
+			if postRequest.IsSolid == nil {
+				defaultIsSolid := constants.TextureDefaultIsSolid
+				postRequest.IsSolid = &defaultIsSolid
+			}
+			if *postRequest.Scale <= 0.0 {
+				errorMessage = "scale must be greater than zero"
+			}
+		case texturetype.LinearGradient, texturetype.RadialGradient:
+			if postRequest.FirstColor == nil ||
+				postRequest.SecondColor == nil ||
+				postRequest.GradientStart == nil ||
+				postRequest.GradientEnd == nil {
+				errorMessage := "missing field from request"
+				errorStatusCode := http.StatusBadRequest
+
+				log.Error(errorMessage)
+				controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, nil)
+				return
+			}
+			if postRequest.IsSolid == nil {
+				defaultIsSolid := constants.TextureDefaultIsSolid
+				postRequest.IsSolid = &defaultIsSolid
+			}
+			gradientStart, gradientEnd = arrayFromVectorRequest(postRequest.GradientStart), arrayFromVectorRequest(postRequest.GradientEnd)
+			if gradientStart == nil || gradientEnd == nil {
+				errorMessage = "gradient_start and gradient_end must have x, y, and z fields"
+			} else if gradientStart[0] == gradientEnd[0] && gradientStart[1] == gradientEnd[1] && gradientStart[2] == gradientEnd[2] {
+				errorMessage = "gradient_start and gradient_end must be different"
+			}
+		case texturetype.Noise, texturetype.Marble, texturetype.Wood:
+			if postRequest.FirstColor == nil ||
+				postRequest.SecondColor == nil {
+				errorMessage := "missing field from request"
+				errorStatusCode := http.StatusBadRequest
+
+				log.Error(errorMessage)
+				controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, nil)
+				return
+			}
+			if postRequest.Scale == nil {
+				defaultScale := constants.TextureDefaultScale
+				postRequest.Scale = &defaultScale
+			}
+			if postRequest.Octaves == nil {
+				defaultOctaves := constants.TextureDefaultOctaves
+				postRequest.Octaves = &defaultOctaves
+			}
+			if postRequest.Seed == nil {
+				defaultSeed := constants.TextureDefaultSeed
+				postRequest.Seed = &defaultSeed
+			}
+			// only marble and wood are warped by turbulence
+			isTurbulent := texturetype.TextureType(strings.ToUpper(*postRequest.TextureType)) != texturetype.Noise
+			if isTurbulent && postRequest.Turbulence == nil {
+				defaultTurbulence := constants.TextureDefaultTurbulence
+				postRequest.Turbulence = &defaultTurbulence
+			}
+			if *postRequest.Scale <= 0.0 {
+				errorMessage = "scale must be greater than zero"
+			} else if *postRequest.Octaves < 1 || *postRequest.Octaves > constants.TextureMaximumOctaves {
+				errorMessage = fmt.Sprintf("octaves must be between 1 and %d", constants.TextureMaximumOctaves)
+			} else if !isTurbulent && postRequest.Turbulence != nil {
+				errorMessage = "turbulence is only valid for marble and wood textures"
+			} else if isTurbulent && *postRequest.Turbulence < 0.0 {
+				errorMessage = "turbulence must be greater than or equal to zero"
+			}
+		case texturetype.Multiply, texturetype.Add, texturetype.Mix:
+			isMix := texturetype.TextureType(strings.ToUpper(*postRequest.TextureType)) == texturetype.Mix
+			if postRequest.FirstTextureName == nil ||
+				postRequest.SecondTextureName == nil ||
+				(isMix && postRequest.MaskTextureName == nil) {
+				errorMessage := "missing field from request"
+				errorStatusCode := http.StatusBadRequest
+
+				log.Error(errorMessage)
+				controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, nil)
+				return
+			}
+		case texturetype.Invert:
+			if postRequest.FirstTextureName == nil {
+				errorMessage := "missing field from request"
+				errorStatusCode := http.StatusBadRequest
+
+				log.Error(errorMessage)
+				controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, nil)
+				return
+			}
+		case texturetype.HSVAdjust:
+			if postRequest.FirstTextureName == nil {
+				errorMessage := "missing field from request"
+				errorStatusCode := http.StatusBadRequest
+
+				log.Error(errorMessage)
+				controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, nil)
+				return
+			}
+			if postRequest.HueShift == nil {
+				defaultHueShift := constants.TextureDefaultHueShift
+				postRequest.HueShift = &defaultHueShift
+			}
+			if postRequest.SaturationScale == nil {
+				defaultSaturationScale := constants.TextureDefaultSaturationScale
+				postRequest.SaturationScale = &defaultSaturationScale
+			}
+			if postRequest.ValueScale == nil {
+				defaultValueScale := constants.TextureDefaultValueScale
+				postRequest.ValueScale = &defaultValueScale
+			}
+			if *postRequest.SaturationScale < 0.0 {
+				errorMessage = "saturation_scale must be greater than or equal to zero"
+			} else if *postRequest.ValueScale < 0.0 {
+				errorMessage = "value_scale must be greater than or equal to zero"
+			}
+		case texturetype.Remap:
+			if postRequest.FirstTextureName == nil ||
+				postRequest.InputRange == nil ||
+				postRequest.OutputRange == nil {
+				errorMessage := "missing field from request"
+				errorStatusCode := http.StatusBadRequest
+
+				log.Error(errorMessage)
+				controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, nil)
+				return
+			}
+			if postRequest.IsClamped == nil {
+				defaultIsClamped := constants.TextureDefaultIsClamped
+				postRequest.IsClamped = &defaultIsClamped
+			}
+			inputRange, outputRange = arrayFromRangeRequest(postRequest.InputRange), arrayFromRangeRequest(postRequest.OutputRange)
+			if inputRange == nil || outputRange == nil {
+				errorMessage = "input_range and output_range must have min and max fields"
+			} else if inputRange[0] == inputRange[1] {
+				errorMessage = "input_range min and max must be different"
+			}
+		case texturetype.Swizzle:
+			if postRequest.FirstTextureName == nil ||
+				postRequest.Swizzle == nil {
+				errorMessage := "missing field from request"
+				errorStatusCode := http.StatusBadRequest
+
+				log.Error(errorMessage)
+				controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, nil)
+				return
+			}
+			swizzle := strings.ToUpper(*postRequest.Swizzle)
+			postRequest.Swizzle = &swizzle
+			_, err := (&texture.Swizzle{Channels: swizzle}).Setup()
+			if err != nil {
+				errorMessage = "swizzle must be three of R, G, and B"
+			}
 		default:
 			errorMessage = "invalid texture_type"
 		}
+		// procedural textures blend between two colors
+		if postRequest.FirstColor != nil || postRequest.SecondColor != nil {
+			switch texturetype.TextureType(strings.ToUpper(*postRequest.TextureType)) {
+			case texturetype.Checker, texturetype.LinearGradient, texturetype.RadialGradient,
+				texturetype.Noise, texturetype.Marble, texturetype.Wood:
+				firstColor, secondColor = arrayFromColorRequest(postRequest.FirstColor), arrayFromColorRequest(postRequest.SecondColor)
+				if firstColor == nil || secondColor == nil {
+					errorMessage = "first_color and second_color must have red, green, and blue fields"
+				} else if firstColor[0] < 0.0 || firstColor[1] < 0.0 || firstColor[2] < 0.0 ||
+					secondColor[0] < 0.0 || secondColor[1] < 0.0 || secondColor[2] < 0.0 {
+					errorMessage = "first_color and second_color fields must be greater than or equal to zero"
+				}
+			default:
+				errorMessage = "first_color and second_color are only valid for procedural textures"
+			}
+		}
+		// texture graph nodes take their inputs from textures which already exist, so they can't form a cycle
+		var inputCount int
+		switch texturetype.TextureType(strings.ToUpper(*postRequest.TextureType)) {
+		case texturetype.Invert, texturetype.HSVAdjust, texturetype.Remap, texturetype.Swizzle:
+			inputCount = 1
+		case texturetype.Multiply, texturetype.Add:
+			inputCount = 2
+		case texturetype.Mix:
+			inputCount = 3
+		}
+		inputFields := []string{"first_texture_name", "second_texture_name", "mask_texture_name"}
+		for i, textureName := range []*string{postRequest.FirstTextureName, postRequest.SecondTextureName, postRequest.MaskTextureName} {
+			if textureName == nil {
+				continue
+			}
+			if i >= inputCount {
+				errorMessage = fmt.Sprintf("%s is not valid for %s textures", inputFields[i], strings.ToLower(*postRequest.TextureType))
+				continue
+			}
+			exists, err := texturepersistence.DoesExist(plData, log, *textureName)
+			if err != nil {
+				errorMessage := "error checking texture existence in database"
+				errorStatusCode := http.StatusInternalServerError
+
+				log.WithError(err).Error(errorMessage)
+				controller.WriteErrorResponse(&response, errorStatusCode, errorMessage, err)
+				return
+			}
+			if !exists {
+				errorMessage = fmt.Sprintf("named texture %s does not exist", *textureName)
+			}
+		}
+	}
+
+	// image textures are filtered and wrapped, bilinearly and repeating unless told otherwise
+	if texturetype.TextureType(strings.ToUpper(*postRequest.TextureType)) == texturetype.Image {
+		if postRequest.FilterType == nil {
+			defaultFilterType := constants.TextureDefaultFilterType
+			postRequest.FilterType = &defaultFilterType
+		}
+		if postRequest.WrapMode == nil {
+			defaultWrapMode := constants.TextureDefaultWrapMode
+			postRequest.WrapMode = &defaultWrapMode
+		}
+		filterType := strings.ToUpper(*postRequest.FilterType)
+		wrapMode := strings.ToUpper(*postRequest.WrapMode)
+		postRequest.FilterType, postRequest.WrapMode = &filterType, &wrapMode
+		switch {
+		case texturefiltertype.TextureFilterType(filterType) != texturefiltertype.Nearest &&
+			texturefiltertype.TextureFilterType(filterType) != texturefiltertype.Bilinear:
+			errorMessage = "invalid filter_type"
+		case wrapmode.WrapMode(wrapMode) != wrapmode.Repeat && wrapmode.WrapMode(wrapMode) != wrapmode.Clamp &&
+			wrapmode.WrapMode(wrapMode) != wrapmode.Mirror:
+			errorMessage = "invalid wrap_mode"
+		}
+	} else if postRequest.FilterType != nil || postRequest.WrapMode != nil {
+		errorMessage = "filter_type and wrap_mode are only valid for image textures"
+	}
+
+	// textures looked up by texture coordinates may have them scaled, rotated, and offset
+	var uvScale, uvOffset []float64
+	if postRequest.UVScale != nil || postRequest.UVOffset != nil || postRequest.UVRotation != nil {
+		switch texturetype.TextureType(strings.ToUpper(*postRequest.TextureType)) {
+		case texturetype.Image, texturetype.Checker, texturetype.LinearGradient, texturetype.RadialGradient:
+			if postRequest.UVScale == nil {
+				defaultUScale, defaultVScale := constants.TextureDefaultUVScale, constants.TextureDefaultUVScale
+				postRequest.UVScale = &TextureCoordinateRequest{U: &defaultUScale, V: &defaultVScale}
+			}
+			if postRequest.UVOffset == nil {
+				defaultUOffset, defaultVOffset := constants.TextureDefaultUVOffset, constants.TextureDefaultUVOffset
+				postRequest.UVOffset = &TextureCoordinateRequest{U: &defaultUOffset, V: &defaultVOffset}
+			}
+			if postRequest.UVRotation == nil {
+				defaultUVRotation := constants.TextureDefaultUVRotation
+				postRequest.UVRotation = &defaultUVRotation
+			}
+			uvScale, uvOffset = arrayFromTextureCoordinateRequest(postRequest.UVScale), arrayFromTextureCoordinateRequest(postRequest.UVOffset)
+			if uvScale == nil || uvOffset == nil {
+				errorMessage = "uv_scale and uv_offset must have u and v fields"
+			} else if uvScale[0] == 0.0 || uvScale[1] == 0.0 {
+				errorMessage = "uv_scale fields must be nonzero"
+			}
+		default:
+			errorMessage = "uv_scale, uv_offset, and uv_rotation are only valid for textures looked up by texture coordinates"
+		}
 	}
 
 	// send error
@@ -349,12 +907,36 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 		imageData, _ = base64.StdEncoding.DecodeString(*postRequest.ImageData)
 	}
 	texture := &texturepersistence.Texture{
-		TextureName: *postRequest.TextureName,
-		TextureType: strings.ToUpper(*postRequest.TextureType),
-		Color:       textureColor,
-		Gamma:       postRequest.Gamma,
-		Magnitude:   postRequest.Magnitude,
-		ImageData:   imageData,
+		TextureName:       *postRequest.TextureName,
+		TextureType:       strings.ToUpper(*postRequest.TextureType),
+		Color:             textureColor,
+		Gamma:             postRequest.Gamma,
+		Magnitude:         postRequest.Magnitude,
+		ImageData:         imageData,
+		FirstColor:        firstColor,
+		SecondColor:       secondColor,
+		Scale:             postRequest.Scale,
+		IsSolid:           postRequest.IsSolid,
+		GradientStart:     gradientStart,
+		GradientEnd:       gradientEnd,
+		Octaves:           postRequest.Octaves,
+		Seed:              postRequest.Seed,
+		Turbulence:        postRequest.Turbulence,
+		FilterType:        postRequest.FilterType,
+		WrapMode:          postRequest.WrapMode,
+		UVScale:           uvScale,
+		UVOffset:          uvOffset,
+		UVRotation:        postRequest.UVRotation,
+		FirstTextureName:  postRequest.FirstTextureName,
+		SecondTextureName: postRequest.SecondTextureName,
+		MaskTextureName:   postRequest.MaskTextureName,
+		HueShift:          postRequest.HueShift,
+		SaturationScale:   postRequest.SaturationScale,
+		ValueScale:        postRequest.ValueScale,
+		InputRange:        inputRange,
+		OutputRange:       outputRange,
+		IsClamped:         postRequest.IsClamped,
+		Swizzle:           postRequest.Swizzle,
 	}
 
 	// save to db
@@ -371,3 +953,73 @@ func PostHandler(response http.ResponseWriter, request *http.Request, plData *co
 	response.WriteHeader(http.StatusCreated)
 	log.Debug("request completed")
 }
+
+// colorFromArray converts a color stored as an array of its channels
+func colorFromArray(values []float64) shading.Color {
+	return shading.Color{
+		Red:   values[0],
+		Green: values[1],
+		Blue:  values[2],
+	}
+}
+
+// vectorFromArray converts a vector stored as an array of its components
+func vectorFromArray(values []float64) geometry.Vector {
+	return geometry.Vector{
+		X: values[0],
+		Y: values[1],
+		Z: values[2],
+	}
+}
+
+// arrayFromColorRequest converts a color from a request to an array, returning nil if any channel is missing
+func arrayFromColorRequest(color *ColorRequest) []float64 {
+	if color == nil || color.Red == nil || color.Green == nil || color.Blue == nil {
+		return nil
+	}
+	return []float64{*color.Red, *color.Green, *color.Blue}
+}
+
+// arrayFromVectorRequest converts a vector from a request to an array, returning nil if any component is missing
+func arrayFromVectorRequest(vector *VectorRequest) []float64 {
+	if vector.X == nil || vector.Y == nil || vector.Z == nil {
+		return nil
+	}
+	return []float64{*vector.X, *vector.Y, *vector.Z}
+}
+
+// textureCoordinateFromArray converts texture coordinates stored as an array, returning nil if they aren't set
+func textureCoordinateFromArray(values []float64) *TextureCoordinate {
+	if values == nil {
+		return nil
+	}
+	return &TextureCoordinate{
+		U: values[0],
+		V: values[1],
+	}
+}
+
+// arrayFromTextureCoordinateRequest converts texture coordinates from a request to an array,
+// returning nil if either is missing
+func arrayFromTextureCoordinateRequest(textureCoordinate *TextureCoordinateRequest) []float64 {
+	if textureCoordinate.U == nil || textureCoordinate.V == nil {
+		return nil
+	}
+	return []float64{*textureCoordinate.U, *textureCoordinate.V}
+}
+
+// rangeFromArray converts a range stored as an array of its minimum and maximum
+func rangeFromArray(values []float64) Range {
+	return Range{
+		Min: values[0],
+		Max: values[1],
+	}
+}
+
+// arrayFromRangeRequest converts a range from a request to an array, returning nil if either bound is missing
+func arrayFromRangeRequest(r *RangeRequest) []float64 {
+	if r.Min == nil || r.Max == nil {
+		return nil
+	}
+	return []float64{*r.Min, *r.Max}
+}
@@ -3,6 +3,95 @@ CREATE TYPE FILE_TYPE AS ENUM (
     'JPEG'
 );
 
+CREATE TYPE RECONSTRUCTION_FILTER AS ENUM (
+    'BOX',
+    'TENT',
+    'GAUSSIAN',
+    'MITCHELL',
+    'LANCZOS'
+);
+
+CREATE TYPE BACKGROUND_TYPE AS ENUM (
+    'COLOR',
+    'ENVIRONMENT_MAP',
+    'SKY'
+);
+
+CREATE TYPE TEXTURE_TYPE AS ENUM (
+    'COLOR',
+    'IMAGE',
+    'CHECKER',
+    'LINEAR_GRADIENT',
+    'RADIAL_GRADIENT',
+    'NOISE',
+    'MARBLE',
+    'WOOD',
+    'MULTIPLY',
+    'ADD',
+    'MIX',
+    'INVERT',
+    'HSV_ADJUST',
+    'REMAP',
+    'SWIZZLE'
+);
+
+CREATE TYPE TEXTURE_FILTER_TYPE AS ENUM (
+    'NEAREST',
+    'BILINEAR'
+);
+
+CREATE TYPE WRAP_MODE AS ENUM (
+    'REPEAT',
+    'CLAMP',
+    'MIRROR'
+);
+
+CREATE TABLE textures (
+    texture_name TEXT PRIMARY KEY,
+    texture_type TEXTURE_TYPE NOT NULL,
+    color DOUBLE PRECISION[3],
+    gamma DOUBLE PRECISION,
+    magnitude DOUBLE PRECISION,
+    image_data BYTEA,
+    first_color DOUBLE PRECISION[3],
+    second_color DOUBLE PRECISION[3],
+    scale DOUBLE PRECISION,
+    is_solid BOOLEAN,
+    gradient_start DOUBLE PRECISION[3],
+    gradient_end DOUBLE PRECISION[3],
+    octaves INTEGER,
+    seed BIGINT,
+    turbulence DOUBLE PRECISION,
+    filter_type TEXTURE_FILTER_TYPE,
+    wrap_mode WRAP_MODE,
+    uv_scale DOUBLE PRECISION[2],
+    uv_offset DOUBLE PRECISION[2],
+    uv_rotation DOUBLE PRECISION,
+    first_texture_name TEXT REFERENCES textures(texture_name),
+    second_texture_name TEXT REFERENCES textures(texture_name),
+    mask_texture_name TEXT REFERENCES textures(texture_name),
+    hue_shift DOUBLE PRECISION,
+    saturation_scale DOUBLE PRECISION,
+    value_scale DOUBLE PRECISION,
+    input_range DOUBLE PRECISION[2],
+    output_range DOUBLE PRECISION[2],
+    is_clamped BOOLEAN,
+    swizzle TEXT,
+    CHECK (texture_type NOT IN ('CHECKER', 'LINEAR_GRADIENT', 'RADIAL_GRADIENT', 'NOISE', 'MARBLE', 'WOOD') OR num_nonnulls(first_color, second_color) = 2),
+    CHECK (texture_type <> 'CHECKER' OR num_nonnulls(scale, is_solid) = 2),
+    CHECK (texture_type NOT IN ('LINEAR_GRADIENT', 'RADIAL_GRADIENT') OR num_nonnulls(gradient_start, gradient_end, is_solid) = 3),
+    CHECK (texture_type <> 'NOISE' OR num_nonnulls(scale, octaves, seed) = 3),
+    CHECK (texture_type NOT IN ('MARBLE', 'WOOD') OR num_nonnulls(scale, octaves, seed, turbulence) = 4),
+    CHECK (texture_type <> 'IMAGE' OR num_nonnulls(filter_type, wrap_mode) = 2),
+    CHECK (num_nulls(uv_scale, uv_offset, uv_rotation) IN (0, 3)),
+    CHECK (texture_type NOT IN ('MULTIPLY', 'ADD') OR num_nonnulls(first_texture_name, second_texture_name) = 2),
+    CHECK (texture_type <> 'MIX' OR num_nonnulls(first_texture_name, second_texture_name, mask_texture_name) = 3),
+    CHECK (texture_type NOT IN ('INVERT', 'HSV_ADJUST', 'REMAP', 'SWIZZLE') OR first_texture_name IS NOT NULL),
+    CHECK (texture_type <> 'HSV_ADJUST' OR num_nonnulls(hue_shift, saturation_scale, value_scale) = 3),
+    CHECK (texture_type <> 'REMAP' OR num_nonnulls(input_range, output_range, is_clamped) = 3),
+    CHECK (texture_type <> 'SWIZZLE' OR swizzle ~ '^[RGB]{3}$')
+);
+
 CREATE TABLE parameters (
     parameters_name TEXT PRIMARY KEY,
     image_width INTEGER NOT NULL,
@@ -19,7 +108,44 @@ CREATE TABLE parameters (
     background_color_magnitude DOUBLE PRECISION NOT NULL,
     background_color DOUBLE PRECISION[3] NOT NULL,
     t_min DOUBLE PRECISION NOT NULL,
-    t_max DOUBLE PRECISION NOT NULL
+    t_max DOUBLE PRECISION NOT NULL,
+    reconstruction_filter RECONSTRUCTION_FILTER NOT NULL,
+    filter_radius DOUBLE PRECISION NOT NULL,
+    use_adaptive_sampling BOOLEAN NOT NULL,
+    adaptive_threshold DOUBLE PRECISION NOT NULL,
+    adaptive_minimum_samples INTEGER NOT NULL,
+    noise_target DOUBLE PRECISION,
+    seed BIGINT,
+    background_type BACKGROUND_TYPE NOT NULL,
+    environment_texture_name TEXT REFERENCES textures(texture_name),
+    environment_rotation DOUBLE PRECISION NOT NULL,
+    environment_intensity DOUBLE PRECISION NOT NULL,
+    sun_direction DOUBLE PRECISION[3],
+    turbidity DOUBLE PRECISION NOT NULL,
+    ground_albedo DOUBLE PRECISION[3] NOT NULL,
+    sky_intensity DOUBLE PRECISION NOT NULL,
+    sun_intensity DOUBLE PRECISION NOT NULL,
+    sun_angular_diameter DOUBLE PRECISION NOT NULL,
+    fog_density DOUBLE PRECISION,
+    fog_albedo DOUBLE PRECISION[3],
+    fog_anisotropy DOUBLE PRECISION,
+    CHECK (background_type <> 'ENVIRONMENT_MAP' OR environment_texture_name IS NOT NULL),
+    CHECK (background_type <> 'SKY' OR sun_direction IS NOT NULL),
+    CHECK (fog_density IS NULL OR num_nonnulls(fog_albedo, fog_anisotropy) = 2)
+);
+
+CREATE TYPE CAMERA_TYPE AS ENUM (
+    'PERSPECTIVE',
+    'ORTHOGRAPHIC',
+    'EQUIRECTANGULAR',
+    'FISHEYE_EQUIDISTANT',
+    'FISHEYE_EQUISOLID',
+    'CYLINDRICAL'
+);
+
+CREATE TYPE STEREO_LAYOUT AS ENUM (
+    'SIDE_BY_SIDE',
+    'TOP_BOTTOM'
 );
 
 CREATE TABLE cameras (
@@ -27,9 +153,34 @@ CREATE TABLE cameras (
     eye_location DOUBLE PRECISION[3] NOT NULL,
     target_location DOUBLE PRECISION[3] NOT NULL,
     up_vector DOUBLE PRECISION[3] NOT NULL,
-    vertical_fov DOUBLE PRECISION NOT NULL,
-    aperture DOUBLE PRECISION NOT NULL,
-    focus_distance DOUBLE PRECISION NOT NULL
+    vertical_fov DOUBLE PRECISION,
+    aperture DOUBLE PRECISION,
+    focus_distance DOUBLE PRECISION NOT NULL,
+    camera_type CAMERA_TYPE NOT NULL,
+    orthographic_height DOUBLE PRECISION,
+    is_physical BOOLEAN NOT NULL,
+    focal_length DOUBLE PRECISION,
+    sensor_width DOUBLE PRECISION,
+    f_number DOUBLE PRECISION,
+    shutter_speed DOUBLE PRECISION,
+    iso DOUBLE PRECISION,
+    aperture_blades INTEGER NOT NULL,
+    aperture_rotation DOUBLE PRECISION NOT NULL,
+    aperture_mask_texture_name TEXT REFERENCES textures(texture_name),
+    cats_eye DOUBLE PRECISION NOT NULL,
+    tilt DOUBLE PRECISION[2] NOT NULL,
+    shift DOUBLE PRECISION[2] NOT NULL,
+    shutter_open DOUBLE PRECISION NOT NULL,
+    shutter_close DOUBLE PRECISION NOT NULL,
+    stereo_layout STEREO_LAYOUT,
+    interocular_distance DOUBLE PRECISION,
+    convergence_distance DOUBLE PRECISION,
+    CHECK (camera_type IN ('ORTHOGRAPHIC', 'EQUIRECTANGULAR') OR is_physical OR vertical_fov IS NOT NULL),
+    CHECK (camera_type <> 'ORTHOGRAPHIC' OR orthographic_height IS NOT NULL),
+    CHECK (is_physical OR aperture IS NOT NULL),
+    CHECK (NOT is_physical OR num_nonnulls(focal_length, sensor_width, f_number, shutter_speed, iso) = 5),
+    CHECK (shutter_close >= shutter_open),
+    CHECK (stereo_layout IS NULL OR interocular_distance IS NOT NULL)
 );
 
 CREATE TABLE scenes (
@@ -49,6 +200,8 @@ CREATE TYPE PRIMITIVE_TYPE AS ENUM (
     'TRANSLATION',
     'ROTATION',
     'QUATERNION',
+    'KEYFRAMED_TRANSLATION',
+    'KEYFRAMED_ROTATION',
     'PARTICIPATING_VOLUME'
 );
 
@@ -73,6 +226,12 @@ CREATE TYPE ROTATION_ORDER AS ENUM (
     'ZYZ'
 );
 
+CREATE TYPE DENSITY_TYPE AS ENUM (
+    'CONSTANT',
+    'NOISE',
+    'VOXEL_GRID'
+);
+
 CREATE TABLE primitives (
     primitive_name TEXT PRIMARY KEY,
     primitive_type PRIMITIVE_TYPE NOT NULL,
@@ -83,6 +242,9 @@ CREATE TABLE primitives (
     a_normal DOUBLE PRECISION[3],
     b_normal DOUBLE PRECISION[3],
     c_normal DOUBLE PRECISION[3],
+    a_uv DOUBLE PRECISION[2],
+    b_uv DOUBLE PRECISION[2],
+    c_uv DOUBLE PRECISION[2],
     point DOUBLE PRECISION[3],
     normal DOUBLE PRECISION[3],
     center DOUBLE PRECISION[3],
@@ -96,30 +258,32 @@ CREATE TABLE primitives (
     height DOUBLE PRECISION,
     angle DOUBLE PRECISION,
     density DOUBLE PRECISION,
+    density_type DENSITY_TYPE,
+    noise_frequency DOUBLE PRECISION,
+    noise_octaves INTEGER,
+    noise_coverage DOUBLE PRECISION,
+    noise_seed BIGINT,
+    voxel_resolution INTEGER[3],
+    voxel_data BYTEA,
+    keyframe_moments DOUBLE PRECISION[],
+    keyframe_values DOUBLE PRECISION[],
     is_culled BOOLEAN,
     has_negative_normal BOOLEAN,
     has_inverted_normals BOOLEAN
 );
 
-CREATE TYPE TEXTURE_TYPE AS ENUM (
-    'COLOR',
-    'IMAGE'
-);
-
-CREATE TABLE textures (
-    texture_name TEXT PRIMARY KEY,
-    texture_type TEXTURE_TYPE NOT NULL,
-    color DOUBLE PRECISION[3],
-    gamma DOUBLE PRECISION,
-    magnitude DOUBLE PRECISION,
-    image_data BYTEA
-);
-
 CREATE TYPE MATERIAL_TYPE AS ENUM (
     'LAMBERTIAN', 
     'METAL', 
     'DIELECTRIC',
-    'ISOTROPIC'
+    'ISOTROPIC',
+    'CONDUCTOR',
+    'ROUGH_DIELECTRIC',
+    'PRINCIPLED',
+    'MIX',
+    'LIGHT',
+    'HENYEY_GREENSTEIN',
+    'SUBSURFACE'
 );
 
 CREATE TABLE materials (
@@ -129,7 +293,50 @@ CREATE TABLE materials (
     emittance_texture_name TEXT REFERENCES textures(texture_name),
     fuzziness DOUBLE PRECISION,
     refractive_index DOUBLE PRECISION,
-    CHECK (num_nonnulls(reflectance_texture_name, emittance_texture_name) > 0)
+    roughness_u DOUBLE PRECISION,
+    roughness_v DOUBLE PRECISION,
+    eta DOUBLE PRECISION[3],
+    k DOUBLE PRECISION[3],
+    roughness_texture_name TEXT REFERENCES textures(texture_name),
+    transmittance_color DOUBLE PRECISION[3],
+    transmittance_distance DOUBLE PRECISION,
+    base_color DOUBLE PRECISION[3],
+    base_color_texture_name TEXT REFERENCES textures(texture_name),
+    metallic DOUBLE PRECISION,
+    metallic_texture_name TEXT REFERENCES textures(texture_name),
+    roughness DOUBLE PRECISION,
+    specular DOUBLE PRECISION,
+    specular_texture_name TEXT REFERENCES textures(texture_name),
+    clearcoat DOUBLE PRECISION,
+    clearcoat_texture_name TEXT REFERENCES textures(texture_name),
+    sheen DOUBLE PRECISION,
+    sheen_texture_name TEXT REFERENCES textures(texture_name),
+    transmission DOUBLE PRECISION,
+    transmission_texture_name TEXT REFERENCES textures(texture_name),
+    emission DOUBLE PRECISION[3],
+    first_material_name TEXT REFERENCES materials(material_name),
+    second_material_name TEXT REFERENCES materials(material_name),
+    weight_texture_name TEXT REFERENCES textures(texture_name),
+    power DOUBLE PRECISION,
+    is_two_sided BOOLEAN,
+    spot_angle DOUBLE PRECISION,
+    spot_blend DOUBLE PRECISION,
+    is_visible_to_camera BOOLEAN,
+    normal_texture_name TEXT REFERENCES textures(texture_name),
+    bump_texture_name TEXT REFERENCES textures(texture_name),
+    bump_strength DOUBLE PRECISION,
+    anisotropy DOUBLE PRECISION,
+    absorption_coefficient DOUBLE PRECISION[3],
+    scattering_coefficient DOUBLE PRECISION[3],
+    albedo DOUBLE PRECISION[3],
+    mean_free_path DOUBLE PRECISION[3],
+    CHECK (material_type <> 'LIGHT' OR num_nonnulls(emittance_texture_name, is_two_sided, spot_angle, spot_blend, is_visible_to_camera) = 5),
+    CHECK (material_type <> 'MIX' OR num_nonnulls(first_material_name, second_material_name, weight_texture_name) = 3),
+    CHECK (material_type IN ('CONDUCTOR', 'PRINCIPLED', 'MIX', 'SUBSURFACE') OR num_nonnulls(reflectance_texture_name, emittance_texture_name) > 0),
+    CHECK ((transmittance_color IS NULL) = (transmittance_distance IS NULL)),
+    CHECK (material_type <> 'HENYEY_GREENSTEIN' OR anisotropy IS NOT NULL),
+    CHECK ((absorption_coefficient IS NULL) = (scattering_coefficient IS NULL)),
+    CHECK (material_type <> 'SUBSURFACE' OR num_nonnulls(refractive_index, albedo, mean_free_path, anisotropy) = 4)
 );
 
 CREATE TABLE scene_primitive_materials (
@@ -159,5 +366,12 @@ CREATE TABLE renders (
     round_progress DOUBLE PRECISION NOT NULL,
     start_timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
     end_timestamp TIMESTAMP WITH TIME ZONE,
-    image_data BYTEA
+    image_data BYTEA,
+    heatmap_data BYTEA,
+    region_origin INTEGER[2],
+    region_span INTEGER[2],
+    is_cropped BOOLEAN NOT NULL,
+    base_render_name TEXT REFERENCES renders(render_name),
+    CHECK (num_nonnulls(region_origin, region_span) IN (0, 2)),
+    CHECK (NOT is_cropped OR base_render_name IS NULL)
 );
\ No newline at end of file
@@ -2,6 +2,7 @@ package encoding
 
 import (
 	"bytes"
+	"image"
 	"image/jpeg"
 	"image/png"
 
@@ -21,11 +22,12 @@ func RunWorker(plData *config.PhotolumData, log *logrus.Entry, renderName string
 		if active {
 			log.Debug("encoding new image to render")
 			buffer := new(bytes.Buffer)
+			img := crop(tracingPayload.Image, tracingPayload.Crop)
 			switch tracingPayload.FileType {
 			case filetype.PNG:
-				err = png.Encode(buffer, tracingPayload.Image)
+				err = png.Encode(buffer, img)
 			case filetype.JPEG:
-				err = jpeg.Encode(buffer, tracingPayload.Image, nil)
+				err = jpeg.Encode(buffer, img, nil)
 			}
 			if err != nil {
 				log.WithError(err).Error("error encoding image")
@@ -36,6 +38,18 @@ func RunWorker(plData *config.PhotolumData, log *logrus.Entry, renderName string
 				log.WithError(err).Error("error updating render")
 				renderpersistence.UpdateRenderStatus(plData, log, renderName, renderstatus.Error)
 			}
+			// heatmaps are always lossless, regardless of the render's file type
+			if tracingPayload.Heatmap != nil {
+				heatmapBuffer := new(bytes.Buffer)
+				err = png.Encode(heatmapBuffer, crop(tracingPayload.Heatmap, tracingPayload.Crop))
+				if err != nil {
+					log.WithError(err).Error("error encoding heatmap")
+				}
+				err = renderpersistence.UpdateHeatmapData(plData, log, renderName, heatmapBuffer.Bytes())
+				if err != nil {
+					log.WithError(err).Error("error updating render heatmap")
+				}
+			}
 			log.Debug("image encoding finished")
 		} else {
 			log.Debug("encoder signalled to exit")
@@ -44,3 +58,16 @@ func RunWorker(plData *config.PhotolumData, log *logrus.Entry, renderName string
 	}
 	log.Debug("closing encoding worker")
 }
+
+// crop returns the part of an image within a rectangle, or the whole image if the rectangle is empty
+func crop(img image.Image, rect image.Rectangle) image.Image {
+	if rect.Empty() {
+		return img
+	}
+	if subImager, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return subImager.SubImage(rect)
+	}
+	return img
+}
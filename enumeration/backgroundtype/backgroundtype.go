@@ -0,0 +1,7 @@
+package backgroundtype
+
+type BackgroundType string
+
+var Color BackgroundType = "COLOR"
+var EnvironmentMap BackgroundType = "ENVIRONMENT_MAP"
+var Sky BackgroundType = "SKY"
@@ -0,0 +1,10 @@
+package cameratype
+
+type CameraType string
+
+var Perspective CameraType = "PERSPECTIVE"
+var Orthographic CameraType = "ORTHOGRAPHIC"
+var Equirectangular CameraType = "EQUIRECTANGULAR"
+var FisheyeEquidistant CameraType = "FISHEYE_EQUIDISTANT"
+var FisheyeEquisolid CameraType = "FISHEYE_EQUISOLID"
+var Cylindrical CameraType = "CYLINDRICAL"
@@ -0,0 +1,7 @@
+package densitytype
+
+type DensityType string
+
+var Constant DensityType = "CONSTANT"
+var Noise DensityType = "NOISE"
+var VoxelGrid DensityType = "VOXEL_GRID"
@@ -0,0 +1,9 @@
+package filtertype
+
+type FilterType string
+
+var Box FilterType = "BOX"
+var Tent FilterType = "TENT"
+var Gaussian FilterType = "GAUSSIAN"
+var Mitchell FilterType = "MITCHELL"
+var Lanczos FilterType = "LANCZOS"
@@ -6,3 +6,10 @@ var Lambertian MaterialType = "LAMBERTIAN"
 var Metal MaterialType = "METAL"
 var Dielectric MaterialType = "DIELECTRIC"
 var Isotropic MaterialType = "ISOTROPIC"
+var Conductor MaterialType = "CONDUCTOR"
+var RoughDielectric MaterialType = "ROUGH_DIELECTRIC"
+var Principled MaterialType = "PRINCIPLED"
+var Mix MaterialType = "MIX"
+var Light MaterialType = "LIGHT"
+var HenyeyGreenstein MaterialType = "HENYEY_GREENSTEIN"
+var Subsurface MaterialType = "SUBSURFACE"
@@ -13,4 +13,6 @@ var Box PrimitiveType = "BOX"
 var Translation PrimitiveType = "TRANSLATION"
 var Rotation PrimitiveType = "ROTATION"
 var Quaternion PrimitiveType = "QUATERNION"
+var KeyframedTranslation PrimitiveType = "KEYFRAMED_TRANSLATION"
+var KeyframedRotation PrimitiveType = "KEYFRAMED_ROTATION"
 var ParticipatingVolume PrimitiveType = "PARTICIPATING_VOLUME"
@@ -0,0 +1,6 @@
+package stereolayout
+
+type StereoLayout string
+
+var SideBySide StereoLayout = "SIDE_BY_SIDE"
+var TopBottom StereoLayout = "TOP_BOTTOM"
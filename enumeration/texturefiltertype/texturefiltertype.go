@@ -0,0 +1,6 @@
+package texturefiltertype
+
+type TextureFilterType string
+
+var Nearest TextureFilterType = "NEAREST"
+var Bilinear TextureFilterType = "BILINEAR"
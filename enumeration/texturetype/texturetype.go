@@ -4,3 +4,16 @@ type TextureType string
 
 var Color TextureType = "COLOR"
 var Image TextureType = "IMAGE"
+var Checker TextureType = "CHECKER"
+var LinearGradient TextureType = "LINEAR_GRADIENT"
+var RadialGradient TextureType = "RADIAL_GRADIENT"
+var Noise TextureType = "NOISE"
+var Marble TextureType = "MARBLE"
+var Wood TextureType = "WOOD"
+var Multiply TextureType = "MULTIPLY"
+var Add TextureType = "ADD"
+var Mix TextureType = "MIX"
+var Invert TextureType = "INVERT"
+var HSVAdjust TextureType = "HSV_ADJUST"
+var Remap TextureType = "REMAP"
+var Swizzle TextureType = "SWIZZLE"
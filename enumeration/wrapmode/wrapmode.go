@@ -0,0 +1,7 @@
+package wrapmode
+
+type WrapMode string
+
+var Repeat WrapMode = "REPEAT"
+var Clamp WrapMode = "CLAMP"
+var Mirror WrapMode = "MIRROR"
@@ -8,13 +8,32 @@ import (
 )
 
 type Camera struct {
-	CameraName     string
-	EyeLocation    []float64
-	TargetLocation []float64
-	UpVector       []float64
-	VerticalFOV    float64
-	Aperture       float64
-	FocusDistance  float64
+	CameraName              string
+	EyeLocation             []float64
+	TargetLocation          []float64
+	UpVector                []float64
+	VerticalFOV             *float64
+	Aperture                *float64
+	FocusDistance           float64
+	CameraType              string
+	OrthographicHeight      *float64
+	IsPhysical              bool
+	FocalLength             *float64
+	SensorWidth             *float64
+	FNumber                 *float64
+	ShutterSpeed            *float64
+	ISO                     *float64
+	ApertureBlades          int32
+	ApertureRotation        float64
+	ApertureMaskTextureName *string
+	CatsEye                 float64
+	Tilt                    []float64
+	Shift                   []float64
+	ShutterOpen             float64
+	ShutterClose            float64
+	StereoLayout            *string
+	InterocularDistance     *float64
+	ConvergenceDistance     *float64
 }
 
 var entity = "camera"
@@ -35,8 +54,27 @@ func Save(plData *config.PhotolumData, baseLog *logrus.Entry, camera *Camera) er
 			up_vector,
 			vertical_fov,
 			aperture,
-			focus_distance
-		) VALUES ($1,$2,$3,$4,$5,$6,$7)`,
+			focus_distance,
+			camera_type,
+			orthographic_height,
+			is_physical,
+			focal_length,
+			sensor_width,
+			f_number,
+			shutter_speed,
+			iso,
+			aperture_blades,
+			aperture_rotation,
+			aperture_mask_texture_name,
+			cats_eye,
+			tilt,
+			shift,
+			shutter_open,
+			shutter_close,
+			stereo_layout,
+			interocular_distance,
+			convergence_distance
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26)`,
 		camera.CameraName,
 		camera.EyeLocation,
 		camera.TargetLocation,
@@ -44,6 +82,25 @@ func Save(plData *config.PhotolumData, baseLog *logrus.Entry, camera *Camera) er
 		camera.VerticalFOV,
 		camera.Aperture,
 		camera.FocusDistance,
+		camera.CameraType,
+		camera.OrthographicHeight,
+		camera.IsPhysical,
+		camera.FocalLength,
+		camera.SensorWidth,
+		camera.FNumber,
+		camera.ShutterSpeed,
+		camera.ISO,
+		camera.ApertureBlades,
+		camera.ApertureRotation,
+		camera.ApertureMaskTextureName,
+		camera.CatsEye,
+		camera.Tilt,
+		camera.Shift,
+		camera.ShutterOpen,
+		camera.ShutterClose,
+		camera.StereoLayout,
+		camera.InterocularDistance,
+		camera.ConvergenceDistance,
 	)
 	if err != nil || tag.RowsAffected() != 1 {
 		return err
@@ -70,7 +127,26 @@ func Get(plData *config.PhotolumData, baseLog *logrus.Entry, cameraName string)
 			up_vector,
 			vertical_fov,
 			aperture,
-			focus_distance
+			focus_distance,
+			camera_type,
+			orthographic_height,
+			is_physical,
+			focal_length,
+			sensor_width,
+			f_number,
+			shutter_speed,
+			iso,
+			aperture_blades,
+			aperture_rotation,
+			aperture_mask_texture_name,
+			cats_eye,
+			tilt,
+			shift,
+			shutter_open,
+			shutter_close,
+			stereo_layout,
+			interocular_distance,
+			convergence_distance
 		FROM cameras
 		WHERE camera_name = $1`, cameraName).Scan(
 		&camera.CameraName,
@@ -80,6 +156,25 @@ func Get(plData *config.PhotolumData, baseLog *logrus.Entry, cameraName string)
 		&camera.VerticalFOV,
 		&camera.Aperture,
 		&camera.FocusDistance,
+		&camera.CameraType,
+		&camera.OrthographicHeight,
+		&camera.IsPhysical,
+		&camera.FocalLength,
+		&camera.SensorWidth,
+		&camera.FNumber,
+		&camera.ShutterSpeed,
+		&camera.ISO,
+		&camera.ApertureBlades,
+		&camera.ApertureRotation,
+		&camera.ApertureMaskTextureName,
+		&camera.CatsEye,
+		&camera.Tilt,
+		&camera.Shift,
+		&camera.ShutterOpen,
+		&camera.ShutterClose,
+		&camera.StereoLayout,
+		&camera.InterocularDistance,
+		&camera.ConvergenceDistance,
 	)
 	if err != nil {
 		return nil, err
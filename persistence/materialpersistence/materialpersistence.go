@@ -8,12 +8,49 @@ import (
 )
 
 type Material struct {
-	MaterialName           string
-	MaterialType           string
-	ReflectanceTextureName *string
-	EmittanceTextureName   *string
-	Fuzziness              *float64
-	RefractiveIndex        *float64
+	MaterialName            string
+	MaterialType            string
+	ReflectanceTextureName  *string
+	EmittanceTextureName    *string
+	Fuzziness               *float64
+	RefractiveIndex         *float64
+	RoughnessU              *float64
+	RoughnessV              *float64
+	Eta                     []float64
+	K                       []float64
+	RoughnessTextureName    *string
+	TransmittanceColor      []float64
+	TransmittanceDistance   *float64
+	BaseColor               []float64
+	BaseColorTextureName    *string
+	Metallic                *float64
+	MetallicTextureName     *string
+	Roughness               *float64
+	Specular                *float64
+	SpecularTextureName     *string
+	Clearcoat               *float64
+	ClearcoatTextureName    *string
+	Sheen                   *float64
+	SheenTextureName        *string
+	Transmission            *float64
+	TransmissionTextureName *string
+	Emission                []float64
+	FirstMaterialName       *string
+	SecondMaterialName      *string
+	WeightTextureName       *string
+	Power                   *float64
+	IsTwoSided              *bool
+	SpotAngle               *float64
+	SpotBlend               *float64
+	IsVisibleToCamera       *bool
+	NormalTextureName       *string
+	BumpTextureName         *string
+	BumpStrength            *float64
+	Anisotropy              *float64
+	AbsorptionCoefficient   []float64
+	ScatteringCoefficient   []float64
+	Albedo                  []float64
+	MeanFreePath            []float64
 }
 
 var entity = "material"
@@ -33,14 +70,88 @@ func Save(plData *config.PhotolumData, baseLog *logrus.Entry, material *Material
 			reflectance_texture_name,
 			emittance_texture_name,
 			fuzziness,
-			refractive_index
-		) VALUES ($1,$2,$3,$4,$5,$6)`,
+			refractive_index,
+			roughness_u,
+			roughness_v,
+			eta,
+			k,
+			roughness_texture_name,
+			transmittance_color,
+			transmittance_distance,
+			base_color,
+			base_color_texture_name,
+			metallic,
+			metallic_texture_name,
+			roughness,
+			specular,
+			specular_texture_name,
+			clearcoat,
+			clearcoat_texture_name,
+			sheen,
+			sheen_texture_name,
+			transmission,
+			transmission_texture_name,
+			emission,
+			first_material_name,
+			second_material_name,
+			weight_texture_name,
+			power,
+			is_two_sided,
+			spot_angle,
+			spot_blend,
+			is_visible_to_camera,
+			normal_texture_name,
+			bump_texture_name,
+			bump_strength,
+			anisotropy,
+			absorption_coefficient,
+			scattering_coefficient,
+			albedo,
+			mean_free_path
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36,$37,$38,$39,$40,$41,$42,$43)`,
 		material.MaterialName,
 		material.MaterialType,
 		material.ReflectanceTextureName,
 		material.EmittanceTextureName,
 		material.Fuzziness,
 		material.RefractiveIndex,
+		material.RoughnessU,
+		material.RoughnessV,
+		material.Eta,
+		material.K,
+		material.RoughnessTextureName,
+		material.TransmittanceColor,
+		material.TransmittanceDistance,
+		material.BaseColor,
+		material.BaseColorTextureName,
+		material.Metallic,
+		material.MetallicTextureName,
+		material.Roughness,
+		material.Specular,
+		material.SpecularTextureName,
+		material.Clearcoat,
+		material.ClearcoatTextureName,
+		material.Sheen,
+		material.SheenTextureName,
+		material.Transmission,
+		material.TransmissionTextureName,
+		material.Emission,
+		material.FirstMaterialName,
+		material.SecondMaterialName,
+		material.WeightTextureName,
+		material.Power,
+		material.IsTwoSided,
+		material.SpotAngle,
+		material.SpotBlend,
+		material.IsVisibleToCamera,
+		material.NormalTextureName,
+		material.BumpTextureName,
+		material.BumpStrength,
+		material.Anisotropy,
+		material.AbsorptionCoefficient,
+		material.ScatteringCoefficient,
+		material.Albedo,
+		material.MeanFreePath,
 	)
 	if err != nil || tag.RowsAffected() != 1 {
 		return err
@@ -66,7 +177,44 @@ func Get(plData *config.PhotolumData, baseLog *logrus.Entry, materialName string
 			reflectance_texture_name,
 			emittance_texture_name,
 			fuzziness,
-			refractive_index
+			refractive_index,
+			roughness_u,
+			roughness_v,
+			eta,
+			k,
+			roughness_texture_name,
+			transmittance_color,
+			transmittance_distance,
+			base_color,
+			base_color_texture_name,
+			metallic,
+			metallic_texture_name,
+			roughness,
+			specular,
+			specular_texture_name,
+			clearcoat,
+			clearcoat_texture_name,
+			sheen,
+			sheen_texture_name,
+			transmission,
+			transmission_texture_name,
+			emission,
+			first_material_name,
+			second_material_name,
+			weight_texture_name,
+			power,
+			is_two_sided,
+			spot_angle,
+			spot_blend,
+			is_visible_to_camera,
+			normal_texture_name,
+			bump_texture_name,
+			bump_strength,
+			anisotropy,
+			absorption_coefficient,
+			scattering_coefficient,
+			albedo,
+			mean_free_path
 		FROM materials
 		WHERE material_name = $1`, materialName).Scan(
 		&material.MaterialName,
@@ -75,6 +223,43 @@ func Get(plData *config.PhotolumData, baseLog *logrus.Entry, materialName string
 		&material.EmittanceTextureName,
 		&material.Fuzziness,
 		&material.RefractiveIndex,
+		&material.RoughnessU,
+		&material.RoughnessV,
+		&material.Eta,
+		&material.K,
+		&material.RoughnessTextureName,
+		&material.TransmittanceColor,
+		&material.TransmittanceDistance,
+		&material.BaseColor,
+		&material.BaseColorTextureName,
+		&material.Metallic,
+		&material.MetallicTextureName,
+		&material.Roughness,
+		&material.Specular,
+		&material.SpecularTextureName,
+		&material.Clearcoat,
+		&material.ClearcoatTextureName,
+		&material.Sheen,
+		&material.SheenTextureName,
+		&material.Transmission,
+		&material.TransmissionTextureName,
+		&material.Emission,
+		&material.FirstMaterialName,
+		&material.SecondMaterialName,
+		&material.WeightTextureName,
+		&material.Power,
+		&material.IsTwoSided,
+		&material.SpotAngle,
+		&material.SpotBlend,
+		&material.IsVisibleToCamera,
+		&material.NormalTextureName,
+		&material.BumpTextureName,
+		&material.BumpStrength,
+		&material.Anisotropy,
+		&material.AbsorptionCoefficient,
+		&material.ScatteringCoefficient,
+		&material.Albedo,
+		&material.MeanFreePath,
 	)
 	if err != nil {
 		return nil, err
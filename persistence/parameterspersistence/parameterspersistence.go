@@ -24,6 +24,26 @@ type Parameters struct {
 	BackgroundColor          []float64
 	TMin                     float64
 	TMax                     float64
+	ReconstructionFilter     string
+	FilterRadius             float64
+	UseAdaptiveSampling      bool
+	AdaptiveThreshold        float64
+	AdaptiveMinimumSamples   uint32
+	NoiseTarget              *float64
+	Seed                     *int64
+	BackgroundType           string
+	EnvironmentTextureName   *string
+	EnvironmentRotation      float64
+	EnvironmentIntensity     float64
+	SunDirection             []float64
+	Turbidity                float64
+	GroundAlbedo             []float64
+	SkyIntensity             float64
+	SunIntensity             float64
+	SunAngularDiameter       float64
+	FogDensity               *float64
+	FogAlbedo                []float64
+	FogAnisotropy            *float64
 }
 
 var entity = "parameters"
@@ -53,8 +73,28 @@ func Save(plData *config.PhotolumData, baseLog *logrus.Entry, parameters *Parame
 			background_color_magnitude,
 			background_color,
 			t_min,
-			t_max
-		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16)`,
+			t_max,
+			reconstruction_filter,
+			filter_radius,
+			use_adaptive_sampling,
+			adaptive_threshold,
+			adaptive_minimum_samples,
+			noise_target,
+			seed,
+			background_type,
+			environment_texture_name,
+			environment_rotation,
+			environment_intensity,
+			sun_direction,
+			turbidity,
+			ground_albedo,
+			sky_intensity,
+			sun_intensity,
+			sun_angular_diameter,
+			fog_density,
+			fog_albedo,
+			fog_anisotropy
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36)`,
 		parameters.ParametersName,
 		parameters.ImageWidth,
 		parameters.ImageHeight,
@@ -71,6 +111,26 @@ func Save(plData *config.PhotolumData, baseLog *logrus.Entry, parameters *Parame
 		parameters.BackgroundColor,
 		parameters.TMin,
 		parameters.TMax,
+		parameters.ReconstructionFilter,
+		parameters.FilterRadius,
+		parameters.UseAdaptiveSampling,
+		parameters.AdaptiveThreshold,
+		parameters.AdaptiveMinimumSamples,
+		parameters.NoiseTarget,
+		parameters.Seed,
+		parameters.BackgroundType,
+		parameters.EnvironmentTextureName,
+		parameters.EnvironmentRotation,
+		parameters.EnvironmentIntensity,
+		parameters.SunDirection,
+		parameters.Turbidity,
+		parameters.GroundAlbedo,
+		parameters.SkyIntensity,
+		parameters.SunIntensity,
+		parameters.SunAngularDiameter,
+		parameters.FogDensity,
+		parameters.FogAlbedo,
+		parameters.FogAnisotropy,
 	)
 	if err != nil || tag.RowsAffected() != 1 {
 		return err
@@ -106,7 +166,27 @@ func Get(plData *config.PhotolumData, baseLog *logrus.Entry, parametersName stri
 			background_color_magnitude,
 			background_color,
 			t_min,
-			t_max
+			t_max,
+			reconstruction_filter,
+			filter_radius,
+			use_adaptive_sampling,
+			adaptive_threshold,
+			adaptive_minimum_samples,
+			noise_target,
+			seed,
+			background_type,
+			environment_texture_name,
+			environment_rotation,
+			environment_intensity,
+			sun_direction,
+			turbidity,
+			ground_albedo,
+			sky_intensity,
+			sun_intensity,
+			sun_angular_diameter,
+			fog_density,
+			fog_albedo,
+			fog_anisotropy
 		FROM parameters
 		WHERE parameters_name = $1`, parametersName).Scan(
 		&parameters.ParametersName,
@@ -125,6 +205,26 @@ func Get(plData *config.PhotolumData, baseLog *logrus.Entry, parametersName stri
 		&parameters.BackgroundColor,
 		&parameters.TMin,
 		&parameters.TMax,
+		&parameters.ReconstructionFilter,
+		&parameters.FilterRadius,
+		&parameters.UseAdaptiveSampling,
+		&parameters.AdaptiveThreshold,
+		&parameters.AdaptiveMinimumSamples,
+		&parameters.NoiseTarget,
+		&parameters.Seed,
+		&parameters.BackgroundType,
+		&parameters.EnvironmentTextureName,
+		&parameters.EnvironmentRotation,
+		&parameters.EnvironmentIntensity,
+		&parameters.SunDirection,
+		&parameters.Turbidity,
+		&parameters.GroundAlbedo,
+		&parameters.SkyIntensity,
+		&parameters.SunIntensity,
+		&parameters.SunAngularDiameter,
+		&parameters.FogDensity,
+		&parameters.FogAlbedo,
+		&parameters.FogAnisotropy,
 	)
 	if err != nil {
 		return nil, err
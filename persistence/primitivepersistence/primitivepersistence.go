@@ -33,6 +33,18 @@ type Primitive struct {
 	IsCulled                  *bool
 	HasNegativeNormal         *bool
 	HasInvertedNormals        *bool
+	AUV                       []float64
+	BUV                       []float64
+	CUV                       []float64
+	DensityType               *string
+	NoiseFrequency            *float64
+	NoiseOctaves              *int32
+	NoiseCoverage             *float64
+	NoiseSeed                 *int64
+	VoxelResolution           []int32
+	VoxelData                 []byte
+	KeyframeMoments           []float64
+	KeyframeValues            []float64
 }
 
 var entity = "primitive"
@@ -71,8 +83,20 @@ func Save(plData *config.PhotolumData, baseLog *logrus.Entry, primitive *Primiti
 			density,
 			is_culled,
 			has_negative_normal,
-			has_inverted_normals
-		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25)`,
+			has_inverted_normals,
+			a_uv,
+			b_uv,
+			c_uv,
+			density_type,
+			noise_frequency,
+			noise_octaves,
+			noise_coverage,
+			noise_seed,
+			voxel_resolution,
+			voxel_data,
+			keyframe_moments,
+			keyframe_values
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36,$37)`,
 		primitive.PrimitiveName,
 		primitive.PrimitiveType,
 		primitive.EncapsulatedPrimitiveName,
@@ -98,6 +122,18 @@ func Save(plData *config.PhotolumData, baseLog *logrus.Entry, primitive *Primiti
 		primitive.IsCulled,
 		primitive.HasNegativeNormal,
 		primitive.HasInvertedNormals,
+		primitive.AUV,
+		primitive.BUV,
+		primitive.CUV,
+		primitive.DensityType,
+		primitive.NoiseFrequency,
+		primitive.NoiseOctaves,
+		primitive.NoiseCoverage,
+		primitive.NoiseSeed,
+		primitive.VoxelResolution,
+		primitive.VoxelData,
+		primitive.KeyframeMoments,
+		primitive.KeyframeValues,
 	)
 	if err != nil || tag.RowsAffected() != 1 {
 		return err
@@ -142,7 +178,19 @@ func Get(plData *config.PhotolumData, baseLog *logrus.Entry, primitiveName strin
 			density,
 			is_culled,
 			has_negative_normal,
-			has_inverted_normals
+			has_inverted_normals,
+			a_uv,
+			b_uv,
+			c_uv,
+			density_type,
+			noise_frequency,
+			noise_octaves,
+			noise_coverage,
+			noise_seed,
+			voxel_resolution,
+			voxel_data,
+			keyframe_moments,
+			keyframe_values
 		FROM primitives
 		WHERE primitive_name = $1`, primitiveName).Scan(
 		&primitive.PrimitiveName,
@@ -170,6 +218,18 @@ func Get(plData *config.PhotolumData, baseLog *logrus.Entry, primitiveName strin
 		&primitive.IsCulled,
 		&primitive.HasNegativeNormal,
 		&primitive.HasInvertedNormals,
+		&primitive.AUV,
+		&primitive.BUV,
+		&primitive.CUV,
+		&primitive.DensityType,
+		&primitive.NoiseFrequency,
+		&primitive.NoiseOctaves,
+		&primitive.NoiseCoverage,
+		&primitive.NoiseSeed,
+		&primitive.VoxelResolution,
+		&primitive.VoxelData,
+		&primitive.KeyframeMoments,
+		&primitive.KeyframeValues,
 	)
 	if err != nil {
 		return nil, err
@@ -20,6 +20,11 @@ type Render struct {
 	StartTimestamp  time.Time
 	EndTimestamp    *time.Time
 	ImageData       []byte
+	HeatmapData     []byte
+	RegionOrigin    []int32
+	RegionSpan      []int32
+	IsCropped       bool
+	BaseRenderName  *string
 }
 
 var entity = "render"
@@ -42,8 +47,13 @@ func Save(plData *config.PhotolumData, baseLog *logrus.Entry, render *Render) er
 			round_progress,
 			start_timestamp,
 			end_timestamp,
-			image_data
-		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`,
+			image_data,
+			heatmap_data,
+			region_origin,
+			region_span,
+			is_cropped,
+			base_render_name
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14)`,
 		render.RenderName,
 		render.ParametersName,
 		render.SceneName,
@@ -53,6 +63,11 @@ func Save(plData *config.PhotolumData, baseLog *logrus.Entry, render *Render) er
 		render.StartTimestamp,
 		render.EndTimestamp,
 		render.ImageData,
+		render.HeatmapData,
+		render.RegionOrigin,
+		render.RegionSpan,
+		render.IsCropped,
+		render.BaseRenderName,
 	)
 	if err != nil || tag.RowsAffected() != 1 {
 		return err
@@ -81,7 +96,12 @@ func Get(plData *config.PhotolumData, baseLog *logrus.Entry, renderName string)
 			round_progress,
 			start_timestamp,
 			end_timestamp,
-			image_data
+			image_data,
+			heatmap_data,
+			region_origin,
+			region_span,
+			is_cropped,
+			base_render_name
 		FROM renders
 		WHERE render_name = $1`, renderName).Scan(
 		&render.RenderName,
@@ -93,6 +113,11 @@ func Get(plData *config.PhotolumData, baseLog *logrus.Entry, renderName string)
 		&render.StartTimestamp,
 		&render.EndTimestamp,
 		&render.ImageData,
+		&render.HeatmapData,
+		&render.RegionOrigin,
+		&render.RegionSpan,
+		&render.IsCropped,
+		&render.BaseRenderName,
 	)
 	if err != nil {
 		return nil, err
@@ -121,7 +146,12 @@ func Update(plData *config.PhotolumData, baseLog *logrus.Entry, render *Render)
 			round_progress = $6,
 			start_timestamp = $7,
 			end_timestamps = $8,
-			image_data = $9
+			image_data = $9,
+			heatmap_data = $10,
+			region_origin = $11,
+			region_span = $12,
+			is_cropped = $13,
+			base_render_name = $14
 		WHERE render_name = $1`,
 		render.RenderName,
 		render.ParametersName,
@@ -132,6 +162,11 @@ func Update(plData *config.PhotolumData, baseLog *logrus.Entry, render *Render)
 		render.StartTimestamp,
 		render.EndTimestamp,
 		render.ImageData,
+		render.HeatmapData,
+		render.RegionOrigin,
+		render.RegionSpan,
+		render.IsCropped,
+		render.BaseRenderName,
 	)
 	if err != nil || tag.RowsAffected() != 1 {
 		return err
@@ -285,3 +320,26 @@ func UpdateImageData(plData *config.PhotolumData, baseLog *logrus.Entry, renderN
 	log.Trace("database event completed")
 	return nil
 }
+
+func UpdateHeatmapData(plData *config.PhotolumData, baseLog *logrus.Entry, renderName string, heatmapData []byte) error {
+	event := "update heatmap_data"
+	log := baseLog.WithFields(logrus.Fields{
+		"entity": entity,
+		"event":  event,
+	})
+	log.Trace("database event initiated")
+
+	tag, err := plData.DB.Exec(context.Background(), `
+		UPDATE renders 
+		SET heatmap_data = $2
+		WHERE render_name = $1`,
+		renderName,
+		heatmapData,
+	)
+	if err != nil || tag.RowsAffected() != 1 {
+		return err
+	}
+
+	log.Trace("database event completed")
+	return nil
+}
@@ -8,12 +8,36 @@ import (
 )
 
 type Texture struct {
-	TextureName string
-	TextureType string
-	Color       []float64
-	Gamma       *float64
-	Magnitude   *float64
-	ImageData   []byte
+	TextureName       string
+	TextureType       string
+	Color             []float64
+	Gamma             *float64
+	Magnitude         *float64
+	ImageData         []byte
+	FirstColor        []float64
+	SecondColor       []float64
+	Scale             *float64
+	IsSolid           *bool
+	GradientStart     []float64
+	GradientEnd       []float64
+	Octaves           *int32
+	Seed              *int64
+	Turbulence        *float64
+	FilterType        *string
+	WrapMode          *string
+	UVScale           []float64
+	UVOffset          []float64
+	UVRotation        *float64
+	FirstTextureName  *string
+	SecondTextureName *string
+	MaskTextureName   *string
+	HueShift          *float64
+	SaturationScale   *float64
+	ValueScale        *float64
+	InputRange        []float64
+	OutputRange       []float64
+	IsClamped         *bool
+	Swizzle           *string
 }
 
 var entity = "texture"
@@ -33,14 +57,62 @@ func Save(plData *config.PhotolumData, baseLog *logrus.Entry, texture *Texture)
 			color,
 			gamma,
 			magnitude,
-			image_data
-		) VALUES ($1,$2,$3,$4,$5,$6)`,
+			image_data,
+			first_color,
+			second_color,
+			scale,
+			is_solid,
+			gradient_start,
+			gradient_end,
+			octaves,
+			seed,
+			turbulence,
+			filter_type,
+			wrap_mode,
+			uv_scale,
+			uv_offset,
+			uv_rotation,
+			first_texture_name,
+			second_texture_name,
+			mask_texture_name,
+			hue_shift,
+			saturation_scale,
+			value_scale,
+			input_range,
+			output_range,
+			is_clamped,
+			swizzle
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30)`,
 		texture.TextureName,
 		texture.TextureType,
 		texture.Color,
 		texture.Gamma,
 		texture.Magnitude,
 		texture.ImageData,
+		texture.FirstColor,
+		texture.SecondColor,
+		texture.Scale,
+		texture.IsSolid,
+		texture.GradientStart,
+		texture.GradientEnd,
+		texture.Octaves,
+		texture.Seed,
+		texture.Turbulence,
+		texture.FilterType,
+		texture.WrapMode,
+		texture.UVScale,
+		texture.UVOffset,
+		texture.UVRotation,
+		texture.FirstTextureName,
+		texture.SecondTextureName,
+		texture.MaskTextureName,
+		texture.HueShift,
+		texture.SaturationScale,
+		texture.ValueScale,
+		texture.InputRange,
+		texture.OutputRange,
+		texture.IsClamped,
+		texture.Swizzle,
 	)
 	if err != nil || tag.RowsAffected() != 1 {
 		return err
@@ -66,7 +138,31 @@ func Get(plData *config.PhotolumData, baseLog *logrus.Entry, textureName string)
 			color,
 			gamma,
 			magnitude,
-			image_data
+			image_data,
+			first_color,
+			second_color,
+			scale,
+			is_solid,
+			gradient_start,
+			gradient_end,
+			octaves,
+			seed,
+			turbulence,
+			filter_type,
+			wrap_mode,
+			uv_scale,
+			uv_offset,
+			uv_rotation,
+			first_texture_name,
+			second_texture_name,
+			mask_texture_name,
+			hue_shift,
+			saturation_scale,
+			value_scale,
+			input_range,
+			output_range,
+			is_clamped,
+			swizzle
 		FROM textures
 		WHERE texture_name = $1`, textureName).Scan(
 		&texture.TextureName,
@@ -75,6 +171,30 @@ func Get(plData *config.PhotolumData, baseLog *logrus.Entry, textureName string)
 		&texture.Gamma,
 		&texture.Magnitude,
 		&texture.ImageData,
+		&texture.FirstColor,
+		&texture.SecondColor,
+		&texture.Scale,
+		&texture.IsSolid,
+		&texture.GradientStart,
+		&texture.GradientEnd,
+		&texture.Octaves,
+		&texture.Seed,
+		&texture.Turbulence,
+		&texture.FilterType,
+		&texture.WrapMode,
+		&texture.UVScale,
+		&texture.UVOffset,
+		&texture.UVRotation,
+		&texture.FirstTextureName,
+		&texture.SecondTextureName,
+		&texture.MaskTextureName,
+		&texture.HueShift,
+		&texture.SaturationScale,
+		&texture.ValueScale,
+		&texture.InputRange,
+		&texture.OutputRange,
+		&texture.IsClamped,
+		&texture.Swizzle,
 	)
 	if err != nil {
 		return nil, err
@@ -1,10 +1,17 @@
 package tracingservice
 
 import (
+	"bytes"
 	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
 	"reflect"
+	"time"
 
 	"github.com/paulwrubel/photolum/config"
+	"github.com/paulwrubel/photolum/config/environment"
+	"github.com/paulwrubel/photolum/config/filter"
 	"github.com/paulwrubel/photolum/config/geometry"
 	"github.com/paulwrubel/photolum/config/geometry/primitive"
 	"github.com/paulwrubel/photolum/config/geometry/primitive/box"
@@ -23,13 +30,21 @@ import (
 	"github.com/paulwrubel/photolum/config/shading"
 	"github.com/paulwrubel/photolum/config/shading/material"
 	"github.com/paulwrubel/photolum/config/shading/texture"
+	"github.com/paulwrubel/photolum/constants"
 	"github.com/paulwrubel/photolum/encoding"
 	"github.com/paulwrubel/photolum/enumeration/axis"
+	"github.com/paulwrubel/photolum/enumeration/backgroundtype"
+	"github.com/paulwrubel/photolum/enumeration/cameratype"
+	"github.com/paulwrubel/photolum/enumeration/densitytype"
 	"github.com/paulwrubel/photolum/enumeration/filetype"
+	"github.com/paulwrubel/photolum/enumeration/filtertype"
 	"github.com/paulwrubel/photolum/enumeration/materialtype"
 	"github.com/paulwrubel/photolum/enumeration/primitivetype"
 	"github.com/paulwrubel/photolum/enumeration/renderstatus"
+	"github.com/paulwrubel/photolum/enumeration/stereolayout"
+	"github.com/paulwrubel/photolum/enumeration/texturefiltertype"
 	"github.com/paulwrubel/photolum/enumeration/texturetype"
+	"github.com/paulwrubel/photolum/enumeration/wrapmode"
 	"github.com/paulwrubel/photolum/persistence/camerapersistence"
 	"github.com/paulwrubel/photolum/persistence/materialpersistence"
 	"github.com/paulwrubel/photolum/persistence/parameterspersistence"
@@ -95,7 +110,17 @@ func loadConfigurations(plData *config.PhotolumData, log *logrus.Entry, renderNa
 		return nil, fmt.Errorf("error getting parameters from db: %s", err.Error())
 	}
 	// create Parameters struct
-	parameters := decodeParameters(parametersDB)
+	parameters, err := decodeParameters(parametersDB)
+	if err != nil {
+		renderpersistence.UpdateRenderStatus(plData, log, renderName, renderstatus.Error)
+		return nil, fmt.Errorf("error decoding parameters: %s", err.Error())
+	}
+	// the render may trace only a region of the image, composited onto a previous render
+	err = decodeRegion(plData, log, renderDB, parameters)
+	if err != nil {
+		renderpersistence.UpdateRenderStatus(plData, log, renderName, renderstatus.Error)
+		return nil, fmt.Errorf("error decoding render region: %s", err.Error())
+	}
 
 	// get scene from db
 	sceneDB, err := scenepersistence.Get(plData, log, renderDB.SceneName)
@@ -106,6 +131,14 @@ func loadConfigurations(plData *config.PhotolumData, log *logrus.Entry, renderNa
 	// create and attach scene
 	parameters.Scene = &config.Scene{}
 
+	// decode and attach environment
+	parameters.Scene.Environment, err = decodeEnvironment(plData, log, parametersDB, parameters)
+	if err != nil {
+		renderpersistence.UpdateRenderStatus(plData, log, renderName, renderstatus.Error)
+		return nil, fmt.Errorf("error decoding environment: %s", err.Error())
+	}
+	parameters.Scene.Fog = decodeFog(parametersDB)
+
 	// get camera from db
 	cameraDB, err := camerapersistence.Get(plData, log, sceneDB.CameraName)
 	if err != nil {
@@ -113,7 +146,11 @@ func loadConfigurations(plData *config.PhotolumData, log *logrus.Entry, renderNa
 		return nil, fmt.Errorf("error getting camera from db: %s", err.Error())
 	}
 	// attach camera to scene
-	parameters.Scene.Camera = decodeCamera(cameraDB, parameters)
+	parameters.Scene.Camera, err = decodeCamera(plData, log, cameraDB, parameters)
+	if err != nil {
+		renderpersistence.UpdateRenderStatus(plData, log, renderName, renderstatus.Error)
+		return nil, fmt.Errorf("error decoding camera: %s", err.Error())
+	}
 
 	// get sceneprimitivematerials from db
 	spmListDB, err := sceneprimitivematerialpersistence.GetAllInScene(plData, log, sceneDB.SceneName)
@@ -151,7 +188,7 @@ func loadConfigurations(plData *config.PhotolumData, log *logrus.Entry, renderNa
 			return nil, fmt.Errorf("error getting material from db: %s", err.Error())
 		}
 		// decode material
-		selectedMaterial, err := decodeMaterial(plData, log, materialDB)
+		selectedMaterial, err := decodeMaterial(plData, log, materialDB, map[string]bool{})
 		if err != nil {
 			renderpersistence.UpdateRenderStatus(plData, log, renderName, renderstatus.Error)
 			return nil, fmt.Errorf("error decoding material: %s", err.Error())
@@ -162,27 +199,38 @@ func loadConfigurations(plData *config.PhotolumData, log *logrus.Entry, renderNa
 		// transmission commponent can be reversed
 		// this is an arbitrary restriction that is likely to be removed in the future with the user choosing to self-restrict
 		// themselves in a similar manner
-		if reflect.TypeOf(selectedMaterial) == reflect.TypeOf(&material.Dielectric{}) && !selectedPrimitive.IsClosed() {
+		if isRefractive(selectedMaterial) && !selectedPrimitive.IsClosed() {
 			return nil, fmt.Errorf("cannot attach refractive materials (%s) to non-closed geometry (%s)",
 				spm.MaterialName, spm.PrimitiveName)
 		}
-		if reflect.TypeOf(selectedMaterial) == reflect.TypeOf(&material.Isotropic{}) && !selectedPrimitive.IsClosed() {
+		if isVolumetric(selectedMaterial) && !selectedPrimitive.IsClosed() {
 			return nil, fmt.Errorf("cannot attach volumetric materials (%s) to non-closed geometry (%s)",
 				spm.MaterialName, spm.PrimitiveName)
 		}
 
-		// additionally, isotropics specifically must be attached to participating volumes
-		if reflect.TypeOf(selectedMaterial) == reflect.TypeOf(&material.Isotropic{}) &&
+		// additionally, volumetric materials specifically must be attached to participating volumes
+		if isVolumetric(selectedMaterial) &&
 			reflect.TypeOf(selectedPrimitive) != reflect.TypeOf(&participatingvolume.ParticipatingVolume{}) {
-			return nil, fmt.Errorf("cannot attach isotropic materials (%s) to primitive not of type participating_volume (%s)",
+			return nil, fmt.Errorf("cannot attach volumetric materials (%s) to primitive not of type participating_volume (%s)",
 				spm.MaterialName, spm.PrimitiveName)
 		}
 		// ...and vice versa as well
 		if reflect.TypeOf(selectedPrimitive) == reflect.TypeOf(&participatingvolume.ParticipatingVolume{}) &&
-			reflect.TypeOf(selectedMaterial) != reflect.TypeOf(&material.Isotropic{}) {
-			return nil, fmt.Errorf("cannot attach to participating volume (%s) a material not of type isotropic (%s)",
+			!isVolumetric(selectedMaterial) {
+			return nil, fmt.Errorf("cannot attach to participating volume (%s) a material not of type isotropic or henyey_greenstein (%s)",
 				spm.PrimitiveName, spm.MaterialName)
 		}
+		// light passing through volumes on its way to a point is dimmed by them
+		if medium, ok := selectedPrimitive.(material.Medium); ok {
+			parameters.Scene.Media = append(parameters.Scene.Media, medium)
+		}
+
+		// lights given by their power spread it over the surface they're attached to
+		err = setLightSurfaceAreas(selectedMaterial, selectedPrimitive)
+		if err != nil {
+			return nil, fmt.Errorf("cannot attach light (%s) to primitive (%s): %s",
+				spm.MaterialName, spm.PrimitiveName, err.Error())
+		}
 
 		selectedPrimitive.SetMaterial(selectedMaterial)
 		// added to the cooresponding list based on type
@@ -193,10 +241,15 @@ func loadConfigurations(plData *config.PhotolumData, log *logrus.Entry, renderNa
 		}
 	}
 
+	// the fog fills the space around the bounded objects, while the open sky beyond them is clear
+	if parameters.Scene.Fog != nil && len(boundedSceneObjects.List) > 0 {
+		parameters.Scene.FogBounds, _ = boundedSceneObjects.BoundingBox(parameters.Scene.Camera.ShutterOpen, parameters.Scene.Camera.ShutterClose)
+	}
+
 	// if we are using a BVH ...
 	if parameters.UseBVH {
-		// ... construct it from the bounded objects ..
-		sceneBVH, err := bvh.New(boundedSceneObjects)
+		// ... construct it from the bounded objects, wherever they move while the shutter is open ..
+		sceneBVH, err := bvh.New(boundedSceneObjects, parameters.Scene.Camera.ShutterOpen, parameters.Scene.Camera.ShutterClose)
 		if err != nil {
 			return nil, err
 		}
@@ -221,7 +274,7 @@ func loadConfigurations(plData *config.PhotolumData, log *logrus.Entry, renderNa
 	return parameters, nil
 }
 
-func decodeParameters(parametersDB *parameterspersistence.Parameters) *config.Parameters {
+func decodeParameters(parametersDB *parameterspersistence.Parameters) (*config.Parameters, error) {
 	parameters := &config.Parameters{
 		ImageWidth:               int(parametersDB.ImageWidth),
 		ImageHeight:              int(parametersDB.ImageHeight),
@@ -240,14 +293,139 @@ func decodeParameters(parametersDB *parameterspersistence.Parameters) *config.Pa
 			Green: parametersDB.BackgroundColor[1],
 			Blue:  parametersDB.BackgroundColor[2],
 		},
-		TMin: parametersDB.TMin,
-		TMax: parametersDB.TMax,
+		TMin:                   parametersDB.TMin,
+		TMax:                   parametersDB.TMax,
+		UseAdaptiveSampling:    parametersDB.UseAdaptiveSampling,
+		AdaptiveThreshold:      parametersDB.AdaptiveThreshold,
+		AdaptiveMinimumSamples: int(parametersDB.AdaptiveMinimumSamples),
+	}
+	if parametersDB.NoiseTarget != nil {
+		parameters.NoiseTarget = *parametersDB.NoiseTarget
+	}
+	// without a fixed seed, every render is different
+	if parametersDB.Seed != nil {
+		parameters.Seed = *parametersDB.Seed
+	} else {
+		parameters.Seed = time.Now().UnixNano()
 	}
 	parameters.BackgroundColor = parameters.BackgroundColor.MultScalar(parameters.BackgroundColorMagnitude)
-	return parameters
+
+	reconstructionFilter, err := decodeFilter(parametersDB)
+	if err != nil {
+		return nil, err
+	}
+	parameters.ReconstructionFilter = reconstructionFilter
+	return parameters, nil
+}
+
+func decodeFilter(parametersDB *parameterspersistence.Parameters) (filter.Filter, error) {
+	switch filtertype.FilterType(parametersDB.ReconstructionFilter) {
+	case filtertype.Box:
+		return (&filter.Box{
+			Radius: parametersDB.FilterRadius,
+		}).Setup()
+	case filtertype.Tent:
+		return (&filter.Tent{
+			Radius: parametersDB.FilterRadius,
+		}).Setup()
+	case filtertype.Gaussian:
+		return (&filter.Gaussian{
+			Radius: parametersDB.FilterRadius,
+			Alpha:  constants.ParametersGaussianFilterAlpha,
+		}).Setup()
+	case filtertype.Mitchell:
+		return (&filter.Mitchell{
+			Radius: parametersDB.FilterRadius,
+			B:      constants.ParametersMitchellFilterB,
+			C:      constants.ParametersMitchellFilterC,
+		}).Setup()
+	case filtertype.Lanczos:
+		return (&filter.Lanczos{
+			Radius: parametersDB.FilterRadius,
+		}).Setup()
+	default:
+		return nil, fmt.Errorf("invalid reconstruction filter")
+	}
+}
+
+func decodeEnvironment(plData *config.PhotolumData, log *logrus.Entry, parametersDB *parameterspersistence.Parameters, parameters *config.Parameters) (environment.Environment, error) {
+	switch backgroundtype.BackgroundType(parametersDB.BackgroundType) {
+	case backgroundtype.Color:
+		return &environment.Color{
+			Color: parameters.BackgroundColor,
+		}, nil
+	case backgroundtype.EnvironmentMap:
+		textureDB, err := texturepersistence.Get(plData, log, *parametersDB.EnvironmentTextureName)
+		if err != nil {
+			return nil, err
+		}
+		environmentTexture, err := decodeTexture(plData, log, textureDB)
+		if err != nil {
+			return nil, err
+		}
+		imageTexture, ok := environmentTexture.(*texture.Image)
+		if !ok {
+			return nil, fmt.Errorf("environment texture must be an image texture")
+		}
+		return (&environment.Image{
+			Texture:   imageTexture,
+			Rotation:  parametersDB.EnvironmentRotation,
+			Intensity: parametersDB.EnvironmentIntensity,
+		}).Setup()
+	case backgroundtype.Sky:
+		return (&environment.Sky{
+			SunDirection: geometry.Vector{
+				X: parametersDB.SunDirection[0],
+				Y: parametersDB.SunDirection[1],
+				Z: parametersDB.SunDirection[2],
+			},
+			Turbidity: parametersDB.Turbidity,
+			GroundAlbedo: shading.Color{
+				Red:   parametersDB.GroundAlbedo[0],
+				Green: parametersDB.GroundAlbedo[1],
+				Blue:  parametersDB.GroundAlbedo[2],
+			},
+			SkyIntensity:       parametersDB.SkyIntensity,
+			SunIntensity:       parametersDB.SunIntensity,
+			SunAngularDiameter: parametersDB.SunAngularDiameter,
+		}).Setup()
+	default:
+		return nil, fmt.Errorf("invalid background type")
+	}
 }
 
-func decodeCamera(cameraDB *camerapersistence.Camera, parameters *config.Parameters) *config.Camera {
+// decodeRegion sets the part of the image a render traces, and what fills in the rest of it
+// regions are given with y increasing downwards, as the image is seen, so they're flipped for tracing
+func decodeRegion(plData *config.PhotolumData, log *logrus.Entry, renderDB *renderpersistence.Render, parameters *config.Parameters) error {
+	parameters.Region = image.Rect(0, 0, parameters.ImageWidth, parameters.ImageHeight)
+	if renderDB.RegionOrigin != nil {
+		x, y := int(renderDB.RegionOrigin[0]), int(renderDB.RegionOrigin[1])
+		width, height := int(renderDB.RegionSpan[0]), int(renderDB.RegionSpan[1])
+		parameters.Region = image.Rect(x, parameters.ImageHeight-y-height, x+width, parameters.ImageHeight-y)
+	}
+	parameters.IsCropped = renderDB.IsCropped
+	if renderDB.BaseRenderName != nil {
+		baseRenderDB, err := renderpersistence.Get(plData, log, *renderDB.BaseRenderName)
+		if err != nil {
+			return fmt.Errorf("error getting base render from db: %s", err.Error())
+		}
+		if baseRenderDB.ImageData == nil {
+			return fmt.Errorf("base render %s has no image yet", baseRenderDB.RenderName)
+		}
+		baseImage, _, err := image.Decode(bytes.NewReader(baseRenderDB.ImageData))
+		if err != nil {
+			return fmt.Errorf("error decoding base render image: %s", err.Error())
+		}
+		if baseImage.Bounds().Dx() != parameters.ImageWidth || baseImage.Bounds().Dy() != parameters.ImageHeight {
+			return fmt.Errorf("base render image is %dx%d, but the render is %dx%d",
+				baseImage.Bounds().Dx(), baseImage.Bounds().Dy(), parameters.ImageWidth, parameters.ImageHeight)
+		}
+		parameters.BaseImage = baseImage
+	}
+	return nil
+}
+
+func decodeCamera(plData *config.PhotolumData, log *logrus.Entry, cameraDB *camerapersistence.Camera, parameters *config.Parameters) (*config.Camera, error) {
 	camera := &config.Camera{
 		EyeLocation: geometry.Point{
 			X: cameraDB.EyeLocation[0],
@@ -264,12 +442,54 @@ func decodeCamera(cameraDB *camerapersistence.Camera, parameters *config.Paramet
 			Y: cameraDB.UpVector[1],
 			Z: cameraDB.UpVector[2],
 		},
-		VerticalFOV:   cameraDB.VerticalFOV,
-		Aperture:      cameraDB.Aperture,
-		FocusDistance: cameraDB.FocusDistance,
+		CameraType:       cameratype.CameraType(cameraDB.CameraType),
+		FocusDistance:    cameraDB.FocusDistance,
+		ApertureBlades:   int(cameraDB.ApertureBlades),
+		ApertureRotation: cameraDB.ApertureRotation,
+		CatsEye:          cameraDB.CatsEye,
+		TiltX:            cameraDB.Tilt[0],
+		TiltY:            cameraDB.Tilt[1],
+		ShiftX:           cameraDB.Shift[0],
+		ShiftY:           cameraDB.Shift[1],
+		ShutterOpen:      cameraDB.ShutterOpen,
+		ShutterClose:     cameraDB.ShutterClose,
+	}
+	if cameraDB.Aperture != nil {
+		camera.Aperture = *cameraDB.Aperture
+	}
+	if cameraDB.VerticalFOV != nil {
+		camera.VerticalFOV = *cameraDB.VerticalFOV
+	}
+	if cameraDB.OrthographicHeight != nil {
+		camera.OrthographicHeight = *cameraDB.OrthographicHeight
+	}
+	if cameraDB.IsPhysical {
+		camera.IsPhysical = true
+		camera.FocalLength = *cameraDB.FocalLength
+		camera.SensorWidth = *cameraDB.SensorWidth
+		camera.FNumber = *cameraDB.FNumber
+		camera.ShutterSpeed = *cameraDB.ShutterSpeed
+		camera.ISO = *cameraDB.ISO
+	}
+	if cameraDB.StereoLayout != nil {
+		camera.StereoLayout = stereolayout.StereoLayout(*cameraDB.StereoLayout)
+		camera.InterocularDistance = *cameraDB.InterocularDistance
+		if cameraDB.ConvergenceDistance != nil {
+			camera.ConvergenceDistance = *cameraDB.ConvergenceDistance
+		}
+	}
+	if cameraDB.ApertureMaskTextureName != nil {
+		textureDB, err := texturepersistence.Get(plData, log, *cameraDB.ApertureMaskTextureName)
+		if err != nil {
+			return nil, err
+		}
+		camera.ApertureMask, err = decodeTexture(plData, log, textureDB)
+		if err != nil {
+			return nil, err
+		}
 	}
 	camera.Setup(parameters)
-	return camera
+	return camera, nil
 }
 
 func decodePrimitive(plData *config.PhotolumData, log *logrus.Entry, primitiveDB *primitivepersistence.Primitive) (primitive.Primitive, error) {
@@ -283,8 +503,12 @@ func decodePrimitive(plData *config.PhotolumData, log *logrus.Entry, primitiveDB
 		if err != nil {
 			return nil, err
 		}
+		density, err := decodeDensity(primitiveDB, corePrimitive)
+		if err != nil {
+			return nil, err
+		}
 		newPV, err := (&participatingvolume.ParticipatingVolume{
-			Density:   *primitiveDB.Density,
+			Density:   density,
 			Primitive: corePrimitive,
 		}).Setup()
 		if err != nil {
@@ -442,6 +666,12 @@ func decodePrimitive(plData *config.PhotolumData, log *logrus.Entry, primitiveDB
 				Z: primitiveDB.CNormal[2],
 			}
 		}
+		var aUV, bUV, cUV [2]float64
+		if primitiveDB.AUV != nil && primitiveDB.BUV != nil && primitiveDB.CUV != nil {
+			aUV = [2]float64{primitiveDB.AUV[0], primitiveDB.AUV[1]}
+			bUV = [2]float64{primitiveDB.BUV[0], primitiveDB.BUV[1]}
+			cUV = [2]float64{primitiveDB.CUV[0], primitiveDB.CUV[1]}
+		}
 		newTriangle, err := (&triangle.Triangle{
 			A: geometry.Point{
 				X: primitiveDB.A[0],
@@ -461,6 +691,9 @@ func decodePrimitive(plData *config.PhotolumData, log *logrus.Entry, primitiveDB
 			ANormal:  aNormal,
 			BNormal:  bNormal,
 			CNormal:  cNormal,
+			AUV:      aUV,
+			BUV:      bUV,
+			CUV:      cUV,
 			IsCulled: *primitiveDB.IsCulled,
 		}).Setup()
 		if err != nil {
@@ -537,15 +770,189 @@ func decodePrimitive(plData *config.PhotolumData, log *logrus.Entry, primitiveDB
 			return nil, err
 		}
 		return newQuaternion, nil
+	case primitivetype.KeyframedTranslation:
+		corePrimitiveDB, err := primitivepersistence.Get(plData, log, *primitiveDB.EncapsulatedPrimitiveName)
+		if err != nil {
+			return nil, err
+		}
+		corePrimitive, err := decodePrimitive(plData, log, corePrimitiveDB)
+		if err != nil {
+			return nil, err
+		}
+		// each keyframe's displacement is stored as three consecutive values
+		keyframes := make([]translate.TranslationKeyframe, len(primitiveDB.KeyframeMoments))
+		for i, moment := range primitiveDB.KeyframeMoments {
+			keyframes[i] = translate.TranslationKeyframe{
+				Moment: moment,
+				Displacement: geometry.Vector{
+					X: primitiveDB.KeyframeValues[3*i],
+					Y: primitiveDB.KeyframeValues[3*i+1],
+					Z: primitiveDB.KeyframeValues[3*i+2],
+				},
+			}
+		}
+		newTranslation, err := (&translate.KeyframedTranslation{
+			Keyframes: keyframes,
+			Primitive: corePrimitive,
+		}).Setup()
+		if err != nil {
+			return nil, err
+		}
+		return newTranslation, nil
+	case primitivetype.KeyframedRotation:
+		corePrimitiveDB, err := primitivepersistence.Get(plData, log, *primitiveDB.EncapsulatedPrimitiveName)
+		if err != nil {
+			return nil, err
+		}
+		corePrimitive, err := decodePrimitive(plData, log, corePrimitiveDB)
+		if err != nil {
+			return nil, err
+		}
+		// each keyframe's axis angles are stored as three consecutive values
+		keyframes := make([]rotate.RotationKeyframe, len(primitiveDB.KeyframeMoments))
+		for i, moment := range primitiveDB.KeyframeMoments {
+			keyframes[i] = rotate.RotationKeyframe{
+				Moment:     moment,
+				AxisAngles: primitiveDB.KeyframeValues[3*i : 3*i+3],
+			}
+		}
+		newRotation, err := (&rotate.KeyframedRotation{
+			Keyframes: keyframes,
+			Order:     *primitiveDB.RotationOrder,
+			Primitive: corePrimitive,
+		}).Setup()
+		if err != nil {
+			return nil, err
+		}
+		return newRotation, nil
 	default:
 		return nil, fmt.Errorf("invalid primitive type")
 	}
 }
 
-func decodeMaterial(plData *config.PhotolumData, log *logrus.Entry, materialDB *materialpersistence.Material) (material.Material, error) {
+// decodeDensity decodes how dense a participating volume is throughout the primitive it fills
+func decodeDensity(primitiveDB *primitivepersistence.Primitive, corePrimitive primitive.Primitive) (participatingvolume.DensityField, error) {
+	// volumes saved before density types existed are constant
+	densityType := densitytype.Constant
+	if primitiveDB.DensityType != nil {
+		densityType = densitytype.DensityType(*primitiveDB.DensityType)
+	}
+	switch densityType {
+	case densitytype.Constant:
+		newDensity, err := (&participatingvolume.ConstantDensity{
+			Density: *primitiveDB.Density,
+		}).Setup()
+		if err != nil {
+			return nil, err
+		}
+		return newDensity, nil
+	case densitytype.Noise:
+		newDensity, err := (&participatingvolume.NoiseDensity{
+			Density:   *primitiveDB.Density,
+			Frequency: *primitiveDB.NoiseFrequency,
+			Octaves:   int(*primitiveDB.NoiseOctaves),
+			Coverage:  *primitiveDB.NoiseCoverage,
+			Noise:     texture.NewPerlin(*primitiveDB.NoiseSeed),
+		}).Setup()
+		if err != nil {
+			return nil, err
+		}
+		return newDensity, nil
+	case densitytype.VoxelGrid:
+		values, err := participatingvolume.DecodeVoxels(primitiveDB.VoxelData)
+		if err != nil {
+			return nil, err
+		}
+		// the grid is stretched over the whole of the primitive it fills
+		box, ok := corePrimitive.BoundingBox(0, 0)
+		if !ok {
+			return nil, fmt.Errorf("voxel grids must fill a bounded primitive")
+		}
+		newDensity, err := (&participatingvolume.VoxelDensity{
+			Density: *primitiveDB.Density,
+			Resolution: [3]int{
+				int(primitiveDB.VoxelResolution[0]),
+				int(primitiveDB.VoxelResolution[1]),
+				int(primitiveDB.VoxelResolution[2]),
+			},
+			Values: values,
+			Box:    box,
+		}).Setup()
+		if err != nil {
+			return nil, err
+		}
+		return newDensity, nil
+	default:
+		return nil, fmt.Errorf("invalid density type")
+	}
+}
+
+// isRefractive returns whether light can be transmitted through a material, as opposed to only being reflected off it
+func isRefractive(m material.Material) bool {
+	switch typedMaterial := m.(type) {
+	case *material.Dielectric, *material.RoughDielectric, *material.Subsurface:
+		return true
+	case *material.Principled:
+		return typedMaterial.IsTransmissive()
+	case *material.Mix:
+		return isRefractive(typedMaterial.First) || isRefractive(typedMaterial.Second)
+	default:
+		return false
+	}
+}
+
+// isVolumetric returns whether a material scatters light throughout a volume, rather than at a surface
+func isVolumetric(m material.Material) bool {
+	switch m.(type) {
+	case *material.Isotropic, *material.HenyeyGreenstein:
+		return true
+	default:
+		return false
+	}
+}
+
+// setLightSurfaceAreas tells any lights specified by their power within a material
+// the surface area of the primitive the material is attached to
+func setLightSurfaceAreas(m material.Material, p primitive.Primitive) error {
+	switch typedMaterial := m.(type) {
+	case *material.Light:
+		if typedMaterial.Power == 0.0 {
+			return nil
+		}
+		area, ok := primitive.SurfaceArea(p)
+		if !ok {
+			return fmt.Errorf("light power is given, but the primitive's surface area is unknown")
+		}
+		return typedMaterial.SetSurfaceArea(area)
+	case *material.Mix:
+		err := setLightSurfaceAreas(typedMaterial.First, p)
+		if err != nil {
+			return err
+		}
+		return setLightSurfaceAreas(typedMaterial.Second, p)
+	default:
+		return nil
+	}
+}
+
+// decodeMaterial decodes a material from the database, along with any materials it references
+// ancestors holds the names of the materials which reference this one, to catch reference cycles
+func decodeMaterial(plData *config.PhotolumData, log *logrus.Entry, materialDB *materialpersistence.Material, ancestors map[string]bool) (material.Material, error) {
+	if ancestors[materialDB.MaterialName] {
+		return nil, fmt.Errorf("material %s is part of a reference cycle", materialDB.MaterialName)
+	}
+	ancestors[materialDB.MaterialName] = true
+	defer delete(ancestors, materialDB.MaterialName)
+
+	normalMap, err := decodeNormalMap(plData, log, materialDB)
+	if err != nil {
+		return nil, err
+	}
+
 	switch materialtype.MaterialType(materialDB.MaterialType) {
 	case materialtype.Lambertian:
 		newMaterial := &material.Lambertian{
+			NormalMap:          normalMap,
 			ReflectanceTexture: &texture.Color{Color: shading.ColorBlack},
 			EmittanceTexture:   &texture.Color{Color: shading.ColorBlack},
 		}
@@ -572,6 +979,7 @@ func decodeMaterial(plData *config.PhotolumData, log *logrus.Entry, materialDB *
 		return newMaterial, nil
 	case materialtype.Metal:
 		newMaterial := &material.Metal{
+			NormalMap:          normalMap,
 			ReflectanceTexture: &texture.Color{Color: shading.ColorBlack},
 			EmittanceTexture:   &texture.Color{Color: shading.ColorBlack},
 			Fuzziness:          *materialDB.Fuzziness,
@@ -599,9 +1007,12 @@ func decodeMaterial(plData *config.PhotolumData, log *logrus.Entry, materialDB *
 		return newMaterial, nil
 	case materialtype.Dielectric:
 		newMaterial := &material.Dielectric{
+			NormalMap:          normalMap,
 			ReflectanceTexture: &texture.Color{Color: shading.ColorBlack},
 			EmittanceTexture:   &texture.Color{Color: shading.ColorBlack},
 			RefractiveIndex:    *materialDB.RefractiveIndex,
+			Absorption:         decodeAbsorption(materialDB),
+			Medium:             decodeMedium(materialDB),
 		}
 		if materialDB.ReflectanceTextureName != nil {
 			textureDB, err := texturepersistence.Get(plData, log, *materialDB.ReflectanceTextureName)
@@ -650,12 +1061,399 @@ func decodeMaterial(plData *config.PhotolumData, log *logrus.Entry, materialDB *
 			}
 		}
 		return newMaterial, nil
+	case materialtype.HenyeyGreenstein:
+		newMaterial := &material.HenyeyGreenstein{
+			ReflectanceTexture: &texture.Color{Color: shading.ColorBlack},
+			EmittanceTexture:   &texture.Color{Color: shading.ColorBlack},
+			Anisotropy:         *materialDB.Anisotropy,
+		}
+		if materialDB.ReflectanceTextureName != nil {
+			textureDB, err := texturepersistence.Get(plData, log, *materialDB.ReflectanceTextureName)
+			if err != nil {
+				return nil, err
+			}
+			newMaterial.ReflectanceTexture, err = decodeTexture(plData, log, textureDB)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if materialDB.EmittanceTextureName != nil {
+			textureDB, err := texturepersistence.Get(plData, log, *materialDB.EmittanceTextureName)
+			if err != nil {
+				return nil, err
+			}
+			newMaterial.EmittanceTexture, err = decodeTexture(plData, log, textureDB)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return newMaterial, nil
+	case materialtype.Conductor:
+		// the reflectance texture only tints a conductor, so it defaults to white rather than black
+		newMaterial := &material.Conductor{
+			NormalMap:          normalMap,
+			ReflectanceTexture: &texture.Color{Color: shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0}},
+			EmittanceTexture:   &texture.Color{Color: shading.ColorBlack},
+			RoughnessU:         *materialDB.RoughnessU,
+			RoughnessV:         *materialDB.RoughnessV,
+			Eta: shading.Color{
+				Red:   materialDB.Eta[0],
+				Green: materialDB.Eta[1],
+				Blue:  materialDB.Eta[2],
+			},
+			K: shading.Color{
+				Red:   materialDB.K[0],
+				Green: materialDB.K[1],
+				Blue:  materialDB.K[2],
+			},
+		}
+		if materialDB.ReflectanceTextureName != nil {
+			textureDB, err := texturepersistence.Get(plData, log, *materialDB.ReflectanceTextureName)
+			if err != nil {
+				return nil, err
+			}
+			newMaterial.ReflectanceTexture, err = decodeTexture(plData, log, textureDB)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if materialDB.EmittanceTextureName != nil {
+			textureDB, err := texturepersistence.Get(plData, log, *materialDB.EmittanceTextureName)
+			if err != nil {
+				return nil, err
+			}
+			newMaterial.EmittanceTexture, err = decodeTexture(plData, log, textureDB)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return newMaterial.Setup()
+	case materialtype.RoughDielectric:
+		newMaterial := &material.RoughDielectric{
+			NormalMap:          normalMap,
+			ReflectanceTexture: &texture.Color{Color: shading.ColorBlack},
+			EmittanceTexture:   &texture.Color{Color: shading.ColorBlack},
+			RefractiveIndex:    *materialDB.RefractiveIndex,
+			Absorption:         decodeAbsorption(materialDB),
+			Medium:             decodeMedium(materialDB),
+		}
+		if materialDB.ReflectanceTextureName != nil {
+			textureDB, err := texturepersistence.Get(plData, log, *materialDB.ReflectanceTextureName)
+			if err != nil {
+				return nil, err
+			}
+			newMaterial.ReflectanceTexture, err = decodeTexture(plData, log, textureDB)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if materialDB.EmittanceTextureName != nil {
+			textureDB, err := texturepersistence.Get(plData, log, *materialDB.EmittanceTextureName)
+			if err != nil {
+				return nil, err
+			}
+			newMaterial.EmittanceTexture, err = decodeTexture(plData, log, textureDB)
+			if err != nil {
+				return nil, err
+			}
+		}
+		textureDB, err := texturepersistence.Get(plData, log, *materialDB.RoughnessTextureName)
+		if err != nil {
+			return nil, err
+		}
+		newMaterial.RoughnessTexture, err = decodeTexture(plData, log, textureDB)
+		if err != nil {
+			return nil, err
+		}
+		return newMaterial, nil
+	case materialtype.Subsurface:
+		// the reflectance texture only tints light crossing the surface, so it defaults to white rather than black
+		newMaterial := &material.Subsurface{
+			NormalMap:          normalMap,
+			ReflectanceTexture: &texture.Color{Color: shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0}},
+			EmittanceTexture:   &texture.Color{Color: shading.ColorBlack},
+			RefractiveIndex:    *materialDB.RefractiveIndex,
+			Albedo: shading.Color{
+				Red:   materialDB.Albedo[0],
+				Green: materialDB.Albedo[1],
+				Blue:  materialDB.Albedo[2],
+			},
+			MeanFreePath: shading.Color{
+				Red:   materialDB.MeanFreePath[0],
+				Green: materialDB.MeanFreePath[1],
+				Blue:  materialDB.MeanFreePath[2],
+			},
+			Anisotropy: *materialDB.Anisotropy,
+		}
+		if materialDB.ReflectanceTextureName != nil {
+			textureDB, err := texturepersistence.Get(plData, log, *materialDB.ReflectanceTextureName)
+			if err != nil {
+				return nil, err
+			}
+			newMaterial.ReflectanceTexture, err = decodeTexture(plData, log, textureDB)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if materialDB.EmittanceTextureName != nil {
+			textureDB, err := texturepersistence.Get(plData, log, *materialDB.EmittanceTextureName)
+			if err != nil {
+				return nil, err
+			}
+			newMaterial.EmittanceTexture, err = decodeTexture(plData, log, textureDB)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return newMaterial, nil
+	case materialtype.Principled:
+		newMaterial := &material.Principled{
+			NormalMap:       normalMap,
+			RefractiveIndex: *materialDB.RefractiveIndex,
+		}
+		newMaterial.BaseColorTexture, err = decodeColorTexture(plData, log, materialDB.BaseColor, materialDB.BaseColorTextureName)
+		if err != nil {
+			return nil, err
+		}
+		newMaterial.MetallicTexture, err = decodeScalarTexture(plData, log, materialDB.Metallic, materialDB.MetallicTextureName)
+		if err != nil {
+			return nil, err
+		}
+		newMaterial.RoughnessTexture, err = decodeScalarTexture(plData, log, materialDB.Roughness, materialDB.RoughnessTextureName)
+		if err != nil {
+			return nil, err
+		}
+		newMaterial.SpecularTexture, err = decodeScalarTexture(plData, log, materialDB.Specular, materialDB.SpecularTextureName)
+		if err != nil {
+			return nil, err
+		}
+		newMaterial.ClearcoatTexture, err = decodeScalarTexture(plData, log, materialDB.Clearcoat, materialDB.ClearcoatTextureName)
+		if err != nil {
+			return nil, err
+		}
+		newMaterial.SheenTexture, err = decodeScalarTexture(plData, log, materialDB.Sheen, materialDB.SheenTextureName)
+		if err != nil {
+			return nil, err
+		}
+		newMaterial.TransmissionTexture, err = decodeScalarTexture(plData, log, materialDB.Transmission, materialDB.TransmissionTextureName)
+		if err != nil {
+			return nil, err
+		}
+		newMaterial.EmittanceTexture, err = decodeColorTexture(plData, log, materialDB.Emission, materialDB.EmittanceTextureName)
+		if err != nil {
+			return nil, err
+		}
+		return newMaterial.Setup()
+	case materialtype.Light:
+		newMaterial := &material.Light{
+			IsTwoSided:        *materialDB.IsTwoSided,
+			SpotAngle:         *materialDB.SpotAngle,
+			SpotBlend:         *materialDB.SpotBlend,
+			IsVisibleToCamera: *materialDB.IsVisibleToCamera,
+		}
+		if materialDB.Power != nil {
+			newMaterial.Power = *materialDB.Power
+		}
+		textureDB, err := texturepersistence.Get(plData, log, *materialDB.EmittanceTextureName)
+		if err != nil {
+			return nil, err
+		}
+		newMaterial.EmittanceTexture, err = decodeTexture(plData, log, textureDB)
+		if err != nil {
+			return nil, err
+		}
+		return newMaterial.Setup()
+	case materialtype.Mix:
+		newMaterial := &material.Mix{}
+		mixedMaterials := []*material.Material{&newMaterial.First, &newMaterial.Second}
+		for i, materialName := range []string{*materialDB.FirstMaterialName, *materialDB.SecondMaterialName} {
+			mixedMaterialDB, err := materialpersistence.Get(plData, log, materialName)
+			if err != nil {
+				return nil, err
+			}
+			*mixedMaterials[i], err = decodeMaterial(plData, log, mixedMaterialDB, ancestors)
+			if err != nil {
+				return nil, err
+			}
+			// volumetric materials scatter throughout a volume rather than at a surface, so they can't be mixed with anything
+			if isVolumetric(*mixedMaterials[i]) {
+				return nil, fmt.Errorf("cannot mix volumetric materials (%s)", materialName)
+			}
+		}
+		textureDB, err := texturepersistence.Get(plData, log, *materialDB.WeightTextureName)
+		if err != nil {
+			return nil, err
+		}
+		newMaterial.WeightTexture, err = decodeTexture(plData, log, textureDB)
+		if err != nil {
+			return nil, err
+		}
+		return newMaterial, nil
 	default:
 		return nil, fmt.Errorf("invalid material type")
 	}
 }
 
+// decodeColorTexture returns the named texture, or if there is none, a solid texture of the constant color
+func decodeColorTexture(plData *config.PhotolumData, log *logrus.Entry, constant []float64, textureName *string) (texture.Texture, error) {
+	if textureName != nil {
+		textureDB, err := texturepersistence.Get(plData, log, *textureName)
+		if err != nil {
+			return nil, err
+		}
+		return decodeTexture(plData, log, textureDB)
+	}
+	if constant == nil {
+		return nil, fmt.Errorf("material parameter has neither a constant nor a texture")
+	}
+	return &texture.Color{
+		Color: shading.Color{
+			Red:   constant[0],
+			Green: constant[1],
+			Blue:  constant[2],
+		},
+	}, nil
+}
+
+// decodeScalarTexture returns the named texture, or if there is none, a solid gray texture of the constant value
+func decodeScalarTexture(plData *config.PhotolumData, log *logrus.Entry, constant *float64, textureName *string) (texture.Texture, error) {
+	var gray []float64
+	if constant != nil {
+		gray = []float64{*constant, *constant, *constant}
+	}
+	return decodeColorTexture(plData, log, gray, textureName)
+}
+
+// decodeNormalMap returns the shading normal perturbation of a material, or nil if it has none
+func decodeNormalMap(plData *config.PhotolumData, log *logrus.Entry, materialDB *materialpersistence.Material) (*material.NormalMap, error) {
+	if materialDB.NormalTextureName == nil && materialDB.BumpTextureName == nil {
+		return nil, nil
+	}
+	normalMap := &material.NormalMap{
+		BumpStrength: constants.MaterialDefaultBumpStrength,
+	}
+	if materialDB.NormalTextureName != nil {
+		textureDB, err := texturepersistence.Get(plData, log, *materialDB.NormalTextureName)
+		if err != nil {
+			return nil, err
+		}
+		normalMap.NormalTexture, err = decodeTexture(plData, log, textureDB)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if materialDB.BumpTextureName != nil {
+		textureDB, err := texturepersistence.Get(plData, log, *materialDB.BumpTextureName)
+		if err != nil {
+			return nil, err
+		}
+		normalMap.BumpTexture, err = decodeTexture(plData, log, textureDB)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if materialDB.BumpStrength != nil {
+		normalMap.BumpStrength = *materialDB.BumpStrength
+	}
+	return normalMap, nil
+}
+
+// decodeMedium returns the medium filling the interior of a transmissive material, or nil if it has none
+func decodeMedium(materialDB *materialpersistence.Material) *material.HomogeneousMedium {
+	if materialDB.AbsorptionCoefficient == nil || materialDB.ScatteringCoefficient == nil {
+		return nil
+	}
+	return &material.HomogeneousMedium{
+		AbsorptionCoefficient: shading.Color{
+			Red:   materialDB.AbsorptionCoefficient[0],
+			Green: materialDB.AbsorptionCoefficient[1],
+			Blue:  materialDB.AbsorptionCoefficient[2],
+		},
+		ScatteringCoefficient: shading.Color{
+			Red:   materialDB.ScatteringCoefficient[0],
+			Green: materialDB.ScatteringCoefficient[1],
+			Blue:  materialDB.ScatteringCoefficient[2],
+		},
+		Anisotropy: *materialDB.Anisotropy,
+	}
+}
+
+// decodeFog returns the medium filling the space between a scene's objects, or nil if the air is clear
+// it only fills the bounds of the scene's bounded objects, which are set once the objects are decoded
+// the fog's albedo splits its density between scattering and absorption
+func decodeFog(parametersDB *parameterspersistence.Parameters) *material.HomogeneousMedium {
+	if parametersDB.FogDensity == nil {
+		return nil
+	}
+	density := *parametersDB.FogDensity
+	albedo := shading.Color{
+		Red:   parametersDB.FogAlbedo[0],
+		Green: parametersDB.FogAlbedo[1],
+		Blue:  parametersDB.FogAlbedo[2],
+	}
+	return &material.HomogeneousMedium{
+		AbsorptionCoefficient: shading.Color{
+			Red:   (1.0 - albedo.Red) * density,
+			Green: (1.0 - albedo.Green) * density,
+			Blue:  (1.0 - albedo.Blue) * density,
+		},
+		ScatteringCoefficient: albedo.MultScalar(density),
+		Anisotropy:            *parametersDB.FogAnisotropy,
+	}
+}
+
+// decodeAbsorption returns the interior absorption of a transmissive material, or nil if it has none
+func decodeAbsorption(materialDB *materialpersistence.Material) *material.Absorption {
+	if materialDB.TransmittanceColor == nil || materialDB.TransmittanceDistance == nil {
+		return nil
+	}
+	return &material.Absorption{
+		TransmittanceColor: shading.Color{
+			Red:   materialDB.TransmittanceColor[0],
+			Green: materialDB.TransmittanceColor[1],
+			Blue:  materialDB.TransmittanceColor[2],
+		},
+		TransmittanceDistance: *materialDB.TransmittanceDistance,
+	}
+}
+
+// decodeTexture returns the texture described by textureDB, along with any textures it takes as inputs
 func decodeTexture(plData *config.PhotolumData, log *logrus.Entry, textureDB *texturepersistence.Texture) (texture.Texture, error) {
+	return decodeTextureNode(plData, log, textureDB, map[string]bool{})
+}
+
+// decodeInputTexture returns the named texture, taken as an input by a texture graph node
+func decodeInputTexture(plData *config.PhotolumData, log *logrus.Entry, textureName string, ancestors map[string]bool) (texture.Texture, error) {
+	textureDB, err := texturepersistence.Get(plData, log, textureName)
+	if err != nil {
+		return nil, err
+	}
+	return decodeTextureNode(plData, log, textureDB, ancestors)
+}
+
+// decodeTextureNode returns the texture described by textureDB, with its texture coordinates transformed if they're set to be
+// ancestors holds the names of the textures which take this one as an input, to catch reference cycles
+func decodeTextureNode(plData *config.PhotolumData, log *logrus.Entry, textureDB *texturepersistence.Texture, ancestors map[string]bool) (texture.Texture, error) {
+	if ancestors[textureDB.TextureName] {
+		return nil, fmt.Errorf("texture %s is part of a reference cycle", textureDB.TextureName)
+	}
+	ancestors[textureDB.TextureName] = true
+	defer delete(ancestors, textureDB.TextureName)
+
+	newTexture, err := decodeUntransformedTexture(plData, log, textureDB, ancestors)
+	if err != nil || textureDB.UVScale == nil {
+		return newTexture, err
+	}
+	return (&texture.Transform{
+		Texture:  newTexture,
+		Scale:    [2]float64{textureDB.UVScale[0], textureDB.UVScale[1]},
+		Offset:   [2]float64{textureDB.UVOffset[0], textureDB.UVOffset[1]},
+		Rotation: *textureDB.UVRotation,
+	}).Setup()
+}
+
+// decodeUntransformedTexture returns the texture described by textureDB, looked up by the texture coordinates it's given
+func decodeUntransformedTexture(plData *config.PhotolumData, log *logrus.Entry, textureDB *texturepersistence.Texture, ancestors map[string]bool) (texture.Texture, error) {
 	switch texturetype.TextureType(textureDB.TextureType) {
 	case texturetype.Color:
 		newTexture := &texture.Color{
@@ -671,13 +1469,130 @@ func decodeTexture(plData *config.PhotolumData, log *logrus.Entry, textureDB *te
 			ImageData: textureDB.ImageData,
 			Gamma:     *textureDB.Gamma,
 			Magnitude: *textureDB.Magnitude,
+			Filter:    texturefiltertype.TextureFilterType(*textureDB.FilterType),
+			WrapMode:  wrapmode.WrapMode(*textureDB.WrapMode),
 		}
 		err := newTexture.Load()
 		if err != nil {
 			return nil, err
 		}
 		return newTexture, nil
+	case texturetype.Checker:
+		newTexture := &texture.Checker{
+			FirstColor:  colorFromArray(textureDB.FirstColor),
+			SecondColor: colorFromArray(textureDB.SecondColor),
+			Scale:       *textureDB.Scale,
+			IsSolid:     *textureDB.IsSolid,
+		}
+		return newTexture, nil
+	case texturetype.LinearGradient, texturetype.RadialGradient:
+		newTexture := &texture.Gradient{
+			FirstColor:  colorFromArray(textureDB.FirstColor),
+			SecondColor: colorFromArray(textureDB.SecondColor),
+			Start: geometry.Point{
+				X: textureDB.GradientStart[0],
+				Y: textureDB.GradientStart[1],
+				Z: textureDB.GradientStart[2],
+			},
+			End: geometry.Point{
+				X: textureDB.GradientEnd[0],
+				Y: textureDB.GradientEnd[1],
+				Z: textureDB.GradientEnd[2],
+			},
+			IsRadial: texturetype.TextureType(textureDB.TextureType) == texturetype.RadialGradient,
+			IsSolid:  *textureDB.IsSolid,
+		}
+		return newTexture, nil
+	case texturetype.Noise:
+		newTexture := &texture.Noise{
+			FirstColor:  colorFromArray(textureDB.FirstColor),
+			SecondColor: colorFromArray(textureDB.SecondColor),
+			Scale:       *textureDB.Scale,
+			Octaves:     int(*textureDB.Octaves),
+			Perlin:      texture.NewPerlin(*textureDB.Seed),
+		}
+		return newTexture, nil
+	case texturetype.Marble:
+		newTexture := &texture.Marble{
+			FirstColor:  colorFromArray(textureDB.FirstColor),
+			SecondColor: colorFromArray(textureDB.SecondColor),
+			Scale:       *textureDB.Scale,
+			Octaves:     int(*textureDB.Octaves),
+			Turbulence:  *textureDB.Turbulence,
+			Perlin:      texture.NewPerlin(*textureDB.Seed),
+		}
+		return newTexture, nil
+	case texturetype.Wood:
+		newTexture := &texture.Wood{
+			FirstColor:  colorFromArray(textureDB.FirstColor),
+			SecondColor: colorFromArray(textureDB.SecondColor),
+			Scale:       *textureDB.Scale,
+			Octaves:     int(*textureDB.Octaves),
+			Turbulence:  *textureDB.Turbulence,
+			Perlin:      texture.NewPerlin(*textureDB.Seed),
+		}
+		return newTexture, nil
+	case texturetype.Multiply, texturetype.Add, texturetype.Mix:
+		first, err := decodeInputTexture(plData, log, *textureDB.FirstTextureName, ancestors)
+		if err != nil {
+			return nil, err
+		}
+		second, err := decodeInputTexture(plData, log, *textureDB.SecondTextureName, ancestors)
+		if err != nil {
+			return nil, err
+		}
+		switch texturetype.TextureType(textureDB.TextureType) {
+		case texturetype.Multiply:
+			return &texture.Multiply{First: first, Second: second}, nil
+		case texturetype.Add:
+			return &texture.Add{First: first, Second: second}, nil
+		}
+		mask, err := decodeInputTexture(plData, log, *textureDB.MaskTextureName, ancestors)
+		if err != nil {
+			return nil, err
+		}
+		return &texture.Mix{First: first, Second: second, Mask: mask}, nil
+	case texturetype.Invert, texturetype.HSVAdjust, texturetype.Remap, texturetype.Swizzle:
+		input, err := decodeInputTexture(plData, log, *textureDB.FirstTextureName, ancestors)
+		if err != nil {
+			return nil, err
+		}
+		switch texturetype.TextureType(textureDB.TextureType) {
+		case texturetype.Invert:
+			return &texture.Invert{Texture: input}, nil
+		case texturetype.HSVAdjust:
+			newTexture := &texture.HSVAdjust{
+				Texture:         input,
+				HueShift:        *textureDB.HueShift,
+				SaturationScale: *textureDB.SaturationScale,
+				ValueScale:      *textureDB.ValueScale,
+			}
+			return newTexture, nil
+		case texturetype.Remap:
+			newTexture := &texture.Remap{
+				Texture:   input,
+				InputMin:  textureDB.InputRange[0],
+				InputMax:  textureDB.InputRange[1],
+				OutputMin: textureDB.OutputRange[0],
+				OutputMax: textureDB.OutputRange[1],
+				IsClamped: *textureDB.IsClamped,
+			}
+			return newTexture, nil
+		}
+		return (&texture.Swizzle{
+			Texture:  input,
+			Channels: *textureDB.Swizzle,
+		}).Setup()
 	default:
 		return nil, fmt.Errorf("invalid texture type")
 	}
 }
+
+// colorFromArray converts a color stored as an array of its channels
+func colorFromArray(values []float64) shading.Color {
+	return shading.Color{
+		Red:   values[0],
+		Green: values[1],
+		Blue:  values[2],
+	}
+}
@@ -0,0 +1,177 @@
+package tracing
+
+import (
+	"image"
+	"math"
+	"sync"
+
+	"github.com/paulwrubel/photolum/config"
+	"github.com/paulwrubel/photolum/config/filter"
+	"github.com/paulwrubel/photolum/config/shading"
+)
+
+// film accumulates filtered radiance samples over the course of a render
+// pixel coordinates are in tracing space, meaning y increases upwards
+type film struct {
+	width   int
+	height  int
+	sums    []shading.Color
+	weights []float64
+}
+
+// tileFilm is a tile-local accumulation buffer, padded by the filter's extent
+// so that samples near the edge of a tile can be splatted into neighbouring pixels
+// without any synchronization. It is merged into the film once the tile is done
+type tileFilm struct {
+	f       filter.Filter
+	originX int
+	originY int
+	width   int
+	height  int
+	sums    []shading.Color
+	weights []float64
+}
+
+// tileMerger merges finished tile buffers into the film strictly in tile order,
+// whatever order the tiles actually finish in. Neighbouring tiles overlap in their margins,
+// and floating point addition is not associative, so merging in a fixed order keeps
+// the developed image independent of goroutine scheduling
+type tileMerger struct {
+	f       *film
+	next    int
+	pending map[int]*tileFilm
+	mutex   sync.Mutex
+}
+
+func newFilm(width, height int) *film {
+	return &film{
+		width:   width,
+		height:  height,
+		sums:    make([]shading.Color, width*height),
+		weights: make([]float64, width*height),
+	}
+}
+
+func newTileMerger(f *film) *tileMerger {
+	return &tileMerger{
+		f:       f,
+		pending: map[int]*tileFilm{},
+	}
+}
+
+// submit hands over the buffer of a finished tile
+// tiles which were skipped entirely must still be submitted, with a nil buffer
+func (tm *tileMerger) submit(tileIndex int, tf *tileFilm) {
+	tm.mutex.Lock()
+	defer tm.mutex.Unlock()
+	tm.pending[tileIndex] = tf
+	for {
+		next, ok := tm.pending[tm.next]
+		if !ok {
+			return
+		}
+		if next != nil {
+			tm.f.merge(next)
+		}
+		delete(tm.pending, tm.next)
+		tm.next++
+	}
+}
+
+// newTileFilm creates a tile buffer covering the tile plus a margin of the filter's extent,
+// cut off at the edges of the tile's view, so samples of one eye never land in the other's pixels
+func newTileFilm(f filter.Filter, t config.Tile) *tileFilm {
+	margin := int(math.Ceil(f.Extent()))
+	minX := int(math.Max(t.Origin.X-float64(margin), t.ViewOrigin.X))
+	minY := int(math.Max(t.Origin.Y-float64(margin), t.ViewOrigin.Y))
+	maxX := int(math.Min(t.Origin.X+t.Span.X+float64(margin), t.ViewOrigin.X+t.ViewSpan.X))
+	maxY := int(math.Min(t.Origin.Y+t.Span.Y+float64(margin), t.ViewOrigin.Y+t.ViewSpan.Y))
+	width := maxX - minX
+	height := maxY - minY
+	return &tileFilm{
+		f:       f,
+		originX: minX,
+		originY: minY,
+		width:   width,
+		height:  height,
+		sums:    make([]shading.Color, width*height),
+		weights: make([]float64, width*height),
+	}
+}
+
+// splat adds a sample taken at continuous pixel coordinates (x, y)
+// to every pixel whose center lies within the filter's extent
+func (tf *tileFilm) splat(x, y float64, c shading.Color) {
+	extent := tf.f.Extent()
+	// pixel centers are at half-integer coordinates
+	x0 := int(math.Ceil(x - 0.5 - extent))
+	x1 := int(math.Floor(x - 0.5 + extent))
+	y0 := int(math.Ceil(y - 0.5 - extent))
+	y1 := int(math.Floor(y - 0.5 + extent))
+	for py := y0; py <= y1; py++ {
+		ty := py - tf.originY
+		if ty < 0 || ty >= tf.height {
+			continue
+		}
+		for px := x0; px <= x1; px++ {
+			tx := px - tf.originX
+			if tx < 0 || tx >= tf.width {
+				continue
+			}
+			weight := tf.f.Evaluate(float64(px)+0.5-x, float64(py)+0.5-y)
+			if weight == 0.0 {
+				continue
+			}
+			i := ty*tf.width + tx
+			tf.sums[i] = tf.sums[i].Add(c.MultScalar(weight))
+			tf.weights[i] += weight
+		}
+	}
+}
+
+// merge adds a tile buffer into the film
+// it is not safe for concurrent use, see tileMerger
+func (f *film) merge(tf *tileFilm) {
+	for ty := 0; ty < tf.height; ty++ {
+		y := tf.originY + ty
+		if y < 0 || y >= f.height {
+			continue
+		}
+		for tx := 0; tx < tf.width; tx++ {
+			x := tf.originX + tx
+			if x < 0 || x >= f.width {
+				continue
+			}
+			ti := ty*tf.width + tx
+			if tf.weights[ti] == 0.0 {
+				continue
+			}
+			i := y*f.width + x
+			f.sums[i] = f.sums[i].Add(tf.sums[ti])
+			f.weights[i] += tf.weights[ti]
+		}
+	}
+}
+
+// develop resolves the accumulated samples within the render's region into a displayable image,
+// applying the camera's exposure, truncation and gamma correction
+// the rest of the image is left as it was
+func (f *film) develop(p *config.Parameters, img *image.RGBA64) {
+	exposure := p.Scene.Camera.Exposure()
+	for y := p.Region.Min.Y; y < p.Region.Max.Y; y++ {
+		for x := p.Region.Min.X; x < p.Region.Max.X; x++ {
+			i := y*f.width + x
+			pixelColor := shading.ColorBlack
+			// filters with negative lobes can leave a pixel with no meaningful weight
+			if f.weights[i] > 0.0 {
+				pixelColor = f.sums[i].DivScalar(f.weights[i]).Clamp(0, math.MaxFloat64).MultScalar(exposure)
+			}
+			if p.UseScalingTruncation {
+				pixelColor = pixelColor.ScaleDown(1.0).Pow(1.0 / p.GammaCorrection)
+			} else {
+				pixelColor = pixelColor.Clamp(0, 1).Pow(1.0 / p.GammaCorrection)
+			}
+			img.SetRGBA64(x, f.height-y-1, pixelColor.ToRGBA64())
+		}
+	}
+}
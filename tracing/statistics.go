@@ -0,0 +1,105 @@
+package tracing
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/paulwrubel/photolum/config"
+)
+
+// pixelStatistics tracks the running mean and variance of each pixel's sample luminance,
+// using Welford's online algorithm so that no individual samples need to be stored
+// each pixel is only ever written to by the tile containing it, so no locking is needed
+type pixelStatistics struct {
+	width  int
+	height int
+	counts []int
+	means  []float64
+	m2s    []float64
+}
+
+func newPixelStatistics(width, height int) *pixelStatistics {
+	return &pixelStatistics{
+		width:  width,
+		height: height,
+		counts: make([]int, width*height),
+		means:  make([]float64, width*height),
+		m2s:    make([]float64, width*height),
+	}
+}
+
+// add records a new sample luminance for the pixel at (x, y)
+func (ps *pixelStatistics) add(x, y int, luminance float64) {
+	i := y*ps.width + x
+	ps.counts[i]++
+	delta := luminance - ps.means[i]
+	ps.means[i] += delta / float64(ps.counts[i])
+	ps.m2s[i] += delta * (luminance - ps.means[i])
+}
+
+// relativeError estimates the standard error of the pixel's mean, relative to the mean itself
+func (ps *pixelStatistics) relativeError(x, y int) float64 {
+	i := y*ps.width + x
+	n := float64(ps.counts[i])
+	if n < 2 {
+		return math.Inf(1)
+	}
+	variance := ps.m2s[i] / (n - 1)
+	standardError := math.Sqrt(variance / n)
+	// very dark pixels would otherwise never be considered converged
+	return standardError / math.Max(ps.means[i], 1e-3)
+}
+
+// isConverged returns whether a pixel has enough samples and a low enough error to stop sampling
+func (ps *pixelStatistics) isConverged(p *config.Parameters, x, y int) bool {
+	return ps.counts[y*ps.width+x] >= p.AdaptiveMinimumSamples &&
+		ps.relativeError(x, y) <= p.AdaptiveThreshold
+}
+
+// isTileConverged returns whether every pixel in a tile has converged
+func (ps *pixelStatistics) isTileConverged(p *config.Parameters, t config.Tile) bool {
+	for y := int(t.Origin.Y); y < int(t.Origin.Y+t.Span.Y); y++ {
+		for x := int(t.Origin.X); x < int(t.Origin.X+t.Span.X); x++ {
+			if !ps.isConverged(p, x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// meanRelativeError returns the average estimated relative error across a region of the image
+func (ps *pixelStatistics) meanRelativeError(region image.Rectangle) float64 {
+	total := 0.0
+	for y := region.Min.Y; y < region.Max.Y; y++ {
+		for x := region.Min.X; x < region.Max.X; x++ {
+			total += ps.relativeError(x, y)
+		}
+	}
+	return total / float64(region.Dx()*region.Dy())
+}
+
+// heatmap renders the per-pixel sample counts as an image,
+// from black (fewest samples) through red and yellow to white (most samples)
+func (ps *pixelStatistics) heatmap() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, ps.width, ps.height))
+	maxCount := 1
+	for _, count := range ps.counts {
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+	for y := 0; y < ps.height; y++ {
+		for x := 0; x < ps.width; x++ {
+			heat := 3.0 * float64(ps.counts[y*ps.width+x]) / float64(maxCount)
+			img.SetRGBA(x, ps.height-y-1, color.RGBA{
+				R: uint8(255 * math.Min(heat, 1.0)),
+				G: uint8(255 * math.Min(math.Max(heat-1.0, 0.0), 1.0)),
+				B: uint8(255 * math.Min(math.Max(heat-2.0, 0.0), 1.0)),
+				A: 255,
+			})
+		}
+	}
+	return img
+}
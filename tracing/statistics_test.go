@@ -0,0 +1,32 @@
+package tracing
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPixelStatisticsVariance(t *testing.T) {
+	ps := newPixelStatistics(1, 1)
+	samples := []float64{2.0, 4.0, 4.0, 4.0, 5.0, 5.0, 7.0, 9.0}
+	for _, s := range samples {
+		ps.add(0, 0, s)
+	}
+	if ps.means[0] != 5.0 {
+		t.Errorf("Expected mean 5.0 but got %f\n", ps.means[0])
+	}
+	variance := ps.m2s[0] / float64(ps.counts[0]-1)
+	if math.Abs(variance-32.0/7.0) > 1e-9 {
+		t.Errorf("Expected sample variance %f but got %f\n", 32.0/7.0, variance)
+	}
+}
+
+func TestPixelStatisticsUnsampledNotConverged(t *testing.T) {
+	ps := newPixelStatistics(2, 2)
+	ps.add(0, 0, 1.0)
+	if !math.IsInf(ps.relativeError(0, 0), 1) {
+		t.Errorf("Expected infinite error with a single sample but got %f\n", ps.relativeError(0, 0))
+	}
+	if !math.IsInf(ps.relativeError(1, 1), 1) {
+		t.Errorf("Expected infinite error with no samples but got %f\n", ps.relativeError(1, 1))
+	}
+}
@@ -12,8 +12,11 @@ import (
 	"time"
 
 	"github.com/paulwrubel/photolum/config"
+	"github.com/paulwrubel/photolum/config/environment"
 	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/sampling"
 	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/config/shading/material"
 	"github.com/paulwrubel/photolum/enumeration/renderstatus"
 	"github.com/paulwrubel/photolum/persistence/renderpersistence.go"
 	"github.com/sirupsen/logrus"
@@ -27,9 +30,16 @@ func RunWorker(plData *config.PhotolumData,
 	renderName string,
 	encodingChan chan<- *config.TracingPayload) {
 	log.Debug("running tracing worker")
+	log.Debugf("tracing with seed %d", parameters.Seed)
 
-	// create new image
+	// create new image, and the film which accumulates samples into it
 	img := image.NewRGBA64(image.Rect(0, 0, parameters.ImageWidth, parameters.ImageHeight))
+	// only the region is traced, so the rest of the image is whatever it's composited onto
+	if parameters.BaseImage != nil {
+		draw.Draw(img, img.Bounds(), parameters.BaseImage, parameters.BaseImage.Bounds().Min, draw.Src)
+	}
+	f := newFilm(parameters.ImageWidth, parameters.ImageHeight)
+	stats := newPixelStatistics(parameters.ImageWidth, parameters.ImageHeight)
 
 	tiles := getTiles(parameters, img)
 
@@ -46,8 +56,9 @@ func RunWorker(plData *config.PhotolumData,
 
 	for round := 1; round <= parameters.RoundCount; round++ {
 		log.Debugf("beginning round %d", round)
-		traceRound(parameters, log, img, tiles, round, tileChan)
-		log.Debugf("round %d finished, copying image", round)
+		traceRound(parameters, log, f, stats, tiles, round, tileChan)
+		log.Debugf("round %d finished, developing and copying image", round)
+		f.develop(parameters, img)
 		bounds := img.Bounds()
 		imgCopy := image.NewRGBA64(bounds)
 		draw.Draw(imgCopy, bounds, img, bounds.Min, draw.Src)
@@ -55,6 +66,15 @@ func RunWorker(plData *config.PhotolumData,
 			FileType: parameters.FileType,
 			Image:    imgCopy,
 		}
+		// images are stored with y increasing downwards, so the region is flipped to crop them
+		if parameters.IsCropped {
+			payload.Crop = image.Rect(
+				parameters.Region.Min.X, parameters.ImageHeight-parameters.Region.Max.Y,
+				parameters.Region.Max.X, parameters.ImageHeight-parameters.Region.Min.Y)
+		}
+		if parameters.UseAdaptiveSampling {
+			payload.Heatmap = stats.heatmap()
+		}
 		log.Debugf("image copied, sending to encoder")
 		encodingChan <- payload
 		databaseWaitGroup.Wait()
@@ -62,6 +82,16 @@ func RunWorker(plData *config.PhotolumData,
 		debug.FreeOSMemory()
 		log.Debugf("manual garbage collection completed")
 		roundChan <- true
+
+		// with a noise target, the round count is only an upper bound
+		if parameters.NoiseTarget > 0.0 {
+			meanRelativeError := stats.meanRelativeError(parameters.Region)
+			log.Debugf("round %d mean relative error: %f", round, meanRelativeError)
+			if meanRelativeError <= parameters.NoiseTarget {
+				log.Debugf("noise target %f reached after %d rounds", parameters.NoiseTarget, round)
+				break
+			}
+		}
 	}
 	doneChan <- true
 	close(encodingChan)
@@ -110,7 +140,8 @@ func runProgressWorker(plData *config.PhotolumData,
 
 func traceRound(params *config.Parameters,
 	log *logrus.Entry,
-	img *image.RGBA64,
+	f *film,
+	stats *pixelStatistics,
 	tiles []config.Tile,
 	roundNum int,
 	tileChan chan<- bool) {
@@ -119,26 +150,33 @@ func traceRound(params *config.Parameters,
 	// sem := semaphore.NewWeighted(int64(runtime.NumCPU() * 2))
 	// sem := semaphore.NewWeighted(int64(len(tiles)))
 
+	merger := newTileMerger(f)
 	wg := sync.WaitGroup{}
 	for i, tile := range tiles {
 		// log.Tracef("Loop iter: %d, Goroutine count: %d", i, runtime.NumGoroutine())
-		rng := rand.New(rand.NewSource(time.Now().UnixNano() - int64(i)))
+		// tiles which have fully converged don't need any more samples
+		if params.UseAdaptiveSampling && stats.isTileConverged(params, tile) {
+			merger.submit(i, nil)
+			tileChan <- true
+			continue
+		}
 		wg.Add(1)
 		sem.Acquire(context.Background(), 1)
-		go traceTile(params, log, img, rng, sem, &wg, tile, roundNum, tileChan)
+		go traceTile(params, log, merger, stats, sem, &wg, i, tile, roundNum, tileChan)
 	}
 	// log.Tracef("Loop complete, waiting, Goroutine count: %d", runtime.NumGoroutine())
 	wg.Wait()
 	// log.Tracef("Done waiting, Goroutine count: %d", runtime.NumGoroutine())
 }
 
-// traceTile iterates over the pixels in a tile and writes the received colors to the image
+// traceTile iterates over the pixels in a tile and splats the received samples into the film
 func traceTile(p *config.Parameters,
 	log *logrus.Entry,
-	img *image.RGBA64,
-	rng *rand.Rand,
+	merger *tileMerger,
+	stats *pixelStatistics,
 	sem *semaphore.Weighted,
 	wg *sync.WaitGroup,
+	tileIndex int,
 	t config.Tile,
 	roundNum int,
 	tileChan chan<- bool) {
@@ -146,43 +184,51 @@ func traceTile(p *config.Parameters,
 	defer wg.Done()
 	defer sem.Release(1)
 	//log.Tracef("tracing tile id: %s", t.ID)
+	tf := newTileFilm(p.ReconstructionFilter, t)
+	rng := rand.New(sampling.NewSource(0))
 	for y := t.Origin.Y; y < t.Origin.Y+t.Span.Y; y++ {
 		for x := t.Origin.X; x < t.Origin.X+t.Span.X; x++ {
-			pixelColor := tracePixel(p, int(x), int(y), rng)
-
-			// we need to weight the color value by what round we just finished
-			imgColor := shading.MakeColor(img.RGBA64At(int(x), p.ImageHeight-int(y)-1))
-			weightedColor := imgColor.MultScalar(float64(roundNum - 1)).Add(pixelColor).DivScalar(float64(roundNum))
-			img.SetRGBA64(int(x), p.ImageHeight-int(y)-1, weightedColor.ToRGBA64())
+			// with adaptive sampling, only pixels above the error threshold are sampled further
+			if p.UseAdaptiveSampling && stats.isConverged(p, int(x), int(y)) {
+				continue
+			}
+			// every pixel gets its own random stream for every round, derived only from the render's seed,
+			// so the samples drawn don't depend on tile size, tile order, or goroutine scheduling
+			rng.Seed(sampling.DeriveSeed(p.Seed, roundNum, int(y)*p.ImageWidth+int(x)))
+			tracePixel(p, tf, stats, int(x), int(y), rng)
 		}
 	}
+	// samples near the tile's edge may land in a neighbouring tile's pixels,
+	// so the tile buffer is merged rather than written directly
+	merger.submit(tileIndex, tf)
 	tileChan <- true
 	// dc <- 1
 }
 
-// tracePixel gets the color for a pixel
-func tracePixel(p *config.Parameters, x, y int, rng *rand.Rand) shading.Color {
-	pixelColor := shading.Color{}
+// tracePixel samples the area of a pixel and splats each sample into the tile buffer
+func tracePixel(p *config.Parameters, tf *tileFilm, stats *pixelStatistics, x, y int, rng *rand.Rand) {
 	for s := 0; s < p.SamplesPerRound; s++ {
 		// pick a random spot on the pixel to shoot a ray into
 		// this is purely random, NOT stratified
-		u := (float64(x) + rng.Float64()) / float64(p.ImageWidth)
-		v := (float64(y) + rng.Float64()) / float64(p.ImageHeight)
-
-		ray := p.Scene.Camera.GetRay(u, v, rng)
+		sampleX := float64(x) + rng.Float64()
+		sampleY := float64(y) + rng.Float64()
+		u := sampleX / float64(p.ImageWidth)
+		v := sampleY / float64(p.ImageHeight)
 
-		tempColor := traceRay(p, rng, ray, 0)
-		pixelColor = pixelColor.Add(tempColor)
-	}
-	if p.UseScalingTruncation {
-		return pixelColor.DivScalar(float64(p.SamplesPerRound)).ScaleDown(1.0).Pow(1.0 / p.GammaCorrection)
+		// points the camera sees nothing through, like the corners of a fisheye image, stay black
+		sampleColor := shading.ColorBlack
+		if ray, ok := p.Scene.Camera.GetRay(u, v, rng); ok {
+			sampleColor = traceRay(p, rng, ray, 0, 0.0)
+		}
+		stats.add(x, y, sampleColor.Luminance())
+		tf.splat(sampleX, sampleY, sampleColor)
 	}
-	return pixelColor.DivScalar(float64(p.SamplesPerRound)).Clamp(0, 1).Pow(1.0 / p.GammaCorrection)
-
 }
 
 // traceRay casts in individual ray into the scene
-func traceRay(parameters *config.Parameters, rng *rand.Rand, r geometry.Ray, depth int) shading.Color {
+// scatterPDF is the density with which a diffuse bounce chose the ray, or zero if the ray came from
+// the camera or a specular bounce. It's used to weigh light which may also have been sampled directly
+func traceRay(parameters *config.Parameters, rng *rand.Rand, r geometry.Ray, depth int, scatterPDF float64) shading.Color {
 
 	// if we've gone too deep...
 	if depth > parameters.MaxBounces {
@@ -191,53 +237,241 @@ func traceRay(parameters *config.Parameters, rng *rand.Rand, r geometry.Ray, dep
 	}
 	// check if we've hit something
 	rayHit, hitSomething := parameters.Scene.Objects.Intersection(r, parameters.TMin, parameters.TMax, rng)
+
+	// the space between objects may be filled with fog, which can scatter the light before it reaches what was hit,
+	// or before it leaves the scene. Rays reaching a closed surface from its inside were travelling within an object,
+	// where there's no fog, but the back of an open surface can be reached through it
+	fogWeight := shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0}
+	tEnd := parameters.TMax
+	if hitSomething {
+		tEnd = rayHit.Time
+	}
+	if !hitSomething || rayHit.Medium != nil || !rayHit.IsClosed || r.Direction.Dot(rayHit.NormalAtHit) < 0.0 {
+		if tEnter, tLeave, inFog := fogSpan(parameters, r, 0.0, tEnd); inFog {
+			fogRay := geometry.Ray{
+				Origin:    r.PointAt(tEnter),
+				Direction: r.Direction,
+				Moment:    r.Moment,
+			}
+			scatteredRay, weight, scattered := parameters.Scene.Fog.Sample(fogRay, tLeave-tEnter, rng)
+			if scattered {
+				return weight.MultColor(scatterInFog(parameters, rng, r, scatteredRay, depth))
+			}
+			fogWeight = weight
+		}
+	}
+
 	// if we did not hit something...
 	if !hitSomething {
-		// ...return the background color
-		// TODO: add support for HDR skymaps
-		return parameters.BackgroundColor
+		// ...return the light arriving from the environment
+		return fogWeight.MultColor(environmentRadiance(parameters, r.Direction, scatterPDF))
+	}
+	return fogWeight.MultColor(shadeHit(parameters, rng, r, rayHit, depth, scatterPDF))
+}
+
+// fogSpan returns the times between tMin and tMax during which a ray travels through the scene's fog
+func fogSpan(parameters *config.Parameters, r geometry.Ray, tMin, tMax float64) (float64, float64, bool) {
+	if parameters.Scene.Fog == nil || parameters.Scene.FogBounds == nil {
+		return 0.0, 0.0, false
 	}
+	tEnter, tLeave, ok := parameters.Scene.FogBounds.Span(r)
+	tEnter = math.Max(tEnter, tMin)
+	tLeave = math.Min(tLeave, tMax)
+	return tEnter, tLeave, ok && tLeave > tEnter
+}
 
+// shadeHit returns the light leaving the point a ray hit, back along the ray
+func shadeHit(parameters *config.Parameters, rng *rand.Rand, r geometry.Ray, rayHit *material.RayHit, depth int, scatterPDF float64) shading.Color {
 	mat := rayHit.Material
+	// mixed materials behave entirely as whichever of their materials is chosen at this hit
+	if mix, ok := mat.(*material.Mix); ok {
+		mat = mix.Choose(rayHit.U, rayHit.V, rayHit.Point(), rng)
+		rayHit.Material = mat
+	}
+
+	// lights only emit, and may be hidden from the camera while still lighting everything else
+	if light, ok := mat.(*material.Light); ok {
+		if depth == 0 && !light.IsVisibleToCamera {
+			passingRay := geometry.Ray{
+				Origin:    r.PointAt(rayHit.Time),
+				Direction: r.Direction,
+				Moment:    r.Moment,
+			}
+			return traceRay(parameters, rng, passingRay, depth, scatterPDF)
+		}
+		return light.EmittanceToward(*rayHit)
+	}
+
+	// if we hit the surface from inside, the ray travelled through the material's interior,
+	// where a medium may have scattered the light before it reached the surface...
+	transmittance := shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0}
+	if scatterer, ok := mat.(material.InteriorScatterer); ok && r.Direction.Dot(rayHit.NormalAtHit) > 0.0 {
+		scatteredRay, weight, scattered := scatterer.ScatterInterior(r, rayHit.Time, rng)
+		if scattered {
+			// light sources can't be seen through the surrounding surface, so they aren't sampled directly
+			return weight.MultColor(traceRay(parameters, rng, scatteredRay, depth+1, 0.0))
+		}
+		transmittance = weight
+	}
+	// ...and whatever light we find here is partly absorbed on its way back
+	if absorber, ok := mat.(material.Absorber); ok && r.Direction.Dot(rayHit.NormalAtHit) > 0.0 {
+		transmittance = transmittance.MultColor(absorber.Transmittance(rayHit.Time * r.Direction.Magnitude()))
+	}
+
+	// the surface may be shaded with a normal perturbed by a normal or bump map
+	if mapper, ok := mat.(material.NormalMapper); ok {
+		rayHit.NormalAtHit = mapper.ShadingNormal(*rayHit)
+	}
 
 	// if the surface is BLACK, it's not going to let any incoming light contribute to the outgoing color
 	// so we can safely say no light is reflected and simply return the emittance of the material
-	if mat.Reflectance(rayHit.U, rayHit.V) == shading.ColorBlack {
-		return mat.Emittance(rayHit.U, rayHit.V)
+	if mat.Reflectance(rayHit.U, rayHit.V, rayHit.Point()) == shading.ColorBlack {
+		return transmittance.MultColor(mat.Emittance(rayHit.U, rayHit.V, rayHit.Point()))
 	}
 
 	// get the reflection incoming ray
-	scatteredRay, wasScattered := rayHit.Material.Scatter(*rayHit, rng)
+	scatteredRay, attenuation, wasScattered := mat.Scatter(*rayHit, rng)
 	// if no ray could have reflected to us, we just return BLACK
 	if !wasScattered {
 		return shading.ColorBlack
 	}
+	outgoingColor := mat.Emittance(rayHit.U, rayHit.V, rayHit.Point())
+	nextScatterPDF := 0.0
+	// diffuse surfaces also look for light from the environment directly
+	if evaluator, ok := mat.(material.Evaluator); ok {
+		outgoingColor = outgoingColor.Add(sampleEnvironment(parameters, rng, *rayHit, evaluator))
+		_, nextScatterPDF = evaluator.Evaluate(*rayHit, scatteredRay.Direction)
+	}
 	// get the color that came to this point and gave us the outgoing ray
-	incomingColor := traceRay(parameters, rng, scatteredRay, depth+1)
+	incomingColor := traceRay(parameters, rng, scatteredRay, depth+1, nextScatterPDF)
 	// return the (very-roughly approximated) value of the rendering equation
-	return mat.Emittance(rayHit.U, rayHit.V).Add(mat.Reflectance(rayHit.U, rayHit.V).MultColor(incomingColor))
+	return transmittance.MultColor(outgoingColor.Add(attenuation.MultColor(incomingColor)))
+}
+
+// scatterInFog continues a path whose light was scattered by the scene's fog, where scatteredRay starts
+func scatterInFog(parameters *config.Parameters, rng *rand.Rand, r, scatteredRay geometry.Ray, depth int) shading.Color {
+	phase := parameters.Scene.Fog.Phase()
+	fogHit := material.RayHit{
+		Ray: geometry.Ray{
+			Origin:    scatteredRay.Origin,
+			Direction: r.Direction,
+			Moment:    r.Moment,
+		},
+		Material: phase,
+	}
+	directColor := sampleEnvironment(parameters, rng, fogHit, phase)
+	_, nextScatterPDF := phase.Evaluate(fogHit, scatteredRay.Direction)
+	return directColor.Add(traceRay(parameters, rng, scatteredRay, depth+1, nextScatterPDF))
+}
+
+// environmentRadiance returns the light arriving from the environment along direction
+// if the environment could also have been sampled directly, the light is weighed against that strategy
+func environmentRadiance(parameters *config.Parameters, direction geometry.Vector, scatterPDF float64) shading.Color {
+	radiance := parameters.Scene.Environment.Radiance(direction)
+	sampler, ok := parameters.Scene.Environment.(environment.Sampler)
+	if !ok || scatterPDF == 0.0 {
+		return radiance
+	}
+	return radiance.MultScalar(powerHeuristic(scatterPDF, sampler.PDF(direction)))
+}
+
+// sampleEnvironment estimates the light arriving at a diffuse surface directly from the environment
+func sampleEnvironment(parameters *config.Parameters, rng *rand.Rand, rayHit material.RayHit, evaluator material.Evaluator) shading.Color {
+	sampler, ok := parameters.Scene.Environment.(environment.Sampler)
+	if !ok {
+		return shading.ColorBlack
+	}
+	direction, lightPDF := sampler.Sample(rng)
+	if lightPDF == 0.0 {
+		return shading.ColorBlack
+	}
+	bsdf, scatterPDF := evaluator.Evaluate(rayHit, direction)
+	if bsdf == shading.ColorBlack {
+		return shading.ColorBlack
+	}
+	// the environment is only visible if nothing is in the way
+	shadowRay := geometry.Ray{
+		Origin:    rayHit.Ray.PointAt(rayHit.Time),
+		Direction: direction,
+		Moment:    rayHit.Ray.Moment,
+	}
+	visibleFraction := visibility(parameters, rng, shadowRay)
+	if visibleFraction == shading.ColorBlack {
+		return shading.ColorBlack
+	}
+	weight := powerHeuristic(lightPDF, scatterPDF)
+	return parameters.Scene.Environment.Radiance(direction).MultColor(bsdf).MultColor(visibleFraction).MultScalar(weight / lightPDF)
+}
+
+// visibility returns the fraction of each channel of light travelling along ray which reaches its origin
+// surfaces block it completely, while volumes and fog only dim it: the volumes' collisions are passed by,
+// and instead the fraction each lets through is estimated along the whole ray
+func visibility(parameters *config.Parameters, rng *rand.Rand, ray geometry.Ray) shading.Color {
+	transmittance := 1.0
+	for _, medium := range parameters.Scene.Media {
+		transmittance *= medium.Transmittance(ray, parameters.TMin, parameters.TMax, rng)
+		if transmittance == 0.0 {
+			return shading.ColorBlack
+		}
+	}
+	tMin := parameters.TMin
+	for {
+		rayHit, wasHit := parameters.Scene.Objects.Intersection(ray, tMin, parameters.TMax, rng)
+		if !wasHit {
+			break
+		}
+		if rayHit.Medium == nil {
+			return shading.ColorBlack
+		}
+		tMin = rayHit.Time
+	}
+	// the light crossed the fog on its way out of the scene
+	visibleFraction := shading.Color{Red: transmittance, Green: transmittance, Blue: transmittance}
+	if tEnter, tLeave, inFog := fogSpan(parameters, ray, parameters.TMin, parameters.TMax); inFog {
+		distance := (tLeave - tEnter) * ray.Direction.Magnitude()
+		visibleFraction = visibleFraction.MultColor(parameters.Scene.Fog.Transmittance(distance))
+	}
+	return visibleFraction
+}
+
+// powerHeuristic returns the multiple importance sampling weight of a sample
+// chosen with density pdf, when it could also have been chosen with density otherPDF
+func powerHeuristic(pdf, otherPDF float64) float64 {
+	return (pdf * pdf) / (pdf*pdf + otherPDF*otherPDF)
 }
 
-// getTiles creates and return a grid of tiles on the image
+// getTiles creates and return a grid of tiles over the part of each of the camera's views within the render's region
+// no tile straddles two views, so each tile traces a single eye of a stereo camera
 func getTiles(p *config.Parameters, i *image.RGBA64) []config.Tile {
 	tiles := []config.Tile{}
 	idNum := 0
-	for y := 0; y < p.ImageHeight; y += p.TileHeight {
-		for x := 0; x < p.ImageWidth; x += p.TileWidth {
-			idNum++
-			width := math.Min(float64(p.TileWidth), float64(p.ImageWidth-x))
-			height := math.Min(float64(p.TileHeight), float64(p.ImageHeight-y))
-			tiles = append(tiles, config.Tile{
-				ID: strconv.Itoa(idNum),
-				Origin: geometry.Point{
-					X: float64(x),
-					Y: float64(y),
-				},
-				Span: geometry.Vector{
-					X: width,
-					Y: height,
-				},
-			})
+	for _, view := range p.Scene.Camera.Views() {
+		traced := view.Intersect(p.Region)
+		for y := traced.Min.Y; y < traced.Max.Y; y += p.TileHeight {
+			for x := traced.Min.X; x < traced.Max.X; x += p.TileWidth {
+				idNum++
+				width := math.Min(float64(p.TileWidth), float64(traced.Max.X-x))
+				height := math.Min(float64(p.TileHeight), float64(traced.Max.Y-y))
+				tiles = append(tiles, config.Tile{
+					ID: strconv.Itoa(idNum),
+					Origin: geometry.Point{
+						X: float64(x),
+						Y: float64(y),
+					},
+					Span: geometry.Vector{
+						X: width,
+						Y: height,
+					},
+					ViewOrigin: geometry.Point{
+						X: float64(view.Min.X),
+						Y: float64(view.Min.Y),
+					},
+					ViewSpan: geometry.Vector{
+						X: float64(view.Dx()),
+						Y: float64(view.Dy()),
+					},
+				})
+			}
 		}
 	}
 	return tiles
@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"image"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/paulwrubel/photolum/config"
+	"github.com/paulwrubel/photolum/config/geometry"
+	"github.com/paulwrubel/photolum/config/geometry/primitive"
+	"github.com/paulwrubel/photolum/config/geometry/primitive/aabb"
+	"github.com/paulwrubel/photolum/config/geometry/primitive/primitivelist"
+	"github.com/paulwrubel/photolum/config/geometry/primitive/sphere"
+	"github.com/paulwrubel/photolum/config/shading"
+	"github.com/paulwrubel/photolum/config/shading/material"
+	"github.com/paulwrubel/photolum/enumeration/stereolayout"
+)
+
+func TestGetTilesRegion(t *testing.T) {
+	p := &config.Parameters{
+		ImageWidth:  100,
+		ImageHeight: 50,
+		TileWidth:   16,
+		TileHeight:  16,
+		Region:      image.Rect(40, 10, 70, 30),
+		Scene: &config.Scene{
+			Camera: &config.Camera{
+				TargetLocation: geometry.Point{Z: -1.0},
+				UpVector:       geometry.Vector{Y: 1.0},
+				StereoLayout:   stereolayout.SideBySide,
+			},
+		},
+	}
+	p.Scene.Camera.Setup(p)
+	traced := 0
+	for _, tile := range getTiles(p, nil) {
+		bounds := image.Rect(int(tile.Origin.X), int(tile.Origin.Y), int(tile.Origin.X+tile.Span.X), int(tile.Origin.Y+tile.Span.Y))
+		view := image.Rect(int(tile.ViewOrigin.X), int(tile.ViewOrigin.Y), int(tile.ViewOrigin.X+tile.ViewSpan.X), int(tile.ViewOrigin.Y+tile.ViewSpan.Y))
+		if !bounds.In(p.Region) {
+			t.Errorf("Expected tile %v within the region %v\n", bounds, p.Region)
+		}
+		// the region straddles both eyes' views, but no tile should
+		if !bounds.In(view) || view.Dx() != 50 {
+			t.Errorf("Expected tile %v within a single eye's view but it's in %v\n", bounds, view)
+		}
+		traced += bounds.Dx() * bounds.Dy()
+	}
+	if traced != p.Region.Dx()*p.Region.Dy() {
+		t.Errorf("Expected tiles to cover %d pixels but they covered %d\n", p.Region.Dx()*p.Region.Dy(), traced)
+	}
+}
+
+func TestVisibilityThroughFog(t *testing.T) {
+	blocker, err := (&sphere.Sphere{Center: geometry.Point{Z: -3.0}, Radius: 1.0}).Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &config.Parameters{
+		TMin: 0.0001,
+		TMax: math.MaxFloat64,
+		Scene: &config.Scene{
+			Objects: &primitivelist.PrimitiveList{List: []primitive.Primitive{blocker}},
+			Fog: &material.HomogeneousMedium{
+				AbsorptionCoefficient: shading.Color{Red: 0.1},
+			},
+			FogBounds: &aabb.AABB{
+				A: geometry.Point{X: -5.0, Y: -5.0, Z: -5.0},
+				B: geometry.Point{X: 5.0, Y: 5.0, Z: 5.0},
+			},
+		},
+	}
+	rng := rand.New(rand.NewSource(0))
+	tests := []struct {
+		name     string
+		ray      geometry.Ray
+		expected shading.Color
+	}{
+		{
+			name:     "escaping through the fog",
+			ray:      geometry.Ray{Direction: geometry.Vector{X: 2.0}},
+			expected: shading.Color{Red: math.Exp(-0.5), Green: 1.0, Blue: 1.0},
+		},
+		{
+			name:     "outside the fog",
+			ray:      geometry.Ray{Origin: geometry.Point{X: 10.0}, Direction: geometry.Vector{X: 1.0}},
+			expected: shading.Color{Red: 1.0, Green: 1.0, Blue: 1.0},
+		},
+		{
+			name:     "blocked by a surface",
+			ray:      geometry.Ray{Direction: geometry.Vector{Z: -1.0}},
+			expected: shading.ColorBlack,
+		},
+	}
+	for _, test := range tests {
+		visibleFraction := visibility(p, rng, test.ray)
+		if math.Abs(visibleFraction.Red-test.expected.Red) > 1e-4 ||
+			math.Abs(visibleFraction.Green-test.expected.Green) > 1e-4 ||
+			math.Abs(visibleFraction.Blue-test.expected.Blue) > 1e-4 {
+			t.Errorf("Expected %s to let %v through but it let %v through\n", test.name, test.expected, visibleFraction)
+		}
+	}
+}